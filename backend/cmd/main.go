@@ -1,8 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Embed the IANA time zone database so event time zones resolve on minimal images
+	_ "time/tzdata"
 
 	"galactavista/internal/handlers"
 	"galactavista/internal/middleware"
@@ -15,6 +23,9 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// shutdownTimeout is how long in-flight requests get to finish on shutdown
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -32,10 +43,34 @@ func main() {
 
 	// Auto-migrate database models
 	if err := db.AutoMigrate(
+		&models.Brokerage{},
 		&models.User{},
+		&models.Development{},
 		&models.Property{},
+		&models.Attribute{},
+		&models.PropertyAttribute{},
+		&models.PropertyRevision{},
+		&models.DuplicateCandidate{},
+		&models.ModerationItem{},
+		&models.ListingEvent{},
+		&models.ReportSchedule{},
 		&models.VRTour{},
 		&models.MediaFile{},
+		&models.ListingImport{},
+		&models.MLSFeed{},
+		&models.SyndicationPartner{},
+		&models.OpenHouse{},
+		&models.OpenHouseRSVP{},
+		&models.AgentAvailability{},
+		&models.AvailabilityBlackout{},
+		&models.Showing{},
+		&models.Lead{},
+		&models.LeadNote{},
+		&models.Conversation{},
+		&models.Message{},
+		&models.Offer{},
+		&models.Transaction{},
+		&models.TransactionMilestone{},
 	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
@@ -45,19 +80,121 @@ func main() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Initialize notification and email delivery
+	notifier := services.NewLogNotifier()
+	mailer := services.NewLogMailer()
+
+	// Initialize listing moderation rules
+	listingRules, err := services.NewListingRules(cfg.ListingModerationRules, cfg.ListingBannedTerms)
+	if err != nil {
+		log.Fatal("Invalid listing moderation rules:", err)
+	}
+
 	// Initialize services
 	authService := services.NewAuthService(db, cfg.JWTSecret)
-	propertyService := services.NewPropertyService(db)
+	propertyService := services.NewPropertyService(db, listingRules...)
+	attributeService := services.NewAttributeService(db)
+	revisionService := services.NewRevisionService(db, propertyService)
 	mediaService := services.NewMediaService(db)
+	trashService := services.NewTrashService(db, propertyService, cfg.TrashRetention)
+	openHouseService := services.NewOpenHouseService(db, notifier, cfg.PublicBaseURL)
+	showingService := services.NewShowingService(db, notifier, cfg.PublicBaseURL)
+	brokerageService := services.NewBrokerageService(db)
+	leadService := services.NewLeadService(db, notifier)
+	messageService := services.NewMessageService(db, mediaService, services.NewMessageHub(),
+		services.NewBlockedTermsModerator(cfg.MessageBlockedTerms))
+	offerService := services.NewOfferService(db, notifier)
+	transactionService := services.NewTransactionService(db, notifier)
+	developmentService := services.NewDevelopmentService(db, propertyService)
+	duplicateService := services.NewDuplicateService(db, propertyService)
+	moderationService := services.NewModerationService(db, notifier)
+	complianceService := services.NewComplianceService(db, propertyService)
+	comparisonService := services.NewComparisonService(db)
+	mortgageService := services.NewMortgageService(db, propertyService)
+	valuationService := services.NewValuationService(db, models.ValuationAdjustments{
+		PerSquareFoot:       cfg.ValuationPerSquareFoot,
+		PerBedroom:          cfg.ValuationPerBedroom,
+		PerBathroom:         cfg.ValuationPerBathroom,
+		PerYearBuilt:        cfg.ValuationPerYearBuilt,
+		PerLotAcre:          cfg.ValuationPerLotAcre,
+		MonthlyAppreciation: cfg.ValuationMonthlyAppreciation,
+	})
+	importService := services.NewImportService(db, propertyService, cfg.ImportMaxXLSXPartSize)
+	exportService := services.NewExportService(db, propertyService)
+	mlsService := services.NewMLSService(db, listingRules...)
+	syndicationService := services.NewSyndicationService(db, cfg.PublicBaseURL)
+	analyticsService := services.NewAnalyticsService(db)
+	listingEvents := services.NewEventRecorder(db, cfg.AnalyticsEventBuffer)
+	dashboardService := services.NewDashboardService(db, propertyService, leadService, cfg.DashboardRebuildInterval)
+	reportService := services.NewReportService(db, mailer, notifier)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
-	propertyHandler := handlers.NewPropertyHandler(propertyService)
+	propertyHandler := handlers.NewPropertyHandler(propertyService, listingEvents)
+	attributeHandler := handlers.NewAttributeHandler(attributeService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	moderationHandler := handlers.NewModerationHandler(moderationService)
+	complianceHandler := handlers.NewComplianceHandler(complianceService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
 	mediaHandler := handlers.NewMediaHandler(mediaService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	openHouseHandler := handlers.NewOpenHouseHandler(openHouseService)
+	showingHandler := handlers.NewShowingHandler(showingService)
+	brokerageHandler := handlers.NewBrokerageHandler(brokerageService)
+	leadHandler := handlers.NewLeadHandler(leadService, listingEvents)
+	messageHandler := handlers.NewMessageHandler(messageService)
+	offerHandler := handlers.NewOfferHandler(offerService)
+	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	developmentHandler := handlers.NewDevelopmentHandler(developmentService)
+	comparisonHandler := handlers.NewComparisonHandler(comparisonService)
+	valuationHandler := handlers.NewValuationHandler(valuationService)
+	mortgageHandler := handlers.NewMortgageHandler(mortgageService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	mlsHandler := handlers.NewMLSHandler(mlsService)
+	syndicationHandler := handlers.NewSyndicationHandler(syndicationService)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, listingEvents)
+	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+	reportHandler := handlers.NewReportHandler(reportService)
+
+	// Seed the attribute catalog on first start
+	if err := attributeService.SeedDefaults(); err != nil {
+		log.Printf("Failed to seed attribute catalog: %v", err)
+	}
+
+	// Resume imports interrupted by a restart
+	if err := importService.ResumeImports(); err != nil {
+		log.Printf("Failed to resume imports: %v", err)
+	}
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authService)
 
+	// Background jobs and the server stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start background jobs. Listing events are recorded by requests, so the
+	// recorder is stopped only after the server has finished serving them.
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	eventsDone := make(chan struct{})
+	go func() {
+		defer close(eventsDone)
+		listingEvents.Run(eventsCtx, cfg.AnalyticsFlushInterval)
+	}()
+	if cfg.MLSSyncInterval > 0 {
+		go mlsService.RunScheduler(ctx, cfg.MLSSyncInterval)
+	}
+	if cfg.TrashRetention > 0 && cfg.TrashPurgeInterval > 0 {
+		go trashService.RunRetention(ctx, cfg.TrashPurgeInterval)
+	}
+	if cfg.ReportScheduleInterval > 0 {
+		go reportService.RunScheduler(ctx, cfg.ReportScheduleInterval)
+	}
+	if cfg.OfferExpiryInterval > 0 {
+		go offerService.RunExpiry(ctx, cfg.OfferExpiryInterval)
+	}
+
 	// Initialize router
 	router := gin.Default()
 
@@ -85,18 +222,248 @@ func main() {
 		properties := api.Group("/properties")
 		{
 			properties.GET("/", propertyHandler.SearchProperties)
-			properties.GET("/:id", propertyHandler.GetProperty)
+			properties.GET("/:id", authMiddleware.OptionalAuth(), propertyHandler.GetProperty)
 			properties.POST("/", authMiddleware.Authenticate(), propertyHandler.CreateProperty)
 			properties.PUT("/:id", authMiddleware.Authenticate(), propertyHandler.UpdateProperty)
 			properties.DELETE("/:id", authMiddleware.Authenticate(), propertyHandler.DeleteProperty)
 			properties.GET("/agent", authMiddleware.Authenticate(), propertyHandler.GetPropertiesByAgent)
+			properties.GET("/export", authMiddleware.Authenticate(), exportHandler.ExportSearch)
+			properties.GET("/compare", authMiddleware.OptionalAuth(), comparisonHandler.CompareProperties)
+			properties.GET("/agent/export", authMiddleware.Authenticate(), exportHandler.ExportAgentPortfolio)
+			properties.GET("/:id/revisions", authMiddleware.Authenticate(), revisionHandler.GetRevisions)
+			properties.GET("/:id/revisions/diff", authMiddleware.Authenticate(), revisionHandler.DiffRevisions)
+			properties.GET("/:id/revisions/:revision", authMiddleware.Authenticate(), revisionHandler.GetRevision)
+			properties.POST("/:id/revisions/:revision/restore", authMiddleware.Authenticate(), revisionHandler.RestoreRevision)
+			properties.GET("/:id/open-houses", authMiddleware.OptionalAuth(), openHouseHandler.GetPropertyOpenHouses)
+			properties.POST("/:id/open-houses", authMiddleware.Authenticate(), openHouseHandler.CreateOpenHouse)
+			properties.GET("/:id/showing-slots", authMiddleware.OptionalAuth(), showingHandler.GetSlots)
+			properties.POST("/:id/showings", authMiddleware.Authenticate(), showingHandler.RequestShowing)
+			properties.POST("/:id/inquiries", authMiddleware.OptionalAuth(), leadHandler.CreateInquiry)
+			properties.POST("/:id/conversations", authMiddleware.Authenticate(), messageHandler.StartConversation)
+			properties.POST("/:id/offers", authMiddleware.Authenticate(), offerHandler.SubmitOffer)
+			properties.GET("/:id/offers", authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAgent)), offerHandler.GetPropertyOffers)
+			properties.GET("/:id/valuation", authMiddleware.Authenticate(), valuationHandler.GetPropertyValuation)
+			properties.GET("/:id/quality", authMiddleware.Authenticate(), propertyHandler.GetListingQuality)
+			properties.POST("/:id/reports", authMiddleware.Authenticate(), moderationHandler.ReportListing)
+			properties.POST("/:id/compliance-override", authMiddleware.Authenticate(), complianceHandler.OverrideBlock)
+			properties.POST("/:id/events", authMiddleware.OptionalAuth(), analyticsHandler.RecordEvent)
+		}
+
+		// Fair-housing compliance routes
+		compliance := api.Group("/compliance")
+		compliance.Use(authMiddleware.Authenticate())
+		{
+			compliance.POST("/lint", complianceHandler.LintDescription)
+			compliance.GET("/blocked", complianceHandler.GetBlockedListings)
+		}
+
+		// Listing performance analytics routes
+		analytics := api.Group("/analytics")
+		analytics.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAgent)))
+		{
+			analytics.GET("/agent", analyticsHandler.GetAgentAnalytics)
+			analytics.GET("/properties/:id", analyticsHandler.GetListingAnalytics)
+		}
+
+		// Agent dashboard routes
+		dashboard := api.Group("/dashboard")
+		dashboard.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAgent)))
+		{
+			dashboard.GET("/agent", dashboardHandler.GetAgentDashboard)
+		}
+
+		// Attribute catalog routes
+		api.GET("/attributes", attributeHandler.GetAttributes)
+
+		// Development routes
+		developments := api.Group("/developments")
+		{
+			developments.GET("/", developmentHandler.GetDevelopments)
+			developments.GET("/:id", authMiddleware.OptionalAuth(), developmentHandler.GetDevelopment)
+			developments.POST("/", authMiddleware.Authenticate(), developmentHandler.CreateDevelopment)
+			developments.PUT("/:id", authMiddleware.Authenticate(), developmentHandler.UpdateDevelopment)
+			developments.DELETE("/:id", authMiddleware.Authenticate(), developmentHandler.DeleteDevelopment)
+			developments.POST("/:id/units", authMiddleware.Authenticate(), developmentHandler.CreateUnit)
+		}
+
+		// Open house routes
+		openHouses := api.Group("/open-houses")
+		{
+			openHouses.GET("/:id", authMiddleware.OptionalAuth(), openHouseHandler.GetOpenHouse)
+			openHouses.PUT("/:id", authMiddleware.Authenticate(), openHouseHandler.UpdateOpenHouse)
+			openHouses.DELETE("/:id", authMiddleware.Authenticate(), openHouseHandler.CancelOpenHouse)
+			openHouses.GET("/:id/calendar.ics", authMiddleware.OptionalAuth(), openHouseHandler.GetEventCalendar)
+			openHouses.POST("/:id/rsvp", authMiddleware.Authenticate(), openHouseHandler.RSVP)
+			openHouses.GET("/:id/rsvp", authMiddleware.Authenticate(), openHouseHandler.GetMyRSVP)
+			openHouses.DELETE("/:id/rsvp", authMiddleware.Authenticate(), openHouseHandler.CancelRSVP)
+			openHouses.GET("/:id/rsvps", authMiddleware.Authenticate(), openHouseHandler.GetRSVPs)
+		}
+
+		// Private showing routes
+		showings := api.Group("/showings")
+		{
+			agentOnly := authMiddleware.RequireRole(string(models.RoleAgent))
+			showings.GET("/", authMiddleware.Authenticate(), showingHandler.GetShowings)
+			showings.GET("/availability", authMiddleware.Authenticate(), agentOnly, showingHandler.GetAvailability)
+			showings.PUT("/availability", authMiddleware.Authenticate(), agentOnly, showingHandler.UpdateAvailability)
+			showings.POST("/availability/calendar-token", authMiddleware.Authenticate(), agentOnly, showingHandler.RegenerateCalendarToken)
+			showings.POST("/availability/blackouts", authMiddleware.Authenticate(), agentOnly, showingHandler.AddBlackout)
+			showings.DELETE("/availability/blackouts/:id", authMiddleware.Authenticate(), agentOnly, showingHandler.DeleteBlackout)
+			showings.GET("/calendar/:token", showingHandler.GetAgentCalendar)
+			showings.GET("/:id", authMiddleware.Authenticate(), showingHandler.GetShowing)
+			showings.POST("/:id/confirm", authMiddleware.Authenticate(), showingHandler.ConfirmShowing)
+			showings.POST("/:id/reschedule", authMiddleware.Authenticate(), showingHandler.RescheduleShowing)
+			showings.POST("/:id/cancel", authMiddleware.Authenticate(), showingHandler.CancelShowing)
+		}
+
+		// Lead pipeline routes
+		leads := api.Group("/leads")
+		leads.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAgent)))
+		{
+			leads.GET("/", leadHandler.GetLeads)
+			leads.GET("/export", leadHandler.ExportLeads)
+			leads.GET("/:id", leadHandler.GetLead)
+			leads.PUT("/:id/stage", leadHandler.UpdateStage)
+			leads.POST("/:id/notes", leadHandler.AddNote)
+			leads.POST("/:id/assign", leadHandler.AssignLead)
+		}
+
+		// Messaging routes. The event stream also accepts the token as a
+		// query parameter for EventSource clients.
+		api.GET("/conversations/stream", authMiddleware.AuthenticateStream(), messageHandler.Stream)
+		conversations := api.Group("/conversations")
+		conversations.Use(authMiddleware.Authenticate())
+		{
+			conversations.GET("/", messageHandler.GetConversations)
+			conversations.GET("/unread", messageHandler.GetUnreadCount)
+			conversations.GET("/:id", messageHandler.GetConversation)
+			conversations.GET("/:id/messages", messageHandler.GetMessages)
+			conversations.POST("/:id/messages", messageHandler.SendMessage)
+			conversations.POST("/:id/read", messageHandler.MarkRead)
+			conversations.GET("/:id/attachments/:file", messageHandler.GetAttachment)
+		}
+
+		// Offer routes
+		offers := api.Group("/offers")
+		offers.Use(authMiddleware.Authenticate())
+		{
+			offers.GET("/", offerHandler.GetOffers)
+			offers.GET("/:id", offerHandler.GetOffer)
+			offers.POST("/:id/counter", offerHandler.CounterOffer)
+			offers.POST("/:id/accept", offerHandler.AcceptOffer)
+			offers.POST("/:id/reject", offerHandler.RejectOffer)
+			offers.POST("/:id/withdraw", offerHandler.WithdrawOffer)
+		}
+
+		// Transaction routes
+		transactions := api.Group("/transactions")
+		transactions.Use(authMiddleware.Authenticate())
+		{
+			transactions.GET("/", transactionHandler.GetTransactions)
+			transactions.GET("/:id", transactionHandler.GetTransaction)
+			transactions.POST("/:id/milestones", transactionHandler.AddMilestone)
+			transactions.PUT("/:id/milestones/:milestoneId", transactionHandler.UpdateMilestone)
+			transactions.POST("/:id/close", transactionHandler.CloseTransaction)
+			transactions.POST("/:id/cancel", transactionHandler.CancelTransaction)
+		}
+
+		// Calculator routes
+		calculations := api.Group("/calculations")
+		{
+			calculations.POST("/mortgage", mortgageHandler.CalculateMortgage)
+			calculations.POST("/affordability", mortgageHandler.FindAffordableListings)
+		}
+
+		// Valuation routes
+		valuations := api.Group("/valuations")
+		{
+			valuations.POST("/", authMiddleware.Authenticate(), valuationHandler.EstimateValue)
+		}
+
+		// Brokerage routes
+		brokerages := api.Group("/brokerages")
+		{
+			brokerages.GET("/mine", authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAgent)), brokerageHandler.GetMyBrokerage)
+		}
+
+		// Agent calendar feeds
+		agents := api.Group("/agents")
+		{
+			agents.GET("/:id/open-houses.ics", authMiddleware.OptionalAuth(), openHouseHandler.GetAgentCalendar)
+		}
+
+		// Bulk import routes
+		imports := api.Group("/imports")
+		{
+			imports.GET("/", authMiddleware.Authenticate(), importHandler.GetImports)
+			imports.POST("/", authMiddleware.Authenticate(), importHandler.ValidateImport)
+			imports.GET("/:id", authMiddleware.Authenticate(), importHandler.GetImport)
+			imports.POST("/:id/commit", authMiddleware.Authenticate(), importHandler.CommitImport)
+		}
+
+		// Trash routes
+		trash := api.Group("/trash")
+		trash.Use(authMiddleware.Authenticate())
+		{
+			trash.GET("/", trashHandler.GetTrash)
+			trash.POST("/properties/:id/restore", trashHandler.RestoreProperty)
+			trash.POST("/media/:id/restore", trashHandler.RestoreMediaFile)
+			trash.POST("/vr-tours/:id/restore", trashHandler.RestoreVRTour)
+		}
+
+		// Syndication feed routes, authenticated by the partner feed key
+		feeds := api.Group("/feeds")
+		{
+			feeds.GET("/:key/reso", syndicationHandler.GetRESOFeed)
+			feeds.GET("/:key/listings.xml", syndicationHandler.GetXMLFeed)
+			feeds.GET("/:key/rss", syndicationHandler.GetRSSFeed)
+			feeds.GET("/:key/atom", syndicationHandler.GetAtomFeed)
+		}
+
+		// Admin routes
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(string(models.RoleAdmin)))
+		{
+			admin.GET("/mls/feeds", mlsHandler.GetFeeds)
+			admin.POST("/mls/feeds", mlsHandler.CreateFeed)
+			admin.POST("/mls/feeds/:id/sync", mlsHandler.SyncFeed)
+			admin.GET("/syndication/partners", syndicationHandler.GetPartners)
+			admin.POST("/syndication/partners", syndicationHandler.CreatePartner)
+			admin.PUT("/syndication/partners/:id", syndicationHandler.UpdatePartner)
+			admin.POST("/trash/purge", trashHandler.PurgeExpired)
+			admin.DELETE("/trash/properties/:id", trashHandler.PurgeProperty)
+			admin.DELETE("/trash/media/:id", trashHandler.PurgeMediaFile)
+			admin.DELETE("/trash/vr-tours/:id", trashHandler.PurgeVRTour)
+			admin.GET("/brokerages", brokerageHandler.GetBrokerages)
+			admin.POST("/brokerages", brokerageHandler.CreateBrokerage)
+			admin.PUT("/users/:id/brokerage", brokerageHandler.SetMembership)
+			admin.GET("/messages/flagged", messageHandler.GetFlaggedMessages)
+			admin.POST("/attributes", attributeHandler.CreateAttribute)
+			admin.PUT("/attributes/:id", attributeHandler.UpdateAttribute)
+			admin.DELETE("/attributes/:id", attributeHandler.DeleteAttribute)
+			admin.POST("/attributes/migrate-features", attributeHandler.MigrateFeatures)
+			admin.GET("/duplicates", duplicateHandler.GetDuplicates)
+			admin.POST("/duplicates/scan", duplicateHandler.ScanDuplicates)
+			admin.POST("/duplicates/:id/dismiss", duplicateHandler.DismissDuplicate)
+			admin.POST("/duplicates/:id/merge", duplicateHandler.MergeDuplicate)
+			admin.POST("/quality/refresh", propertyHandler.RefreshQualityScores)
+			admin.GET("/moderation", moderationHandler.GetQueue)
+			admin.POST("/moderation/:id/approve", moderationHandler.ApproveItem)
+			admin.POST("/moderation/:id/reject", moderationHandler.RejectItem)
+			admin.GET("/reports", reportHandler.GetMetrics)
+			admin.GET("/reports/schedules", reportHandler.GetSchedules)
+			admin.POST("/reports/schedules", reportHandler.CreateSchedule)
+			admin.PUT("/reports/schedules/:id", reportHandler.UpdateSchedule)
+			admin.DELETE("/reports/schedules/:id", reportHandler.DeleteSchedule)
+			admin.POST("/reports/schedules/:id/send", reportHandler.SendSchedule)
+			admin.GET("/reports/:metric", reportHandler.GetReport)
 		}
 
 		// Media routes
 		media := api.Group("/properties")
 		{
 			media.POST("/:id/upload", authMiddleware.Authenticate(), mediaHandler.UploadFile)
-			media.GET("/:id/media", mediaHandler.GetPropertyMedia)
+			media.GET("/:id/media", authMiddleware.OptionalAuth(), mediaHandler.GetPropertyMedia)
 			media.DELETE("/:id/media/:fileId", authMiddleware.Authenticate(), mediaHandler.DeleteMediaFile)
 		}
 	}
@@ -107,8 +474,24 @@ func main() {
 		port = "8080"
 	}
 
-	log.Printf("Starting Galactavista server on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		log.Printf("Starting Galactavista server on port %s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down Galactavista server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down server gracefully: %v", err)
 	}
+
+	// Flush buffered listing events before exiting
+	stopEvents()
+	<-eventsDone
 }
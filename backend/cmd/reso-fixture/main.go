@@ -0,0 +1,44 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"galactavista/pkg/reso"
+)
+
+//go:embed fixtures/properties.json
+var defaultFixture []byte
+
+// reso-fixture serves RESO Property records from a JSON file so the MLS
+// ingestion can be run locally. Point an MLS feed at
+// http://localhost:8090/reso/odata to sync from it.
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	file := flag.String("file", "", "JSON file with an array of RESO Property records (defaults to the bundled fixture)")
+	flag.Parse()
+
+	data := defaultFixture
+	if *file != "" {
+		var err error
+		if data, err = os.ReadFile(*file); err != nil {
+			log.Fatal("Failed to read fixture file:", err)
+		}
+	}
+
+	var records []reso.Property
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Fatal("Failed to parse fixture file:", err)
+	}
+
+	http.Handle("/reso/odata/Property", reso.NewFixtureHandler(records))
+
+	log.Printf("Serving %d RESO fixture records on %s/reso/odata", len(records), *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatal("Failed to start fixture server:", err)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// visitorIDHeader carries the anonymous visitor ID the web app keeps in the
+// browser, so unique visitors survive address changes
+const visitorIDHeader = "X-Visitor-ID"
+
+// AnalyticsHandler handles listing event reports and performance analytics
+type AnalyticsHandler struct {
+	analyticsService *services.AnalyticsService
+	events           *services.EventRecorder
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService, events *services.EventRecorder) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService, events: events}
+}
+
+// RecordEvent records a media view or tour open the client reports on a
+// listing. Events are stored in the background, so the response does not
+// confirm the listing exists.
+func (h *AnalyticsHandler) RecordEvent(c *gin.Context) {
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ListingEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+	if req.Type != models.ListingEventMediaView && req.Type != models.ListingEventTourOpen {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "type must be media_view or tour_open",
+		})
+		return
+	}
+
+	event := listingEvent(c, uint(propertyID), req.Type)
+	event.MediaFileID = req.MediaFileID
+	event.VRTourID = req.VRTourID
+	h.events.Record(event)
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Success: true,
+		Message: "Event recorded",
+	})
+}
+
+// GetListingAnalytics reports a listing's performance to its agent
+func (h *AnalyticsHandler) GetListingAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	req, ok := bindAnalyticsRequest(c)
+	if !ok {
+		return
+	}
+
+	analytics, err := h.analyticsService.GetListingAnalytics(uint(propertyID), userID.(uint), req)
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    analytics,
+	})
+}
+
+// GetAgentAnalytics reports the combined performance of the authenticated
+// agent's listings
+func (h *AnalyticsHandler) GetAgentAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	req, ok := bindAnalyticsRequest(c)
+	if !ok {
+		return
+	}
+
+	analytics, err := h.analyticsService.GetAgentAnalytics(userID.(uint), req)
+	if err != nil {
+		respondAnalyticsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    analytics,
+	})
+}
+
+// bindAnalyticsRequest binds the analytics query parameters
+func bindAnalyticsRequest(c *gin.Context) (*models.AnalyticsRequest, bool) {
+	var req models.AnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return nil, false
+	}
+	return &req, true
+}
+
+// listingEvent builds an event of the request's visitor on a listing
+func listingEvent(c *gin.Context, propertyID uint, eventType models.ListingEventType) models.ListingEvent {
+	event := models.ListingEvent{PropertyID: propertyID, Type: eventType}
+	if userID, exists := c.Get("user_id"); exists {
+		id := userID.(uint)
+		event.UserID = &id
+	}
+	event.VisitorID = visitorID(c)
+	return event
+}
+
+// visitorID identifies the visitor making a request: the signed-in user,
+// else the browser's visitor ID, else a hash of the client address and user
+// agent. Browser IDs are hashed too so stored IDs have a fixed length.
+func visitorID(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%d", userID.(uint))
+	}
+
+	source := c.GetHeader(visitorIDHeader)
+	if source == "" {
+		source = c.ClientIP() + "|" + c.Request.UserAgent()
+	}
+	sum := sha256.Sum256([]byte(source))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+// respondAnalyticsError maps analytics service errors to HTTP responses
+func respondAnalyticsError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Property not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You can only view analytics for your own listings",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load analytics",
+		})
+	}
+}
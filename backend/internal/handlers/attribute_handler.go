@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AttributeHandler handles property attribute catalog requests
+type AttributeHandler struct {
+	attributeService *services.AttributeService
+}
+
+// NewAttributeHandler creates a new attribute handler
+func NewAttributeHandler(attributeService *services.AttributeService) *AttributeHandler {
+	return &AttributeHandler{attributeService: attributeService}
+}
+
+// GetAttributes lists the attribute catalog
+func (h *AttributeHandler) GetAttributes(c *gin.Context) {
+	var req models.AttributeListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	attributes, err := h.attributeService.GetAttributes(&req)
+	if err != nil {
+		respondAttributeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    attributes,
+	})
+}
+
+// CreateAttribute adds an attribute to the catalog (admin only)
+func (h *AttributeHandler) CreateAttribute(c *gin.Context) {
+	var req models.AttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	attribute, err := h.attributeService.CreateAttribute(&req)
+	if err != nil {
+		respondAttributeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Attribute created successfully",
+		Data:    attribute,
+	})
+}
+
+// UpdateAttribute replaces a catalog attribute (admin only)
+func (h *AttributeHandler) UpdateAttribute(c *gin.Context) {
+	id, ok := parseAttributeID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	attribute, err := h.attributeService.UpdateAttribute(id, &req)
+	if err != nil {
+		respondAttributeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Attribute updated successfully",
+		Data:    attribute,
+	})
+}
+
+// DeleteAttribute removes an attribute and its values (admin only)
+func (h *AttributeHandler) DeleteAttribute(c *gin.Context) {
+	id, ok := parseAttributeID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attributeService.DeleteAttribute(id); err != nil {
+		respondAttributeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Attribute deleted successfully",
+	})
+}
+
+// MigrateFeatures maps free-form listing features onto catalog attributes
+// (admin only)
+func (h *AttributeHandler) MigrateFeatures(c *gin.Context) {
+	result, err := h.attributeService.MigrateFeatures()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Features migrated",
+		Data:    result,
+	})
+}
+
+// parseAttributeID parses the attribute ID path parameter
+func parseAttributeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid attribute ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondAttributeError maps attribute service errors to HTTP responses
+func respondAttributeError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Attribute not found",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
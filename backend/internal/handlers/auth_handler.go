@@ -95,6 +95,11 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	if setVersionETag(c, user.Version) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    user,
@@ -112,13 +117,12 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		Phone     string `json:"phone"`
-		Avatar    string `json:"avatar"`
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
 	}
 
+	var req models.UserProfileUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
@@ -127,10 +131,22 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement profile update logic using userID
-	_ = userID // Use userID to avoid unused variable warning
+	user, err := h.authService.UpdateProfile(userID.(uint), &req, expectedVersion)
+	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", versionETag(user.Version))
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Profile updated successfully",
+		Data:    user,
 	})
 }
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BrokerageHandler handles brokerage requests
+type BrokerageHandler struct {
+	brokerageService *services.BrokerageService
+}
+
+// NewBrokerageHandler creates a new brokerage handler
+func NewBrokerageHandler(brokerageService *services.BrokerageService) *BrokerageHandler {
+	return &BrokerageHandler{brokerageService: brokerageService}
+}
+
+// GetBrokerages lists all brokerages (admin only)
+func (h *BrokerageHandler) GetBrokerages(c *gin.Context) {
+	brokerages, err := h.brokerageService.GetBrokerages()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    brokerages,
+	})
+}
+
+// CreateBrokerage creates a brokerage (admin only)
+func (h *BrokerageHandler) CreateBrokerage(c *gin.Context) {
+	var req models.BrokerageCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	brokerage, err := h.brokerageService.CreateBrokerage(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Brokerage created successfully",
+		Data:    brokerage,
+	})
+}
+
+// SetMembership adds an agent to a brokerage or removes them (admin only)
+func (h *BrokerageHandler) SetMembership(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid user ID",
+		})
+		return
+	}
+
+	var req models.BrokerageMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.brokerageService.SetMembership(uint(userID), &req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "User or brokerage not found",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Brokerage membership updated successfully",
+	})
+}
+
+// GetMyBrokerage gets the authenticated agent's brokerage and its agents
+func (h *BrokerageHandler) GetMyBrokerage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	brokerage, err := h.brokerageService.GetAgentBrokerage(userID.(uint))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "You do not belong to a brokerage",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    brokerage,
+	})
+}
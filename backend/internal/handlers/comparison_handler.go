@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ComparisonHandler handles property comparison requests
+type ComparisonHandler struct {
+	comparisonService *services.ComparisonService
+}
+
+// NewComparisonHandler creates a new comparison handler
+func NewComparisonHandler(comparisonService *services.ComparisonService) *ComparisonHandler {
+	return &ComparisonHandler{comparisonService: comparisonService}
+}
+
+// CompareProperties compares 2 to 5 properties side by side
+func (h *ComparisonHandler) CompareProperties(c *gin.Context) {
+	var req models.PropertyCompareRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	comparison, err := h.comparisonService.CompareProperties(req.IDs, listingViewer(c))
+	if err != nil {
+		var validationErr *services.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+				Data:    validationErr.Errors,
+			})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    comparison,
+	})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ComplianceHandler handles fair-housing linting and broker overrides
+type ComplianceHandler struct {
+	complianceService *services.ComplianceService
+}
+
+// NewComplianceHandler creates a new compliance handler
+func NewComplianceHandler(complianceService *services.ComplianceService) *ComplianceHandler {
+	return &ComplianceHandler{complianceService: complianceService}
+}
+
+// LintDescription checks a draft listing description for fair-housing
+// issues
+func (h *ComplianceHandler) LintDescription(c *gin.Context) {
+	var req models.ComplianceLintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    h.complianceService.LintDescription(&req),
+	})
+}
+
+// GetBlockedListings lists the blocked listings of the broker's brokerage
+func (h *ComplianceHandler) GetBlockedListings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	listings, err := h.complianceService.GetBlockedListings(userID.(uint))
+	if err != nil {
+		respondComplianceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    listings,
+	})
+}
+
+// OverrideBlock publishes a blocked listing on a broker's authority
+func (h *ComplianceHandler) OverrideBlock(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ComplianceOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	property, err := h.complianceService.OverrideBlock(uint(propertyID), userID.(uint), &req)
+	if err != nil {
+		respondComplianceError(c, err)
+		return
+	}
+
+	c.Header("ETag", versionETag(property.Version))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Listing published by broker override",
+		Data:    property,
+	})
+}
+
+// respondComplianceError maps compliance service errors to HTTP responses
+func respondComplianceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Property not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "Only a broker of the listing agent's brokerage can do this",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
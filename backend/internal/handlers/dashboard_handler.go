@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHandler handles agent dashboard requests
+type DashboardHandler struct {
+	dashboardService *services.DashboardService
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboardService *services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{dashboardService: dashboardService}
+}
+
+// GetAgentDashboard returns the authenticated agent's dashboard statistics
+// with their recent listings and inquiries
+func (h *DashboardHandler) GetAgentDashboard(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	dashboard, err := h.dashboardService.GetAgentDashboard(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Failed to load dashboard",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    dashboard,
+	})
+}
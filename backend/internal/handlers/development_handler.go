@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DevelopmentHandler handles development requests
+type DevelopmentHandler struct {
+	developmentService *services.DevelopmentService
+}
+
+// NewDevelopmentHandler creates a new development handler
+func NewDevelopmentHandler(developmentService *services.DevelopmentService) *DevelopmentHandler {
+	return &DevelopmentHandler{developmentService: developmentService}
+}
+
+// CreateDevelopment creates a new development
+func (h *DevelopmentHandler) CreateDevelopment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.DevelopmentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	development, err := h.developmentService.CreateDevelopment(&req, userID.(uint))
+	if err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Development created successfully",
+		Data:    development,
+	})
+}
+
+// GetDevelopments lists developments
+func (h *DevelopmentHandler) GetDevelopments(c *gin.Context) {
+	var req models.DevelopmentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	developments, err := h.developmentService.GetDevelopments(&req)
+	if err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    developments,
+	})
+}
+
+// GetDevelopment gets a development with its unit availability and price
+// tables
+func (h *DevelopmentHandler) GetDevelopment(c *gin.Context) {
+	id, ok := parseDevelopmentID(c)
+	if !ok {
+		return
+	}
+
+	development, err := h.developmentService.GetDevelopment(id, listingViewer(c))
+	if err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    development,
+	})
+}
+
+// UpdateDevelopment updates a development
+func (h *DevelopmentHandler) UpdateDevelopment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseDevelopmentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DevelopmentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	development, err := h.developmentService.UpdateDevelopment(id, &req, userID.(uint))
+	if err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Development updated successfully",
+		Data:    development,
+	})
+}
+
+// DeleteDevelopment deletes a development without units
+func (h *DevelopmentHandler) DeleteDevelopment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseDevelopmentID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.developmentService.DeleteDevelopment(id, userID.(uint)); err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Development deleted successfully",
+	})
+}
+
+// CreateUnit lists a unit of a development
+func (h *DevelopmentHandler) CreateUnit(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseDevelopmentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DevelopmentUnitCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	property, err := h.developmentService.CreateUnit(id, &req, userID.(uint))
+	if err != nil {
+		respondDevelopmentError(c, err)
+		return
+	}
+
+	c.Header("ETag", versionETag(property.Version))
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Unit created successfully",
+		Data:    property,
+	})
+}
+
+// parseDevelopmentID parses the development ID path parameter
+func parseDevelopmentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid development ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondDevelopmentError maps development service errors to HTTP responses
+func respondDevelopmentError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Development not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You do not manage this development",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
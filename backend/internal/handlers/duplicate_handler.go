@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DuplicateHandler handles the duplicate listing review queue
+type DuplicateHandler struct {
+	duplicateService *services.DuplicateService
+}
+
+// NewDuplicateHandler creates a new duplicate handler
+func NewDuplicateHandler(duplicateService *services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{duplicateService: duplicateService}
+}
+
+// GetDuplicates lists flagged duplicate listings (admin only)
+func (h *DuplicateHandler) GetDuplicates(c *gin.Context) {
+	var req models.DuplicateListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	duplicates, err := h.duplicateService.GetDuplicates(&req)
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    duplicates,
+	})
+}
+
+// ScanDuplicates normalizes all listing addresses and flags duplicates
+// (admin only)
+func (h *DuplicateHandler) ScanDuplicates(c *gin.Context) {
+	result, err := h.duplicateService.ScanDuplicates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Duplicate scan completed",
+		Data:    result,
+	})
+}
+
+// DismissDuplicate marks a flagged pair as not a duplicate (admin only)
+func (h *DuplicateHandler) DismissDuplicate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseDuplicateID(c)
+	if !ok {
+		return
+	}
+
+	duplicate, err := h.duplicateService.DismissDuplicate(id, userID.(uint))
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Duplicate dismissed",
+		Data:    duplicate,
+	})
+}
+
+// MergeDuplicate merges a flagged pair into one listing (admin only)
+func (h *DuplicateHandler) MergeDuplicate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseDuplicateID(c)
+	if !ok {
+		return
+	}
+
+	var req models.DuplicateMergeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	property, err := h.duplicateService.MergeDuplicate(id, &req, userID.(uint))
+	if err != nil {
+		respondDuplicateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Listings merged successfully",
+		Data:    property,
+	})
+}
+
+// parseDuplicateID parses the duplicate ID path parameter
+func parseDuplicateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid duplicate ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondDuplicateError maps duplicate service errors to HTTP responses
+func respondDuplicateError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Duplicate or listing not found",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
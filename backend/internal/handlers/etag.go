@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag formats a resource version as a strong ETag
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setVersionETag sets the ETag header for a versioned resource and reports
+// whether the client's If-None-Match already matches it
+func setVersionETag(c *gin.Context, version int) bool {
+	return setETag(c, versionETag(version))
+}
+
+// contentETag formats a resource version and a hash of its response body as
+// a strong ETag. It suits resources whose responses include related data
+// that can change without the resource's version changing. requireIfMatch
+// reads the version back from it.
+func contentETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// setContentETag sets the ETag header for a versioned resource whose
+// response body is given and reports whether the client's If-None-Match
+// already matches it
+func setContentETag(c *gin.Context, version int, body []byte) bool {
+	return setETag(c, contentETag(version, body))
+}
+
+// setETag sets the ETag header and reports whether the client's
+// If-None-Match already matches it
+func setETag(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// requireIfMatch reads the expected version from the If-Match header, which
+// may hold a version or content ETag. It writes 428 Precondition Required
+// when the header is missing and 412 Precondition Failed when it is not one
+// of those.
+func requireIfMatch(c *gin.Context) (int, bool) {
+	ifMatch := strings.TrimSpace(c.GetHeader("If-Match"))
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, models.APIResponse{
+			Success: false,
+			Error:   "If-Match header with the resource ETag is required",
+		})
+		return 0, false
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		c.JSON(http.StatusPreconditionFailed, models.APIResponse{
+			Success: false,
+			Error:   "If-Match header does not match a resource version",
+		})
+		return 0, false
+	}
+
+	return version, true
+}
+
+// respondVersionConflict writes a 412 response with field-level conflict
+// details if err is a version conflict, and reports whether it did
+func respondVersionConflict(c *gin.Context, err error) bool {
+	var conflict *services.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	c.Header("ETag", versionETag(conflict.Details.CurrentVersion))
+	c.JSON(http.StatusPreconditionFailed, models.APIResponse{
+		Success: false,
+		Error:   "Resource was modified by another request",
+		Data:    conflict.Details,
+	})
+	return true
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"galactavista/pkg/tabular"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler handles listing export requests
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportSearch exports the properties matching the search filters
+func (h *ExportHandler) ExportSearch(c *gin.Context) {
+	// Exports include every match, so pagination only needs to validate
+	req := models.PropertySearchRequest{PaginationRequest: models.PaginationRequest{Page: 1, PageSize: 1}}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	req.Attributes = c.QueryMap("attr")
+
+	format, err := tabular.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	w := newExportWriter(c, "listings", format)
+	if err := h.exportService.ExportSearch(w, &req, format); err != nil {
+		if w.abort(err) || respondSearchValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
+
+// ExportAgentPortfolio exports all properties of the current agent
+func (h *ExportHandler) ExportAgentPortfolio(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	format, err := tabular.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	w := newExportWriter(c, "portfolio", format)
+	if err := h.exportService.ExportAgentPortfolio(w, userID.(uint), format); err != nil {
+		if w.abort(err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
+
+// sendExport writes an export file as a download
+func sendExport(c *gin.Context, name string, format tabular.Format, buf *bytes.Buffer) {
+	setExportHeaders(c, name, format)
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// setExportHeaders marks the response as a dated download of an export file
+func setExportHeaders(c *gin.Context, name string, format tabular.Format) {
+	fileName := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+}
+
+// exportWriter streams an export file as a download. The download headers
+// are only sent with the first write, so an error that happens before
+// anything is written can still be sent as JSON.
+type exportWriter struct {
+	c       *gin.Context
+	name    string
+	format  tabular.Format
+	started bool
+}
+
+// newExportWriter creates a writer streaming an export to the response
+func newExportWriter(c *gin.Context, name string, format tabular.Format) *exportWriter {
+	return &exportWriter{c: c, name: name, format: format}
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		setExportHeaders(w.c, w.name, w.format)
+		w.c.Header("Content-Type", w.format.ContentType())
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// abort handles an export error once the download has started, when it
+// can no longer be sent to the client: the error is attached to the request
+// log and the download is left truncated. It returns false if nothing was
+// written yet.
+func (w *exportWriter) abort(err error) bool {
+	if !w.started {
+		return false
+	}
+	_ = w.c.Error(err)
+	return true
+}
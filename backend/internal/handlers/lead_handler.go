@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"galactavista/pkg/tabular"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LeadHandler handles buyer inquiry and lead pipeline requests
+type LeadHandler struct {
+	leadService *services.LeadService
+	events      *services.EventRecorder
+}
+
+// NewLeadHandler creates a new lead handler
+func NewLeadHandler(leadService *services.LeadService, events *services.EventRecorder) *LeadHandler {
+	return &LeadHandler{leadService: leadService, events: events}
+}
+
+// CreateInquiry sends an inquiry about a property to its agent. Guests
+// must include their name and email address.
+func (h *LeadHandler) CreateInquiry(c *gin.Context) {
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.InquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	var buyerID *uint
+	if userID, exists := c.Get("user_id"); exists {
+		id := userID.(uint)
+		buyerID = &id
+	}
+
+	if _, err := h.leadService.CreateInquiry(uint(propertyID), buyerID, &req); err != nil {
+		respondLeadError(c, err)
+		return
+	}
+	h.events.Record(listingEvent(c, uint(propertyID), models.ListingEventInquiry))
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Your inquiry has been sent to the agent",
+	})
+}
+
+// GetLeads lists the authenticated agent's leads
+func (h *LeadHandler) GetLeads(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.LeadListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	leads, err := h.leadService.GetLeads(userID.(uint), &req)
+	if err != nil {
+		respondLeadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    leads,
+	})
+}
+
+// ExportLeads exports the authenticated agent's leads matching the filters
+func (h *LeadHandler) ExportLeads(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.LeadListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	format, err := tabular.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.leadService.ExportLeads(&buf, userID.(uint), &req, format); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	sendExport(c, "leads", format, &buf)
+}
+
+// GetLead gets a lead with its notes
+func (h *LeadHandler) GetLead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseLeadID(c)
+	if !ok {
+		return
+	}
+
+	lead, err := h.leadService.GetLead(id, userID.(uint))
+	if err != nil {
+		respondLeadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    lead,
+	})
+}
+
+// UpdateStage moves a lead to another pipeline stage
+func (h *LeadHandler) UpdateStage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseLeadID(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeadStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	lead, err := h.leadService.UpdateStage(id, userID.(uint), &req)
+	if err != nil {
+		respondLeadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Lead stage updated successfully",
+		Data:    lead,
+	})
+}
+
+// AddNote records a note against a lead
+func (h *LeadHandler) AddNote(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseLeadID(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeadNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	note, err := h.leadService.AddNote(id, userID.(uint), &req)
+	if err != nil {
+		respondLeadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Note added successfully",
+		Data:    note,
+	})
+}
+
+// AssignLead reassigns a lead to another agent of the brokerage
+func (h *LeadHandler) AssignLead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseLeadID(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeadAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	lead, err := h.leadService.AssignLead(id, userID.(uint), &req)
+	if err != nil {
+		respondLeadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Lead assigned successfully",
+		Data:    lead,
+	})
+}
+
+// parseLeadID parses the lead ID path parameter
+func parseLeadID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid lead ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondLeadError maps lead service errors to HTTP responses
+func respondLeadError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You do not have access to this lead",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"galactavista/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // MediaHandler handles media file operations
@@ -73,7 +75,14 @@ func (h *MediaHandler) GetPropertyMedia(c *gin.Context) {
 	}
 
 	// Get media files
-	mediaFiles, err := h.mediaService.GetPropertyMedia(uint(propertyID))
+	mediaFiles, err := h.mediaService.GetPropertyMedia(uint(propertyID), listingViewer(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Property not found",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
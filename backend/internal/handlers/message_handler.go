@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// messageStreamHeartbeat is how often an idle event stream sends a ping so
+// proxies keep the connection open
+const messageStreamHeartbeat = 25 * time.Second
+
+// MessageHandler handles conversation and messaging requests
+type MessageHandler struct {
+	messageService *services.MessageService
+}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler(messageService *services.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+// StartConversation opens a conversation with a property's agent
+func (h *MessageHandler) StartConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ConversationCreateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	conversation, err := h.messageService.StartConversation(uint(propertyID), userID.(uint), &req)
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    conversation,
+	})
+}
+
+// GetConversations lists the authenticated user's conversations
+func (h *MessageHandler) GetConversations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.ConversationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	conversations, err := h.messageService.GetConversations(userID.(uint), &req)
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    conversations,
+	})
+}
+
+// GetUnreadCount gets the authenticated user's unread message totals
+func (h *MessageHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	counts, err := h.messageService.GetUnreadCount(userID.(uint))
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    counts,
+	})
+}
+
+// GetConversation gets a conversation by ID
+func (h *MessageHandler) GetConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseConversationID(c)
+	if !ok {
+		return
+	}
+
+	conversation, err := h.messageService.GetConversation(id, userID.(uint))
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    conversation,
+	})
+}
+
+// GetMessages pages through a conversation's message history
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseConversationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MessageHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	messages, err := h.messageService.GetMessages(id, userID.(uint), &req)
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    messages,
+	})
+}
+
+// SendMessage sends a message. Text-only messages may be sent as JSON;
+// messages with attachments are sent as multipart form data.
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseConversationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MessageCreateRequest
+	var files []*multipart.FileHeader
+	if c.ContentType() == "multipart/form-data" {
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+		files = form.File["attachments"]
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	message, err := h.messageService.SendMessage(id, userID.(uint), req.Body, files)
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Message sent successfully",
+		Data:    message,
+	})
+}
+
+// MarkRead marks a conversation read and sends a read receipt
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseConversationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MarkReadRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := h.messageService.MarkRead(id, userID.(uint), &req); err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Conversation marked as read",
+	})
+}
+
+// GetAttachment serves a message attachment to a conversation participant
+func (h *MessageHandler) GetAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseConversationID(c)
+	if !ok {
+		return
+	}
+
+	path, originalName, err := h.messageService.GetAttachment(id, userID.(uint), c.Param("file"))
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.FileAttachment(path, originalName)
+}
+
+// Stream delivers new messages and read receipts to the authenticated user
+// as server-sent events
+func (h *MessageHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	events, unsubscribe := h.messageService.Subscribe(userID.(uint))
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(messageStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("ready", gin.H{"user_id": userID})
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
+
+// GetFlaggedMessages lists messages flagged for review (admin only)
+func (h *MessageHandler) GetFlaggedMessages(c *gin.Context) {
+	var req models.ConversationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	messages, err := h.messageService.GetFlaggedMessages(req.Page, req.PageSize)
+	if err != nil {
+		respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    messages,
+	})
+}
+
+// parseConversationID parses the conversation ID path parameter
+func parseConversationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid conversation ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondMessageError maps message service errors to HTTP responses
+func respondMessageError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not a participant in this conversation",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MLSHandler handles MLS feed administration requests
+type MLSHandler struct {
+	mlsService *services.MLSService
+}
+
+// NewMLSHandler creates a new MLS handler
+func NewMLSHandler(mlsService *services.MLSService) *MLSHandler {
+	return &MLSHandler{mlsService: mlsService}
+}
+
+// CreateFeed registers a new RESO Web API feed
+func (h *MLSHandler) CreateFeed(c *gin.Context) {
+	var req models.MLSFeedCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	feed, err := h.mlsService.CreateFeed(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "MLS feed created successfully",
+		Data:    feed,
+	})
+}
+
+// GetFeeds lists the registered feeds with their sync state
+func (h *MLSHandler) GetFeeds(c *gin.Context) {
+	feeds, err := h.mlsService.GetFeeds()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    feeds,
+	})
+}
+
+// SyncFeed runs an incremental sync of a feed and returns its summary
+func (h *MLSHandler) SyncFeed(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid feed ID",
+		})
+		return
+	}
+
+	result, err := h.mlsService.SyncFeed(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MLS feed synced successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ModerationHandler handles listing reports and the moderation queue
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(moderationService *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// ReportListing reports a listing or one of its media files as
+// inappropriate
+func (h *ModerationHandler) ReportListing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ModerationReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := h.moderationService.ReportListing(uint(propertyID), userID.(uint), &req); err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Report received",
+	})
+}
+
+// GetQueue lists the moderation queue (admin only)
+func (h *ModerationHandler) GetQueue(c *gin.Context) {
+	var req models.ModerationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	items, err := h.moderationService.GetQueue(&req)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// ApproveItem approves a moderation item (admin only)
+func (h *ModerationHandler) ApproveItem(c *gin.Context) {
+	h.reviewItem(c, h.moderationService.ApproveItem, "Content approved")
+}
+
+// RejectItem rejects a moderation item, hiding the content (admin only)
+func (h *ModerationHandler) RejectItem(c *gin.Context) {
+	h.reviewItem(c, h.moderationService.RejectItem, "Content rejected")
+}
+
+// reviewItem binds a review request and applies the admin's decision
+func (h *ModerationHandler) reviewItem(c *gin.Context, review func(id, adminID uint, req *models.ModerationReviewRequest) (*models.ModerationItemResponse, error), message string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseModerationID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ModerationReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	item, err := review(id, userID.(uint), &req)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    item,
+	})
+}
+
+// parseModerationID parses the moderation item ID path parameter
+func parseModerationID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid moderation item ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondModerationError maps moderation service errors to HTTP responses
+func respondModerationError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Moderation item, listing or media file not found",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
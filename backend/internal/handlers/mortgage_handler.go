@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MortgageHandler handles mortgage and affordability calculation requests
+type MortgageHandler struct {
+	mortgageService *services.MortgageService
+}
+
+// NewMortgageHandler creates a new mortgage handler
+func NewMortgageHandler(mortgageService *services.MortgageService) *MortgageHandler {
+	return &MortgageHandler{mortgageService: mortgageService}
+}
+
+// CalculateMortgage calculates a monthly payment breakdown and amortization
+// schedule
+func (h *MortgageHandler) CalculateMortgage(c *gin.Context) {
+	var req models.MortgageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	mortgage, err := h.mortgageService.CalculateMortgage(&req)
+	if err != nil {
+		respondMortgageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    mortgage,
+	})
+}
+
+// FindAffordableListings finds the highest affordable price and the listings
+// within it
+func (h *MortgageHandler) FindAffordableListings(c *gin.Context) {
+	var req models.AffordabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	affordability, err := h.mortgageService.FindAffordableListings(&req)
+	if err != nil {
+		respondMortgageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    affordability,
+	})
+}
+
+// respondMortgageError maps mortgage service errors to HTTP responses
+func respondMortgageError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Property not found",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OfferHandler handles offer requests
+type OfferHandler struct {
+	offerService *services.OfferService
+}
+
+// NewOfferHandler creates a new offer handler
+func NewOfferHandler(offerService *services.OfferService) *OfferHandler {
+	return &OfferHandler{offerService: offerService}
+}
+
+// SubmitOffer submits an offer on a property
+func (h *OfferHandler) SubmitOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.OfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	offer, err := h.offerService.SubmitOffer(uint(propertyID), userID.(uint), &req)
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Offer submitted successfully",
+		Data:    offer,
+	})
+}
+
+// GetPropertyOffers lists the offers on one of the agent's properties
+func (h *OfferHandler) GetPropertyOffers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	req, ok := bindOfferListRequest(c)
+	if !ok {
+		return
+	}
+
+	offers, err := h.offerService.GetPropertyOffers(uint(propertyID), userID.(uint), req)
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    offers,
+	})
+}
+
+// GetOffers lists the offers an agent received, or a buyer's own offers
+func (h *OfferHandler) GetOffers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	req, ok := bindOfferListRequest(c)
+	if !ok {
+		return
+	}
+
+	userRole, _ := c.Get("user_role")
+	asAgent := userRole == string(models.RoleAgent)
+
+	offers, err := h.offerService.GetOffers(userID.(uint), asAgent, req)
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    offers,
+	})
+}
+
+// GetOffer gets an offer with its negotiation history
+func (h *OfferHandler) GetOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOfferID(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.offerService.GetOffer(id, userID.(uint))
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    offer,
+	})
+}
+
+// CounterOffer answers an offer with a counter-offer
+func (h *OfferHandler) CounterOffer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOfferID(c)
+	if !ok {
+		return
+	}
+
+	var req models.OfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	offer, err := h.offerService.CounterOffer(id, userID.(uint), &req)
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Counter-offer submitted successfully",
+		Data:    offer,
+	})
+}
+
+// AcceptOffer accepts an offer and opens its transaction
+func (h *OfferHandler) AcceptOffer(c *gin.Context) {
+	h.respond(c, h.offerService.AcceptOffer, "Offer accepted")
+}
+
+// RejectOffer rejects an offer
+func (h *OfferHandler) RejectOffer(c *gin.Context) {
+	h.respond(c, h.offerService.RejectOffer, "Offer rejected")
+}
+
+// WithdrawOffer withdraws an offer
+func (h *OfferHandler) WithdrawOffer(c *gin.Context) {
+	h.respond(c, h.offerService.WithdrawOffer, "Offer withdrawn")
+}
+
+// respond runs a body-less offer action for the authenticated user
+func (h *OfferHandler) respond(c *gin.Context, action func(id, userID uint) (*models.OfferResponse, error), message string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOfferID(c)
+	if !ok {
+		return
+	}
+
+	offer, err := action(id, userID.(uint))
+	if err != nil {
+		respondOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    offer,
+	})
+}
+
+// bindOfferListRequest binds an offer list query with default pagination
+func bindOfferListRequest(c *gin.Context) (*models.OfferListRequest, bool) {
+	var req models.OfferListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return nil, false
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+	return &req, true
+}
+
+// parseOfferID parses the offer ID path parameter
+func parseOfferID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid offer ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondOfferError maps offer service errors to HTTP responses
+func respondOfferError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not a party to this offer",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"galactavista/pkg/ical"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OpenHouseHandler handles open house and RSVP requests
+type OpenHouseHandler struct {
+	openHouseService *services.OpenHouseService
+}
+
+// NewOpenHouseHandler creates a new open house handler
+func NewOpenHouseHandler(openHouseService *services.OpenHouseService) *OpenHouseHandler {
+	return &OpenHouseHandler{openHouseService: openHouseService}
+}
+
+// CreateOpenHouse schedules an open house for a property
+func (h *OpenHouseHandler) CreateOpenHouse(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.OpenHouseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	openHouse, err := h.openHouseService.CreateOpenHouse(uint(propertyID), userID.(uint), &req)
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Open house scheduled successfully",
+		Data:    openHouse,
+	})
+}
+
+// GetPropertyOpenHouses lists the upcoming open houses of a property
+func (h *OpenHouseHandler) GetPropertyOpenHouses(c *gin.Context) {
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	openHouses, err := h.openHouseService.GetPropertyOpenHouses(uint(propertyID), listingViewer(c))
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    openHouses,
+	})
+}
+
+// GetOpenHouse gets an open house by ID
+func (h *OpenHouseHandler) GetOpenHouse(c *gin.Context) {
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	openHouse, err := h.openHouseService.GetOpenHouse(id, listingViewer(c))
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    openHouse,
+	})
+}
+
+// UpdateOpenHouse reschedules or edits an open house
+func (h *OpenHouseHandler) UpdateOpenHouse(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.OpenHouseUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	openHouse, err := h.openHouseService.UpdateOpenHouse(id, userID.(uint), &req)
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Open house updated successfully",
+		Data:    openHouse,
+	})
+}
+
+// CancelOpenHouse cancels an open house
+func (h *OpenHouseHandler) CancelOpenHouse(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.openHouseService.CancelOpenHouse(id, userID.(uint)); err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Open house cancelled successfully",
+	})
+}
+
+// RSVP reserves a place at an open house for the authenticated user
+func (h *OpenHouseHandler) RSVP(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	var req models.OpenHouseRSVPRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	rsvp, err := h.openHouseService.RSVP(id, userID.(uint), &req)
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	message := "RSVP confirmed"
+	if rsvp.Status == models.RSVPStatusWaitlisted {
+		message = fmt.Sprintf("Open house is full; you are number %d on the waitlist", rsvp.WaitlistPosition)
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    rsvp,
+	})
+}
+
+// GetMyRSVP gets the authenticated user's RSVP for an open house
+func (h *OpenHouseHandler) GetMyRSVP(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	rsvp, err := h.openHouseService.GetMyRSVP(id, userID.(uint))
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rsvp,
+	})
+}
+
+// CancelRSVP cancels the authenticated user's RSVP
+func (h *OpenHouseHandler) CancelRSVP(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.openHouseService.CancelRSVP(id, userID.(uint)); err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "RSVP cancelled successfully",
+	})
+}
+
+// GetRSVPs lists the attendees and waitlist of an open house for its agent
+func (h *OpenHouseHandler) GetRSVPs(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	rsvps, err := h.openHouseService.GetRSVPs(id, userID.(uint))
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rsvps,
+	})
+}
+
+// GetEventCalendar serves an open house as an iCalendar (.ics) file
+func (h *OpenHouseHandler) GetEventCalendar(c *gin.Context) {
+	id, ok := parseOpenHouseID(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.openHouseService.EventCalendar(id, listingViewer(c))
+	if err != nil {
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="open-house-%d.ics"`, id))
+	c.Data(http.StatusOK, ical.ContentType, []byte(calendar.String()))
+}
+
+// GetAgentCalendar serves an agent's open houses as a subscribable iCalendar feed
+func (h *OpenHouseHandler) GetAgentCalendar(c *gin.Context) {
+	agentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid agent ID",
+		})
+		return
+	}
+
+	calendar, err := h.openHouseService.AgentCalendar(uint(agentID), listingViewer(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "Agent not found",
+			})
+			return
+		}
+		respondOpenHouseError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, ical.ContentType, []byte(calendar.String()))
+}
+
+// parseOpenHouseID parses the open house ID path parameter
+func parseOpenHouseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid open house ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondOpenHouseError maps open house service errors to HTTP responses
+func respondOpenHouseError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not the agent for this property",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
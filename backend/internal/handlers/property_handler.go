@@ -1,22 +1,26 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"galactavista/internal/models"
 	"galactavista/internal/services"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // PropertyHandler handles property requests
 type PropertyHandler struct {
 	propertyService *services.PropertyService
+	events          *services.EventRecorder
 }
 
 // NewPropertyHandler creates a new property handler
-func NewPropertyHandler(propertyService *services.PropertyService) *PropertyHandler {
-	return &PropertyHandler{propertyService: propertyService}
+func NewPropertyHandler(propertyService *services.PropertyService, events *services.EventRecorder) *PropertyHandler {
+	return &PropertyHandler{propertyService: propertyService, events: events}
 }
 
 // CreateProperty creates a new property
@@ -48,9 +52,10 @@ func (h *PropertyHandler) CreateProperty(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(property.Version))
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
-		Message: "Property created successfully",
+		Message: complianceMessage(property, "Property created successfully"),
 		Data:    property,
 	})
 }
@@ -67,7 +72,7 @@ func (h *PropertyHandler) GetProperty(c *gin.Context) {
 		return
 	}
 
-	property, err := h.propertyService.GetProperty(uint(id))
+	property, err := h.propertyService.GetVisibleProperty(uint(id), listingViewer(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.APIResponse{
 			Success: false,
@@ -76,10 +81,28 @@ func (h *PropertyHandler) GetProperty(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.APIResponse{
+	// The response includes open houses, the development, the agent and
+	// attributes, none of which change the listing's version, so the ETag
+	// covers the whole body as well as the version If-Match checks
+	body, err := json.Marshal(models.APIResponse{
 		Success: true,
 		Data:    property,
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if setContentETag(c, property.Version, body) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	h.events.Record(listingEvent(c, property.ID, models.ListingEventView))
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 }
 
 // UpdateProperty updates a property
@@ -103,6 +126,11 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req models.PropertyUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
@@ -112,8 +140,11 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 		return
 	}
 
-	property, err := h.propertyService.UpdateProperty(uint(id), &req, userID.(uint))
+	property, err := h.propertyService.UpdateProperty(uint(id), &req, userID.(uint), expectedVersion)
 	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -121,9 +152,10 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", versionETag(property.Version))
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Property updated successfully",
+		Message: complianceMessage(property, "Property updated successfully"),
 		Data:    property,
 	})
 }
@@ -149,8 +181,16 @@ func (h *PropertyHandler) DeleteProperty(c *gin.Context) {
 		return
 	}
 
-	err = h.propertyService.DeleteProperty(uint(id), userID.(uint))
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	err = h.propertyService.DeleteProperty(uint(id), userID.(uint), expectedVersion)
 	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -174,6 +214,7 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 		})
 		return
 	}
+	req.Attributes = c.QueryMap("attr")
 
 	// Set default pagination
 	if req.Page == 0 {
@@ -185,6 +226,9 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 
 	properties, err := h.propertyService.SearchProperties(&req)
 	if err != nil {
+		if respondSearchValidationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Error:   err.Error(),
@@ -198,6 +242,30 @@ func (h *PropertyHandler) SearchProperties(c *gin.Context) {
 	})
 }
 
+// respondSearchValidationError responds to invalid search filters, reporting
+// whether err was one
+func respondSearchValidationError(c *gin.Context, err error) bool {
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Error:   err.Error(),
+		Data:    validationErr.Errors,
+	})
+	return true
+}
+
+// listingViewer identifies the caller to the listing visibility rules.
+// Without a login only public listings are visible.
+func listingViewer(c *gin.Context) services.ListingViewer {
+	userID, _ := c.Get("user_id")
+	userRole, _ := c.Get("user_role")
+	id, _ := userID.(uint)
+	return services.ListingViewer{UserID: id, Admin: userRole == string(models.RoleAdmin)}
+}
+
 // GetPropertiesByAgent gets properties by agent ID
 func (h *PropertyHandler) GetPropertiesByAgent(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -240,3 +308,79 @@ func (h *PropertyHandler) GetPropertiesByAgent(c *gin.Context) {
 		Data:    properties,
 	})
 }
+
+// GetListingQuality scores an agent's listing and suggests improvements
+func (h *PropertyHandler) GetListingQuality(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	quality, err := h.propertyService.GetListingQuality(uint(id), userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "Property not found",
+			})
+		case err.Error() == "unauthorized":
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Success: false,
+				Error:   "Not authorized to view this listing's quality",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    quality,
+	})
+}
+
+// RefreshQualityScores recomputes every listing's quality score (admin only)
+func (h *PropertyHandler) RefreshQualityScores(c *gin.Context) {
+	result, err := h.propertyService.RefreshQualityScores()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Quality scores refreshed",
+		Data:    result,
+	})
+}
+
+// complianceMessage explains that a saved listing is held from publication
+// when its description has severe fair-housing warnings
+func complianceMessage(property *models.PropertyResponse, message string) string {
+	if property.Compliance.Status == models.ComplianceStatusBlocked {
+		return message + "; it will not be published until the fair-housing warnings are fixed or a broker overrides them"
+	}
+	return message
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"galactavista/pkg/tabular"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReportHandler handles admin business reports and report schedules
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetMetrics lists the metrics reports can be run on (admin only)
+func (h *ReportHandler) GetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    h.reportService.GetMetrics(),
+	})
+}
+
+// GetReport runs a report on a metric, as JSON or as a csv or xlsx
+// download (admin only)
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	var req models.ReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	var format tabular.Format
+	if req.Format != "" && req.Format != "json" {
+		var err error
+		if format, err = tabular.ParseFormat(req.Format); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	report, err := h.reportService.GetReport(models.ReportMetric(c.Param("metric")), &req)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	if format == "" {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Data:    report,
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.reportService.ExportReport(&buf, report, format); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	sendExport(c, "report-"+string(report.Metric), format, &buf)
+}
+
+// GetSchedules lists the report schedules (admin only)
+func (h *ReportHandler) GetSchedules(c *gin.Context) {
+	schedules, err := h.reportService.GetSchedules()
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    schedules,
+	})
+}
+
+// CreateSchedule schedules a report to be emailed (admin only)
+func (h *ReportHandler) CreateSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.reportService.CreateSchedule(userID.(uint), &req)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Report scheduled",
+		Data:    schedule,
+	})
+}
+
+// UpdateSchedule changes a report schedule (admin only)
+func (h *ReportHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := parseReportScheduleID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.reportService.UpdateSchedule(id, &req)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Report schedule updated",
+		Data:    schedule,
+	})
+}
+
+// DeleteSchedule removes a report schedule (admin only)
+func (h *ReportHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := parseReportScheduleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reportService.DeleteSchedule(id); err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Report schedule deleted",
+	})
+}
+
+// SendSchedule emails a scheduled report for its last full period right
+// away (admin only)
+func (h *ReportHandler) SendSchedule(c *gin.Context) {
+	id, ok := parseReportScheduleID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.reportService.SendSchedule(id)
+	if err != nil {
+		respondReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Report sent",
+		Data:    schedule,
+	})
+}
+
+// parseReportScheduleID parses the report schedule ID path parameter
+func parseReportScheduleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid report schedule ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondReportError maps report service errors to HTTP responses
+func respondReportError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Report schedule not found",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
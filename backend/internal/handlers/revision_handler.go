@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RevisionHandler handles property revision history requests
+type RevisionHandler struct {
+	revisionService *services.RevisionService
+}
+
+// NewRevisionHandler creates a new revision handler
+func NewRevisionHandler(revisionService *services.RevisionService) *RevisionHandler {
+	return &RevisionHandler{revisionService: revisionService}
+}
+
+// GetRevisions lists the revisions of a property
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	// Set default pagination
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	revisions, err := h.revisionService.GetRevisions(uint(id), userID.(uint), &req)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    revisions,
+	})
+}
+
+// GetRevision gets a single revision of a property
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, revision, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	rev, err := h.revisionService.GetRevision(id, revision, userID.(uint))
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rev,
+	})
+}
+
+// DiffRevisions compares two revisions of a property
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.PropertyRevisionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	diff, err := h.revisionService.DiffRevisions(uint(id), userID.(uint), &req)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    diff,
+	})
+}
+
+// RestoreRevision restores a property's content from a previous revision
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, revision, ok := parseRevisionParams(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	property, err := h.revisionService.RestoreRevision(id, revision, userID.(uint), expectedVersion)
+	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.Header("ETag", versionETag(property.Version))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Revision restored successfully",
+		Data:    property,
+	})
+}
+
+// parseRevisionParams parses the property ID and revision number path parameters
+func parseRevisionParams(c *gin.Context) (uint, int, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return 0, 0, false
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid revision number",
+		})
+		return 0, 0, false
+	}
+
+	return uint(id), revision, true
+}
+
+// respondRevisionError maps revision service errors to HTTP responses
+func respondRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Property not found",
+		})
+	case err.Error() == "revision not found":
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Revision not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not the agent for this property",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
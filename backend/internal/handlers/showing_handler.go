@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"galactavista/pkg/ical"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ShowingHandler handles private showing and agent availability requests
+type ShowingHandler struct {
+	showingService *services.ShowingService
+}
+
+// NewShowingHandler creates a new showing handler
+func NewShowingHandler(showingService *services.ShowingService) *ShowingHandler {
+	return &ShowingHandler{showingService: showingService}
+}
+
+// GetAvailability gets the authenticated agent's availability rules
+func (h *ShowingHandler) GetAvailability(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	availability, err := h.showingService.GetAvailability(userID.(uint))
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    availability,
+	})
+}
+
+// UpdateAvailability replaces the authenticated agent's availability rules
+func (h *ShowingHandler) UpdateAvailability(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.AgentAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	availability, err := h.showingService.UpdateAvailability(userID.(uint), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Availability updated successfully",
+		Data:    availability,
+	})
+}
+
+// RegenerateCalendarToken replaces the agent's showing calendar feed URL
+func (h *ShowingHandler) RegenerateCalendarToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	availability, err := h.showingService.RegenerateCalendarToken(userID.(uint))
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Calendar feed URL regenerated successfully",
+		Data:    availability,
+	})
+}
+
+// AddBlackout blocks showings on a range of dates
+func (h *ShowingHandler) AddBlackout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.AvailabilityBlackoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	blackout, err := h.showingService.AddBlackout(userID.(uint), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Blackout added successfully",
+		Data:    blackout,
+	})
+}
+
+// DeleteBlackout removes one of the agent's blackouts
+func (h *ShowingHandler) DeleteBlackout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid blackout ID",
+		})
+		return
+	}
+
+	if err := h.showingService.DeleteBlackout(userID.(uint), uint(id)); err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Blackout removed successfully",
+	})
+}
+
+// GetSlots lists the bookable showing slots of a property
+func (h *ShowingHandler) GetSlots(c *gin.Context) {
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ShowingSlotsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	slots, err := h.showingService.GetSlots(uint(propertyID), listingViewer(c), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    slots,
+	})
+}
+
+// RequestShowing books a private showing of a property
+func (h *ShowingHandler) RequestShowing(c *gin.Context) {
+	if _, exists := c.Get("user_id"); !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	propertyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var req models.ShowingCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	showing, err := h.showingService.RequestShowing(uint(propertyID), listingViewer(c), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Showing requested successfully",
+		Data:    showing,
+	})
+}
+
+// GetShowings lists the authenticated user's showings. Agents see the
+// showings of their listings; other users see their own requests.
+func (h *ShowingHandler) GetShowings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.ShowingListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	userRole, _ := c.Get("user_role")
+	asAgent := userRole == string(models.RoleAgent)
+
+	showings, err := h.showingService.GetShowings(userID.(uint), asAgent, &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    showings,
+	})
+}
+
+// GetShowing gets a showing by ID
+func (h *ShowingHandler) GetShowing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseShowingID(c)
+	if !ok {
+		return
+	}
+
+	showing, err := h.showingService.GetShowing(id, userID.(uint))
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    showing,
+	})
+}
+
+// ConfirmShowing confirms a showing request
+func (h *ShowingHandler) ConfirmShowing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseShowingID(c)
+	if !ok {
+		return
+	}
+
+	showing, err := h.showingService.ConfirmShowing(id, userID.(uint))
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Showing confirmed successfully",
+		Data:    showing,
+	})
+}
+
+// RescheduleShowing moves a showing to another slot
+func (h *ShowingHandler) RescheduleShowing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseShowingID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShowingRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	showing, err := h.showingService.RescheduleShowing(id, userID.(uint), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Showing rescheduled successfully",
+		Data:    showing,
+	})
+}
+
+// CancelShowing cancels a showing
+func (h *ShowingHandler) CancelShowing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseShowingID(c)
+	if !ok {
+		return
+	}
+
+	var req models.ShowingCancelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Invalid request data: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	showing, err := h.showingService.CancelShowing(id, userID.(uint), &req)
+	if err != nil {
+		respondShowingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Showing cancelled successfully",
+		Data:    showing,
+	})
+}
+
+// GetAgentCalendar serves an agent's showings as a subscribable iCalendar
+// feed, authenticated by the calendar token
+func (h *ShowingHandler) GetAgentCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	calendar, err := h.showingService.AgentCalendar(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Error:   "Calendar not found",
+			})
+			return
+		}
+		respondShowingError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, ical.ContentType, []byte(calendar.String()))
+}
+
+// parseShowingID parses the showing ID path parameter
+func parseShowingID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid showing ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondShowingError maps showing service errors to HTTP responses
+func respondShowingError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not a participant in this showing",
+		})
+	case err.Error() == "requested time is not available":
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Error:   "The requested time is no longer available",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
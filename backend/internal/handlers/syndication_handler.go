@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyndicationHandler handles outbound listing feeds and partner administration
+type SyndicationHandler struct {
+	syndicationService *services.SyndicationService
+}
+
+// NewSyndicationHandler creates a new syndication handler
+func NewSyndicationHandler(syndicationService *services.SyndicationService) *SyndicationHandler {
+	return &SyndicationHandler{syndicationService: syndicationService}
+}
+
+// GetRESOFeed serves the RESO-compatible JSON feed
+func (h *SyndicationHandler) GetRESOFeed(c *gin.Context) {
+	h.serveFeed(c, models.FeedFormatRESO)
+}
+
+// GetXMLFeed serves the generic XML listing feed
+func (h *SyndicationHandler) GetXMLFeed(c *gin.Context) {
+	h.serveFeed(c, models.FeedFormatXML)
+}
+
+// GetRSSFeed serves the RSS feed of new listings
+func (h *SyndicationHandler) GetRSSFeed(c *gin.Context) {
+	h.serveFeed(c, models.FeedFormatRSS)
+}
+
+// GetAtomFeed serves the Atom feed of new listings
+func (h *SyndicationHandler) GetAtomFeed(c *gin.Context) {
+	h.serveFeed(c, models.FeedFormatAtom)
+}
+
+// serveFeed renders a partner feed, answering conditional requests with 304
+func (h *SyndicationHandler) serveFeed(c *gin.Context, format models.FeedFormat) {
+	partner, err := h.syndicationService.GetPartnerByFeedKey(c.Param("key"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Feed not found",
+		})
+		return
+	}
+
+	var req models.FeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	state, err := h.syndicationService.GetFeedState(partner, format, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("ETag", state.ETag)
+	c.Header("Last-Modified", state.LastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-cache")
+
+	if feedNotModified(c.Request, state) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	body, contentType, err := h.syndicationService.RenderFeed(partner, format, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// CreatePartner creates a syndication partner
+func (h *SyndicationHandler) CreatePartner(c *gin.Context) {
+	var req models.SyndicationPartnerCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	partner, err := h.syndicationService.CreatePartner(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Syndication partner created successfully",
+		Data:    partner,
+	})
+}
+
+// UpdatePartner updates a syndication partner
+func (h *SyndicationHandler) UpdatePartner(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid partner ID",
+		})
+		return
+	}
+
+	var req models.SyndicationPartnerUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	partner, err := h.syndicationService.UpdatePartner(uint(id), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Syndication partner updated successfully",
+		Data:    partner,
+	})
+}
+
+// GetPartners lists syndication partners
+func (h *SyndicationHandler) GetPartners(c *gin.Context) {
+	partners, err := h.syndicationService.GetPartners()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    partners,
+	})
+}
+
+// feedNotModified evaluates If-None-Match and If-Modified-Since against the feed state
+func feedNotModified(r *http.Request, state *services.FeedState) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == state.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		if since, err := time.Parse(http.TimeFormat, ifModifiedSince); err == nil {
+			return !state.LastModified.After(since)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TransactionHandler handles transaction and milestone requests
+type TransactionHandler struct {
+	transactionService *services.TransactionService
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(transactionService *services.TransactionService) *TransactionHandler {
+	return &TransactionHandler{transactionService: transactionService}
+}
+
+// GetTransactions lists the authenticated user's transactions
+func (h *TransactionHandler) GetTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.TransactionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.PageSize == 0 {
+		req.PageSize = 10
+	}
+
+	transactions, err := h.transactionService.GetTransactions(userID.(uint), &req)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    transactions,
+	})
+}
+
+// GetTransaction gets a transaction with its milestone checklist
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTransactionID(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.GetTransaction(id, userID.(uint))
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    transaction,
+	})
+}
+
+// AddMilestone adds a custom milestone to a transaction
+func (h *TransactionHandler) AddMilestone(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTransactionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MilestoneCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.transactionService.AddMilestone(id, userID.(uint), &req)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Milestone added successfully",
+		Data:    transaction,
+	})
+}
+
+// UpdateMilestone updates a milestone's due date or completion
+func (h *TransactionHandler) UpdateMilestone(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTransactionID(c)
+	if !ok {
+		return
+	}
+
+	milestoneID, err := strconv.ParseUint(c.Param("milestoneId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid milestone ID",
+		})
+		return
+	}
+
+	var req models.MilestoneUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	transaction, err := h.transactionService.UpdateMilestone(id, uint(milestoneID), userID.(uint), &req)
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Milestone updated successfully",
+		Data:    transaction,
+	})
+}
+
+// CloseTransaction closes a transaction and marks the listing sold (agent only)
+func (h *TransactionHandler) CloseTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTransactionID(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.CloseTransaction(id, userID.(uint))
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Transaction closed",
+		Data:    transaction,
+	})
+}
+
+// CancelTransaction cancels a transaction and relists the property (agent only)
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTransactionID(c)
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.CancelTransaction(id, userID.(uint))
+	if err != nil {
+		respondTransactionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Transaction cancelled",
+		Data:    transaction,
+	})
+}
+
+// parseTransactionID parses the transaction ID path parameter
+func parseTransactionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid transaction ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondTransactionError maps transaction service errors to HTTP responses
+func respondTransactionError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Not found",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not a party to this transaction",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
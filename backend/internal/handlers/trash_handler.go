@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashHandler handles trash listing, restore and purge requests
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// GetTrash lists the authenticated agent's deleted items
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	var req models.TrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if req.Type != "" && !req.Type.IsValid() {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid trash item type",
+		})
+		return
+	}
+
+	// Set default pagination
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
+	trash, err := h.trashService.GetTrash(userID.(uint), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    trash,
+	})
+}
+
+// RestoreProperty restores a deleted property with its media and tours
+func (h *TrashHandler) RestoreProperty(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	property, err := h.trashService.RestoreProperty(id, userID.(uint))
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.Header("ETag", versionETag(property.Version))
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Property restored successfully",
+		Data:    property,
+	})
+}
+
+// RestoreMediaFile restores a deleted media file
+func (h *TrashHandler) RestoreMediaFile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreMediaFile(id, userID.(uint)); err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Media file restored successfully",
+	})
+}
+
+// RestoreVRTour restores a deleted VR tour
+func (h *TrashHandler) RestoreVRTour(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "User not authenticated",
+		})
+		return
+	}
+
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreVRTour(id, userID.(uint)); err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "VR tour restored successfully",
+	})
+}
+
+// PurgeProperty permanently deletes a property in the trash (admin only)
+func (h *TrashHandler) PurgeProperty(c *gin.Context) {
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.trashService.PurgeProperty(id)
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Property purged successfully",
+		Data:    result,
+	})
+}
+
+// PurgeMediaFile permanently deletes a media file in the trash (admin only)
+func (h *TrashHandler) PurgeMediaFile(c *gin.Context) {
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.trashService.PurgeMediaFile(id)
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Media file purged successfully",
+		Data:    result,
+	})
+}
+
+// PurgeVRTour permanently deletes a VR tour in the trash (admin only)
+func (h *TrashHandler) PurgeVRTour(c *gin.Context) {
+	id, ok := parseTrashID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.trashService.PurgeVRTour(id)
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "VR tour purged successfully",
+		Data:    result,
+	})
+}
+
+// PurgeExpired runs the retention purge immediately (admin only)
+func (h *TrashHandler) PurgeExpired(c *gin.Context) {
+	result, err := h.trashService.PurgeExpired()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Expired trash purged successfully",
+		Data:    result,
+	})
+}
+
+// parseTrashID parses the trashed item ID path parameter
+func parseTrashID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondTrashError maps trash service errors to HTTP responses
+func respondTrashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Item not found in trash",
+		})
+	case err.Error() == "unauthorized":
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Error:   "You are not the agent for this property",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
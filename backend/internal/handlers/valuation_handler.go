@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"galactavista/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ValuationHandler handles comparable sales valuation requests
+type ValuationHandler struct {
+	valuationService *services.ValuationService
+}
+
+// NewValuationHandler creates a new valuation handler
+func NewValuationHandler(valuationService *services.ValuationService) *ValuationHandler {
+	return &ValuationHandler{valuationService: valuationService}
+}
+
+// GetPropertyValuation estimates a property's value from comparable sales
+func (h *ValuationHandler) GetPropertyValuation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid property ID",
+		})
+		return
+	}
+
+	var options models.ValuationOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	valuation, err := h.valuationService.ValueProperty(uint(id), &options)
+	if err != nil {
+		respondValuationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    valuation,
+	})
+}
+
+// EstimateValue estimates the value of a property described by its address
+// and attributes
+func (h *ValuationHandler) EstimateValue(c *gin.Context) {
+	var req models.ValuationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	valuation, err := h.valuationService.EstimateValue(&req)
+	if err != nil {
+		respondValuationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    valuation,
+	})
+}
+
+// respondValuationError maps valuation service errors to HTTP responses
+func respondValuationError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Data:    validationErr.Errors,
+		})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Property not found",
+		})
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+	}
+}
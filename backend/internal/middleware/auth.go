@@ -99,6 +99,21 @@ func (m *AuthMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// AuthenticateStream validates the JWT like Authenticate but also accepts it
+// in the access_token query parameter, since browser EventSource requests
+// cannot set headers
+func (m *AuthMiddleware) AuthenticateStream() gin.HandlerFunc {
+	authenticate := m.Authenticate()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		authenticate(c)
+	}
+}
+
 // OptionalAuth middleware that doesn't require authentication but sets context if token is provided
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+)
+
+// Attribute is an entry of the managed property attribute catalog. Values
+// are typed and an attribute can be limited to some property types.
+type Attribute struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Key           string         `json:"key" gorm:"uniqueIndex;not null"`
+	Label         string         `json:"label" gorm:"not null"`
+	Category      string         `json:"category" gorm:"not null;index"`
+	Type          AttributeType  `json:"type" gorm:"not null"`
+	Options       []string       `json:"options" gorm:"type:json"`
+	PropertyTypes []PropertyType `json:"property_types" gorm:"type:json"`
+	Aliases       []string       `json:"aliases" gorm:"type:json"`
+	SortOrder     int            `json:"sort_order"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// AttributeType represents the value type of an attribute
+type AttributeType string
+
+const (
+	AttributeTypeBool AttributeType = "bool"
+	AttributeTypeInt  AttributeType = "int"
+	AttributeTypeEnum AttributeType = "enum"
+	AttributeTypeText AttributeType = "text"
+)
+
+// IsValid reports whether the attribute type is one of the known types
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeBool, AttributeTypeInt, AttributeTypeEnum, AttributeTypeText:
+		return true
+	}
+	return false
+}
+
+// AppliesTo reports whether the attribute can be set on a property type. An
+// attribute without property types applies to all of them.
+func (a *Attribute) AppliesTo(propertyType PropertyType) bool {
+	if len(a.PropertyTypes) == 0 {
+		return true
+	}
+	for _, t := range a.PropertyTypes {
+		if t == propertyType {
+			return true
+		}
+	}
+	return false
+}
+
+// PropertyAttribute is the value of a catalog attribute on a property. The
+// value column used depends on the attribute type; enum and text values
+// share TextValue.
+type PropertyAttribute struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	PropertyID  uint      `json:"property_id" gorm:"not null;uniqueIndex:idx_property_attributes_attribute"`
+	AttributeID uint      `json:"attribute_id" gorm:"not null;uniqueIndex:idx_property_attributes_attribute;index"`
+	Attribute   Attribute `json:"attribute" gorm:"foreignKey:AttributeID"`
+	BoolValue   *bool     `json:"bool_value"`
+	IntValue    *int      `json:"int_value"`
+	TextValue   *string   `json:"text_value"`
+}
+
+// Value returns the typed value of a property attribute
+func (v *PropertyAttribute) Value() interface{} {
+	switch {
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	case v.IntValue != nil:
+		return *v.IntValue
+	case v.TextValue != nil:
+		return *v.TextValue
+	}
+	return nil
+}
+
+// AttributeRequest creates or replaces a catalog attribute. Keys are
+// lowercase snake_case; enum attributes need their options.
+type AttributeRequest struct {
+	Key           string         `json:"key" binding:"required,max=50"`
+	Label         string         `json:"label" binding:"required,max=100"`
+	Category      string         `json:"category" binding:"required,max=50"`
+	Type          AttributeType  `json:"type" binding:"required"`
+	Options       []string       `json:"options"`
+	PropertyTypes []PropertyType `json:"property_types"`
+	Aliases       []string       `json:"aliases"`
+	SortOrder     int            `json:"sort_order"`
+}
+
+// AttributeListRequest filters the attribute catalog
+type AttributeListRequest struct {
+	PropertyType *PropertyType `form:"property_type"`
+	Category     string        `form:"category"`
+}
+
+// AttributeValue is an attribute value shown on a property
+type AttributeValue struct {
+	Key      string        `json:"key"`
+	Label    string        `json:"label"`
+	Category string        `json:"category"`
+	Type     AttributeType `json:"type"`
+	Value    interface{}   `json:"value"`
+}
+
+// UnmatchedFeature is a free-form feature string the migration could not
+// map onto the catalog, with the number of listings using it
+type UnmatchedFeature struct {
+	Feature  string `json:"feature"`
+	Listings int    `json:"listings"`
+}
+
+// FeatureMigrationResponse reports a migration of free-form features onto
+// catalog attributes
+type FeatureMigrationResponse struct {
+	PropertiesScanned int                `json:"properties_scanned"`
+	PropertiesUpdated int                `json:"properties_updated"`
+	FeaturesMigrated  int                `json:"features_migrated"`
+	Unmatched         []UnmatchedFeature `json:"unmatched"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// Brokerage represents a real estate brokerage that agents belong to.
+// Leads can be reassigned between the agents of a brokerage.
+type Brokerage struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BrokerageCreateRequest represents brokerage creation request
+type BrokerageCreateRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// BrokerageMembershipRequest sets or clears an agent's brokerage. IsBroker
+// makes the agent a broker of the brokerage, who can override fair-housing
+// blocks on its listings.
+type BrokerageMembershipRequest struct {
+	BrokerageID *uint `json:"brokerage_id"`
+	IsBroker    bool  `json:"is_broker"`
+}
+
+// BrokerageResponse represents brokerage response
+type BrokerageResponse struct {
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Agents    []UserResponse `json:"agents"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
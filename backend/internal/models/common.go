@@ -12,6 +12,27 @@ type APIResponse struct {
 	Error   string      `json:"error,omitempty"`
 }
 
+// FieldError represents a validation error for a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldConflict describes a field whose stored value no longer matches what
+// the client expected when it submitted a conditional update
+type FieldConflict struct {
+	Field        string      `json:"field"`
+	YourValue    interface{} `json:"your_value"`
+	CurrentValue interface{} `json:"current_value"`
+}
+
+// VersionConflictDetails is returned with 412 Precondition Failed responses
+type VersionConflictDetails struct {
+	ExpectedVersion int             `json:"expected_version"`
+	CurrentVersion  int             `json:"current_version"`
+	Conflicts       []FieldConflict `json:"conflicts"`
+}
+
 // PaginationRequest represents pagination parameters
 type PaginationRequest struct {
 	Page     int `json:"page" form:"page" binding:"min=1"`
@@ -39,6 +60,54 @@ type PropertySearchRequest struct {
 	City         string          `json:"city" form:"city"`
 	State        string          `json:"state" form:"state"`
 	Status       *PropertyStatus `json:"status" form:"status"`
+
+	// Rental filters. Pets matches rentals that allow the given pet ("cats"
+	// or "dogs"); AvailableBy matches rentals available on or before a date.
+	ListingType    *ListingType `json:"listing_type" form:"listing_type"`
+	MinRent        *float64     `json:"min_rent" form:"min_rent"`
+	MaxRent        *float64     `json:"max_rent" form:"max_rent"`
+	Pets           string       `json:"pets" form:"pets" binding:"omitempty,oneof=cats dogs"`
+	AvailableBy    *time.Time   `json:"available_by" form:"available_by" time_format:"2006-01-02"`
+	MaxLeaseMonths *int         `json:"max_lease_months" form:"max_lease_months"`
+
+	// Commercial and land filters match those listings' own fields; Zoning
+	// matches either and Utilities lists comma-separated utilities a lot
+	// must all have
+	Zoning              string               `json:"zoning" form:"zoning"`
+	MinUsableSquareFeet *int                 `json:"min_usable_square_feet" form:"min_usable_square_feet"`
+	MinCapRate          *float64             `json:"min_cap_rate" form:"min_cap_rate"`
+	MaxCapRate          *float64             `json:"max_cap_rate" form:"max_cap_rate"`
+	MinNOI              *float64             `json:"min_net_operating_income" form:"min_net_operating_income"`
+	CommercialLeaseType *CommercialLeaseType `json:"commercial_lease_type" form:"commercial_lease_type"`
+	MinAcreage          *float64             `json:"min_acreage" form:"min_acreage"`
+	MaxAcreage          *float64             `json:"max_acreage" form:"max_acreage"`
+	Utilities           string               `json:"utilities" form:"utilities"`
+	Topography          *Topography          `json:"topography" form:"topography"`
+
+	// Attributes filters on catalog attributes by key, given as
+	// attr[key]=value: bool attributes match the value, int attributes match
+	// at least the value, enum attributes match any of comma-separated
+	// options and text attributes match a substring
+	Attributes map[string]string `json:"attributes" form:"-"`
+
+	// DevelopmentID limits results to the units of a development.
+	// RollupDevelopments collapses the matching units of each development
+	// into one result, its lowest priced match, with a rollup summary.
+	DevelopmentID      *uint `json:"development_id" form:"development_id"`
+	RollupDevelopments bool  `json:"rollup_developments" form:"rollup_developments"`
+
+	// Open house filters match listings with an upcoming open house. Dates
+	// are compared with the event's local date; the weekend filter uses the
+	// weekend as seen in OpenHouseTimeZone (UTC by default).
+	HasOpenHouse      bool       `json:"has_open_house" form:"has_open_house"`
+	OpenHouseFrom     *time.Time `json:"open_house_from" form:"open_house_from" time_format:"2006-01-02"`
+	OpenHouseTo       *time.Time `json:"open_house_to" form:"open_house_to" time_format:"2006-01-02"`
+	OpenHouseWeekend  bool       `json:"open_house_weekend" form:"open_house_weekend"`
+	OpenHouseTimeZone string     `json:"open_house_tz" form:"open_house_tz"`
+
+	// Sort orders the results. The default, relevance, boosts listings with
+	// higher quality scores and then shows newer listings first.
+	Sort string `json:"sort" form:"sort" binding:"omitempty,oneof=relevance newest price_asc price_desc"`
 }
 
 // VRExperience represents a VR experience for a property
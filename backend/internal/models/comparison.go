@@ -0,0 +1,58 @@
+package models
+
+// PropertyCompareRequest represents a property comparison request. IDs is a
+// comma-separated list of 2 to 5 property IDs.
+type PropertyCompareRequest struct {
+	IDs string `form:"ids" binding:"required"`
+}
+
+// ComparedProperty is one column of a property comparison. PricePerSqFt and
+// Age are nil when the square footage or year built is unknown.
+type ComparedProperty struct {
+	ID           uint           `json:"id"`
+	Title        string         `json:"title"`
+	Address      string         `json:"address"`
+	City         string         `json:"city"`
+	State        string         `json:"state"`
+	ZipCode      string         `json:"zip_code"`
+	PropertyType PropertyType   `json:"property_type"`
+	ListingType  ListingType    `json:"listing_type"`
+	Status       PropertyStatus `json:"status"`
+	Price        float64        `json:"price"`
+	MonthlyRent  *float64       `json:"monthly_rent"`
+	Bedrooms     int            `json:"bedrooms"`
+	Bathrooms    float64        `json:"bathrooms"`
+	SquareFeet   int            `json:"square_feet"`
+	YearBuilt    int            `json:"year_built"`
+	LotSize      float64        `json:"lot_size"`
+	PricePerSqFt *float64       `json:"price_per_sqft"`
+	Age          *int           `json:"age"`
+	Image        string         `json:"image"`
+	VRModelURL   string         `json:"vr_model_url"`
+	Features     []string       `json:"features"`
+}
+
+// ComparisonRow holds one field's values across the compared properties, in
+// the order the properties were requested. Differs is set when the values
+// are not all equal.
+type ComparisonRow struct {
+	Field   string        `json:"field"`
+	Values  []interface{} `json:"values"`
+	Differs bool          `json:"differs"`
+}
+
+// FeatureComparison reports which compared properties have a feature
+type FeatureComparison struct {
+	Feature string `json:"feature"`
+	Present []bool `json:"present"`
+	Differs bool   `json:"differs"`
+}
+
+// PropertyComparisonResponse represents a side-by-side property comparison
+type PropertyComparisonResponse struct {
+	Properties     []ComparedProperty  `json:"properties"`
+	Rows           []ComparisonRow     `json:"rows"`
+	Features       []FeatureComparison `json:"features"`
+	CommonFeatures []string            `json:"common_features"`
+	DifferingRows  []string            `json:"differing_rows"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+)
+
+// ComplianceStatus represents whether a listing's description passed the
+// fair-housing linter. Blocked listings are saved but not published until
+// the description is fixed or a broker overrides the block.
+type ComplianceStatus string
+
+const (
+	ComplianceStatusClear      ComplianceStatus = "clear"
+	ComplianceStatusBlocked    ComplianceStatus = "blocked"
+	ComplianceStatusOverridden ComplianceStatus = "overridden"
+)
+
+// ListingCompliance records the fair-housing status of a listing and any
+// broker override. Warnings are only filled in when the description was just
+// linted.
+type ListingCompliance struct {
+	Status         ComplianceStatus    `json:"status" gorm:"not null;default:'clear';index"`
+	OverrideByID   *uint               `json:"override_by_id,omitempty"`
+	OverrideReason string              `json:"override_reason,omitempty"`
+	OverriddenAt   *time.Time          `json:"overridden_at,omitempty"`
+	Warnings       []ComplianceWarning `json:"warnings,omitempty" gorm:"-"`
+}
+
+// ComplianceWarning is a fair-housing concern the linter found in a
+// description. Start and End are character offsets of the flagged phrase,
+// End exclusive.
+type ComplianceWarning struct {
+	RuleID       string   `json:"rule_id"`
+	Category     string   `json:"category"`
+	Severity     string   `json:"severity"`
+	Phrase       string   `json:"phrase"`
+	Message      string   `json:"message"`
+	Alternatives []string `json:"alternatives"`
+	Start        int      `json:"start"`
+	End          int      `json:"end"`
+}
+
+// ComplianceLintRequest represents a description lint request
+type ComplianceLintRequest struct {
+	Description string `json:"description" binding:"max=20000"`
+}
+
+// ComplianceLintResponse represents the linter's findings for a description
+type ComplianceLintResponse struct {
+	Warnings []ComplianceWarning `json:"warnings"`
+	Blocked  bool                `json:"blocked"`
+}
+
+// ComplianceOverrideRequest represents a broker's override of a blocked
+// listing
+type ComplianceOverrideRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// BlockedListingResponse represents a listing held for fair-housing review
+// in its brokerage
+type BlockedListingResponse struct {
+	ID        uint                `json:"id"`
+	Title     string              `json:"title"`
+	AgentID   uint                `json:"agent_id"`
+	AgentName string              `json:"agent_name"`
+	Warnings  []ComplianceWarning `json:"warnings"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
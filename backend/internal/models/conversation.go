@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+)
+
+// Conversation is a message thread between a buyer and the listing agent
+// about one property. Each side's read position is the ID of the last
+// message they have read.
+type Conversation struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	PropertyID      uint       `json:"property_id" gorm:"not null;uniqueIndex:idx_conversations_property_buyer"`
+	Property        Property   `json:"property" gorm:"foreignKey:PropertyID"`
+	BuyerID         uint       `json:"buyer_id" gorm:"not null;uniqueIndex:idx_conversations_property_buyer"`
+	Buyer           User       `json:"buyer" gorm:"foreignKey:BuyerID"`
+	AgentID         uint       `json:"agent_id" gorm:"not null;index"`
+	Agent           User       `json:"agent" gorm:"foreignKey:AgentID"`
+	BuyerLastReadID uint       `json:"buyer_last_read_id" gorm:"not null;default:0"`
+	AgentLastReadID uint       `json:"agent_last_read_id" gorm:"not null;default:0"`
+	LastMessageAt   *time.Time `json:"last_message_at" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Message is a message in a conversation. Flagged messages were accepted
+// but marked by a moderator for review.
+type Message struct {
+	ID             uint                `json:"id" gorm:"primaryKey"`
+	ConversationID uint                `json:"conversation_id" gorm:"not null;index"`
+	SenderID       uint                `json:"sender_id" gorm:"not null"`
+	Body           string              `json:"body" gorm:"type:text"`
+	Attachments    []MessageAttachment `json:"attachments" gorm:"type:json;serializer:json"`
+	Flagged        bool                `json:"flagged" gorm:"not null;default:false;index"`
+	FlagReason     string              `json:"flag_reason,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// MessageAttachment is a file attached to a message. Attachments are
+// served only to the conversation's participants.
+type MessageAttachment struct {
+	FileName     string `json:"file_name"`
+	OriginalName string `json:"original_name"`
+	FileType     string `json:"file_type"`
+	FileSize     int64  `json:"file_size"`
+}
+
+// ConversationCreateRequest starts a conversation with a property's agent
+type ConversationCreateRequest struct {
+	Body string `json:"body" binding:"max=5000"`
+}
+
+// ConversationListRequest represents a conversation list request
+type ConversationListRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// MessageCreateRequest represents a text message. Attachments are sent as
+// multipart "attachments" files alongside a "body" form field.
+type MessageCreateRequest struct {
+	Body string `json:"body" form:"body" binding:"max=5000"`
+}
+
+// MessageHistoryRequest pages backwards through a conversation's messages.
+// Before is the oldest message ID already loaded.
+type MessageHistoryRequest struct {
+	Before uint `form:"before"`
+	Limit  int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// MarkReadRequest marks a conversation read up to MessageID, or up to the
+// latest message when it is zero
+type MarkReadRequest struct {
+	MessageID uint `json:"message_id"`
+}
+
+// MessageResponse represents message response. Read is set on the viewer's
+// own messages once the other participant has read them.
+type MessageResponse struct {
+	ID             uint                        `json:"id"`
+	ConversationID uint                        `json:"conversation_id"`
+	SenderID       uint                        `json:"sender_id"`
+	Body           string                      `json:"body"`
+	Attachments    []MessageAttachmentResponse `json:"attachments"`
+	Read           bool                        `json:"read"`
+	Flagged        bool                        `json:"flagged,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at"`
+}
+
+// MessageAttachmentResponse represents message attachment response
+type MessageAttachmentResponse struct {
+	FileName string `json:"file_name"`
+	FileURL  string `json:"file_url"`
+	FileType string `json:"file_type"`
+	FileSize int64  `json:"file_size"`
+}
+
+// MessageHistoryResponse represents a page of messages, oldest first
+type MessageHistoryResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	HasMore  bool              `json:"has_more"`
+}
+
+// ConversationResponse represents conversation response from the viewer's
+// point of view
+type ConversationResponse struct {
+	ID            uint             `json:"id"`
+	PropertyID    uint             `json:"property_id"`
+	PropertyTitle string           `json:"property_title"`
+	Participant   UserResponse     `json:"participant"`
+	LastMessage   *MessageResponse `json:"last_message,omitempty"`
+	UnreadCount   int64            `json:"unread_count"`
+	LastMessageAt *time.Time       `json:"last_message_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// UnreadCountResponse represents the viewer's unread message totals
+type UnreadCountResponse struct {
+	Messages      int64 `json:"messages"`
+	Conversations int64 `json:"conversations"`
+}
+
+// MessageEvent is a real-time event delivered to a conversation
+// participant: a new message, or a read receipt from the other side
+type MessageEvent struct {
+	Type           string           `json:"type"`
+	ConversationID uint             `json:"conversation_id"`
+	Message        *MessageResponse `json:"message,omitempty"`
+	ReaderID       uint             `json:"reader_id,omitempty"`
+	LastReadID     uint             `json:"last_read_id,omitempty"`
+}
+
+// Message event types
+const (
+	MessageEventMessage = "message"
+	MessageEventRead    = "read"
+)
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// DashboardStats summarizes an agent's listings and pipeline. Active
+// listings are those available or pending; days on market run from when a
+// listing was created, to today for active listings and to the sale for
+// sold ones. JSON names follow the web app's dashboard types.
+type DashboardStats struct {
+	TotalProperties     int       `json:"totalProperties"`
+	AvailableProperties int       `json:"availableProperties"`
+	PendingProperties   int       `json:"pendingProperties"`
+	SoldProperties      int       `json:"soldProperties"`
+	RentedProperties    int       `json:"rentedProperties"`
+	TotalViews          int64     `json:"totalViews"`
+	TotalInquiries      int       `json:"totalInquiries"`
+	NewLeads            int       `json:"newLeads"`
+	UpcomingShowings    int       `json:"upcomingShowings"`
+	AverageDaysOnMarket float64   `json:"averageDaysOnMarket"`
+	AverageDaysToSell   float64   `json:"averageDaysToSell"`
+	RefreshedAt         time.Time `json:"refreshedAt"`
+}
+
+// AgentDashboardData is an agent's dashboard: the statistics with the
+// agent's most recently created listings and inquiries
+type AgentDashboardData struct {
+	Stats            DashboardStats     `json:"stats"`
+	RecentProperties []PropertyResponse `json:"recentProperties"`
+	RecentInquiries  []LeadResponse     `json:"recentInquiries"`
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Development is a building or development whose units are listed as
+// individual properties. Units take their address and location from the
+// development and fall back to its media and VR tour.
+type Development struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description"`
+	Address     string         `json:"address" gorm:"not null"`
+	City        string         `json:"city" gorm:"not null"`
+	State       string         `json:"state" gorm:"not null"`
+	ZipCode     string         `json:"zip_code" gorm:"not null"`
+	Country     string         `json:"country" gorm:"not null;default:'US'"`
+	Latitude    *float64       `json:"latitude"`
+	Longitude   *float64       `json:"longitude"`
+	YearBuilt   int            `json:"year_built"`
+	Amenities   []string       `json:"amenities" gorm:"type:json"`
+	Images      []string       `json:"images" gorm:"type:json"`
+	VRModelURL  string         `json:"vr_model_url"`
+	AgentID     uint           `json:"agent_id" gorm:"not null;index"`
+	Agent       User           `json:"agent" gorm:"foreignKey:AgentID"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// DevelopmentCreateRequest represents development creation request
+type DevelopmentCreateRequest struct {
+	Name        string   `json:"name" binding:"required,max=200"`
+	Description string   `json:"description"`
+	Address     string   `json:"address" binding:"required"`
+	City        string   `json:"city" binding:"required"`
+	State       string   `json:"state" binding:"required"`
+	ZipCode     string   `json:"zip_code" binding:"required"`
+	Country     string   `json:"country"`
+	Latitude    *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude   *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	YearBuilt   int      `json:"year_built"`
+	Amenities   []string `json:"amenities"`
+	Images      []string `json:"images"`
+	VRModelURL  string   `json:"vr_model_url"`
+}
+
+// DevelopmentUpdateRequest represents development update request. Address
+// and location changes are applied to every unit.
+type DevelopmentUpdateRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,max=200"`
+	Description *string  `json:"description"`
+	Address     *string  `json:"address"`
+	City        *string  `json:"city"`
+	State       *string  `json:"state"`
+	ZipCode     *string  `json:"zip_code"`
+	Country     *string  `json:"country"`
+	Latitude    *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude   *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	YearBuilt   *int     `json:"year_built"`
+	Amenities   []string `json:"amenities"`
+	Images      []string `json:"images"`
+	VRModelURL  *string  `json:"vr_model_url"`
+}
+
+// DevelopmentUnitCreateRequest lists a unit of a development. The address,
+// location and year built come from the development.
+type DevelopmentUnitCreateRequest struct {
+	UnitNumber   string       `json:"unit_number" binding:"required,max=20"`
+	Title        string       `json:"title" binding:"required"`
+	Description  string       `json:"description"`
+	Price        float64      `json:"price"`
+	PropertyType PropertyType `json:"property_type" binding:"required"`
+	ListingType  ListingType  `json:"listing_type"`
+	Bedrooms     int          `json:"bedrooms"`
+	Bathrooms    float64      `json:"bathrooms"`
+	SquareFeet   int          `json:"square_feet"`
+	Features     []string     `json:"features"`
+	Images       []string     `json:"images"`
+
+	// Commercial fields of commercial units
+	Commercial *CommercialDetails `json:"commercial"`
+
+	// Rental terms, as for PropertyCreateRequest
+	MonthlyRent     *float64  `json:"monthly_rent"`
+	SecurityDeposit *float64  `json:"security_deposit"`
+	LeaseTermMonths *int      `json:"lease_term_months"`
+	PetPolicy       PetPolicy `json:"pet_policy"`
+	AvailableFrom   string    `json:"available_from" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// DevelopmentListRequest represents a development list request
+type DevelopmentListRequest struct {
+	City     string `form:"city"`
+	State    string `form:"state"`
+	AgentID  *uint  `form:"agent_id"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// DevelopmentSummary is the development shown on its unit listings
+type DevelopmentSummary struct {
+	ID         uint     `json:"id"`
+	Name       string   `json:"name"`
+	Amenities  []string `json:"amenities"`
+	Images     []string `json:"images"`
+	VRModelURL string   `json:"vr_model_url"`
+}
+
+// DevelopmentRollup summarises the units of a development that matched a
+// search when units are rolled up into one result per development
+type DevelopmentRollup struct {
+	MatchingUnits int64    `json:"matching_units"`
+	MinPrice      *float64 `json:"min_price"`
+	MaxPrice      *float64 `json:"max_price"`
+	MinRent       *float64 `json:"min_rent"`
+	MaxRent       *float64 `json:"max_rent"`
+	MinBedrooms   int      `json:"min_bedrooms"`
+	MaxBedrooms   int      `json:"max_bedrooms"`
+}
+
+// DevelopmentUnit is a row of a development's unit availability table
+type DevelopmentUnit struct {
+	ID            uint           `json:"id"`
+	UnitNumber    string         `json:"unit_number"`
+	Title         string         `json:"title"`
+	PropertyType  PropertyType   `json:"property_type"`
+	ListingType   ListingType    `json:"listing_type"`
+	Status        PropertyStatus `json:"status"`
+	Bedrooms      int            `json:"bedrooms"`
+	Bathrooms     float64        `json:"bathrooms"`
+	SquareFeet    int            `json:"square_feet"`
+	Price         float64        `json:"price"`
+	MonthlyRent   *float64       `json:"monthly_rent"`
+	AvailableFrom *time.Time     `json:"available_from"`
+}
+
+// UnitPriceRow is a row of a development's price table, grouping units by
+// listing type and bedroom count. Price ranges cover available units only
+// and are monthly rents for rent listings.
+type UnitPriceRow struct {
+	ListingType    ListingType `json:"listing_type"`
+	Bedrooms       int         `json:"bedrooms"`
+	Units          int         `json:"units"`
+	AvailableUnits int         `json:"available_units"`
+	MinPrice       *float64    `json:"min_price"`
+	MaxPrice       *float64    `json:"max_price"`
+	MinSquareFeet  int         `json:"min_square_feet"`
+	MaxSquareFeet  int         `json:"max_square_feet"`
+}
+
+// DevelopmentResponse represents development response. Units and the price
+// table are included when a single development is requested.
+type DevelopmentResponse struct {
+	ID             uint              `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	Address        string            `json:"address"`
+	City           string            `json:"city"`
+	State          string            `json:"state"`
+	ZipCode        string            `json:"zip_code"`
+	Country        string            `json:"country"`
+	Latitude       *float64          `json:"latitude"`
+	Longitude      *float64          `json:"longitude"`
+	YearBuilt      int               `json:"year_built"`
+	Amenities      []string          `json:"amenities"`
+	Images         []string          `json:"images"`
+	VRModelURL     string            `json:"vr_model_url"`
+	AgentID        uint              `json:"agent_id"`
+	UnitCount      int64             `json:"unit_count"`
+	AvailableUnits int64             `json:"available_units"`
+	Units          []DevelopmentUnit `json:"units,omitempty"`
+	PriceTable     []UnitPriceRow    `json:"price_table,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
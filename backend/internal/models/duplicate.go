@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// DuplicateReason records why two listings were flagged as duplicates
+type DuplicateReason string
+
+const (
+	DuplicateReasonSameAddress    DuplicateReason = "same_address"
+	DuplicateReasonSimilarAddress DuplicateReason = "similar_address"
+	DuplicateReasonSameLocation   DuplicateReason = "same_location"
+)
+
+// DuplicateStatus represents where a flagged pair is in admin review
+type DuplicateStatus string
+
+const (
+	DuplicateStatusPending   DuplicateStatus = "pending"
+	DuplicateStatusDismissed DuplicateStatus = "dismissed"
+	DuplicateStatusMerged    DuplicateStatus = "merged"
+)
+
+// IsValid reports whether the duplicate status is one of the known statuses
+func (s DuplicateStatus) IsValid() bool {
+	switch s {
+	case DuplicateStatusPending, DuplicateStatusDismissed, DuplicateStatusMerged:
+		return true
+	}
+	return false
+}
+
+// DuplicateCandidate flags a listing that looks like a duplicate of an
+// earlier one. Each pair is flagged once, so dismissed pairs stay dismissed.
+type DuplicateCandidate struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	PropertyID    uint            `json:"property_id" gorm:"not null;uniqueIndex:idx_duplicate_candidates_pair"`
+	Property      Property        `json:"property" gorm:"foreignKey:PropertyID"`
+	DuplicateOfID uint            `json:"duplicate_of_id" gorm:"not null;uniqueIndex:idx_duplicate_candidates_pair;index"`
+	DuplicateOf   Property        `json:"duplicate_of" gorm:"foreignKey:DuplicateOfID"`
+	Reason        DuplicateReason `json:"reason" gorm:"not null"`
+	Score         float64         `json:"score"`
+	Status        DuplicateStatus `json:"status" gorm:"not null;default:'pending';index"`
+	ReviewedByID  *uint           `json:"reviewed_by_id"`
+	ReviewedAt    *time.Time      `json:"reviewed_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// DuplicateListRequest represents duplicate review queue request
+type DuplicateListRequest struct {
+	Status   DuplicateStatus `form:"status"`
+	Page     int             `form:"page"`
+	PageSize int             `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// DuplicateMergeRequest represents a duplicate merge request. KeepID picks
+// which listing of the pair survives; it defaults to the earlier listing.
+type DuplicateMergeRequest struct {
+	KeepID *uint `json:"keep_id"`
+}
+
+// DuplicateListing is a listing as shown in the duplicate review queue
+type DuplicateListing struct {
+	ID         uint           `json:"id"`
+	Title      string         `json:"title"`
+	Address    string         `json:"address"`
+	UnitNumber string         `json:"unit_number"`
+	City       string         `json:"city"`
+	State      string         `json:"state"`
+	ZipCode    string         `json:"zip_code"`
+	AddressKey string         `json:"address_key"`
+	Price      float64        `json:"price"`
+	Status     PropertyStatus `json:"status"`
+	AgentID    uint           `json:"agent_id"`
+	ImageCount int            `json:"image_count"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// DuplicateCandidateResponse represents a flagged pair in the review queue
+type DuplicateCandidateResponse struct {
+	ID           uint             `json:"id"`
+	Reason       DuplicateReason  `json:"reason"`
+	Score        float64          `json:"score"`
+	Status       DuplicateStatus  `json:"status"`
+	Property     DuplicateListing `json:"property"`
+	DuplicateOf  DuplicateListing `json:"duplicate_of"`
+	ReviewedByID *uint            `json:"reviewed_by_id"`
+	ReviewedAt   *time.Time       `json:"reviewed_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// DuplicateScanResult reports what a duplicate scan found
+type DuplicateScanResult struct {
+	Scanned    int `json:"scanned"`
+	Normalized int `json:"normalized"`
+	Flagged    int `json:"flagged"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+)
+
+// Lead represents a buyer inquiry about a property, tracked through the
+// listing agent's sales pipeline. BrokerageID is copied from the listing
+// agent so the lead stays visible to the brokerage when it is reassigned.
+type Lead struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	PropertyID  uint       `json:"property_id" gorm:"not null;index"`
+	Property    Property   `json:"property" gorm:"foreignKey:PropertyID"`
+	AgentID     uint       `json:"agent_id" gorm:"not null;index"`
+	Agent       User       `json:"agent" gorm:"foreignKey:AgentID"`
+	BrokerageID *uint      `json:"brokerage_id" gorm:"index"`
+	BuyerID     *uint      `json:"buyer_id" gorm:"index"`
+	Name        string     `json:"name" gorm:"not null"`
+	Email       string     `json:"email" gorm:"not null;index"`
+	Phone       string     `json:"phone"`
+	Message     string     `json:"message" gorm:"type:text"`
+	Source      LeadSource `json:"source" gorm:"not null;default:'inquiry'"`
+	Stage       LeadStage  `json:"stage" gorm:"not null;default:'new';index"`
+	Notes       []LeadNote `json:"notes,omitempty" gorm:"foreignKey:LeadID"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// LeadNote is a note an agent recorded against a lead
+type LeadNote struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	LeadID    uint      `json:"lead_id" gorm:"not null;index"`
+	AuthorID  uint      `json:"author_id" gorm:"not null"`
+	Author    User      `json:"author" gorm:"foreignKey:AuthorID"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LeadSource represents where a lead came from
+type LeadSource string
+
+const (
+	LeadSourceInquiry LeadSource = "inquiry"
+)
+
+// LeadStage represents a lead's pipeline stage
+type LeadStage string
+
+const (
+	LeadStageNew       LeadStage = "new"
+	LeadStageContacted LeadStage = "contacted"
+	LeadStageQualified LeadStage = "qualified"
+	LeadStageShowing   LeadStage = "showing"
+	LeadStageOffer     LeadStage = "offer"
+	LeadStageWon       LeadStage = "won"
+	LeadStageLost      LeadStage = "lost"
+)
+
+// IsValid reports whether the stage is a known pipeline stage
+func (s LeadStage) IsValid() bool {
+	switch s {
+	case LeadStageNew, LeadStageContacted, LeadStageQualified, LeadStageShowing,
+		LeadStageOffer, LeadStageWon, LeadStageLost:
+		return true
+	}
+	return false
+}
+
+// InquiryRequest represents a buyer inquiry about a property. Name and
+// email are required for guests and default to the profile of a signed-in
+// user.
+type InquiryRequest struct {
+	Name    string `json:"name"`
+	Email   string `json:"email" binding:"omitempty,email"`
+	Phone   string `json:"phone"`
+	Message string `json:"message" binding:"required,max=5000"`
+}
+
+// LeadListRequest represents lead list filters. Leads are limited to the
+// agent's own leads and those of their brokerage.
+type LeadListRequest struct {
+	Stage      *LeadStage `form:"stage"`
+	PropertyID *uint      `form:"property_id"`
+	AgentID    *uint      `form:"agent_id"`
+	Query      string     `form:"q"`
+	From       *time.Time `form:"from" time_format:"2006-01-02"`
+	To         *time.Time `form:"to" time_format:"2006-01-02"`
+	Page       int        `form:"page"`
+	PageSize   int        `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// LeadStageRequest moves a lead to another pipeline stage
+type LeadStageRequest struct {
+	Stage LeadStage `json:"stage" binding:"required"`
+}
+
+// LeadNoteRequest represents a lead note creation request
+type LeadNoteRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// LeadAssignRequest reassigns a lead to another agent of the brokerage
+type LeadAssignRequest struct {
+	AgentID uint `json:"agent_id" binding:"required"`
+}
+
+// LeadResponse represents lead response
+type LeadResponse struct {
+	ID            uint       `json:"id"`
+	PropertyID    uint       `json:"property_id"`
+	PropertyTitle string     `json:"property_title"`
+	AgentID       uint       `json:"agent_id"`
+	AgentName     string     `json:"agent_name"`
+	BrokerageID   *uint      `json:"brokerage_id,omitempty"`
+	BuyerID       *uint      `json:"buyer_id,omitempty"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Phone         string     `json:"phone"`
+	Message       string     `json:"message"`
+	Source        LeadSource `json:"source"`
+	Stage         LeadStage  `json:"stage"`
+	Notes         []LeadNote `json:"notes,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
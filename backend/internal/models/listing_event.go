@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+)
+
+// ListingEventType represents what a visitor did on a listing
+type ListingEventType string
+
+const (
+	ListingEventView      ListingEventType = "view"
+	ListingEventMediaView ListingEventType = "media_view"
+	ListingEventTourOpen  ListingEventType = "tour_open"
+	ListingEventInquiry   ListingEventType = "inquiry"
+)
+
+// IsValid reports whether the type is a known listing event type
+func (t ListingEventType) IsValid() bool {
+	switch t {
+	case ListingEventView, ListingEventMediaView, ListingEventTourOpen, ListingEventInquiry:
+		return true
+	}
+	return false
+}
+
+// ListingEvent records a visitor's interaction with a listing. VisitorID
+// identifies the visitor across events: the user for signed-in visitors,
+// otherwise the browser's visitor ID or a hash of its address and user
+// agent. It is what unique visitor counts are based on.
+type ListingEvent struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	PropertyID  uint             `json:"property_id" gorm:"not null;index:idx_listing_events_property_time"`
+	Type        ListingEventType `json:"type" gorm:"not null;index"`
+	VisitorID   string           `json:"visitor_id" gorm:"not null;index"`
+	UserID      *uint            `json:"user_id" gorm:"index"`
+	MediaFileID *uint            `json:"media_file_id"`
+	VRTourID    *uint            `json:"vr_tour_id"`
+	OccurredAt  time.Time        `json:"occurred_at" gorm:"not null;index:idx_listing_events_property_time"`
+}
+
+// ListingEventRequest represents an event the client reports on a listing.
+// Views and inquiries are recorded by the server, so only media views and
+// tour opens are accepted.
+type ListingEventRequest struct {
+	Type        ListingEventType `json:"type" binding:"required"`
+	MediaFileID *uint            `json:"media_file_id"`
+	VRTourID    *uint            `json:"vr_tour_id"`
+}
+
+// AnalyticsInterval is the bucket size of an analytics time series
+type AnalyticsInterval string
+
+const (
+	AnalyticsIntervalDay   AnalyticsInterval = "day"
+	AnalyticsIntervalWeek  AnalyticsInterval = "week"
+	AnalyticsIntervalMonth AnalyticsInterval = "month"
+)
+
+// IsValid reports whether the interval is a known bucket size
+func (i AnalyticsInterval) IsValid() bool {
+	return i == AnalyticsIntervalDay || i == AnalyticsIntervalWeek || i == AnalyticsIntervalMonth
+}
+
+// AnalyticsRequest represents an analytics query. Dates are UTC days and
+// both ends are inclusive; the range defaults to the last 30 days and may
+// span at most a year.
+type AnalyticsRequest struct {
+	From     *time.Time        `form:"from" time_format:"2006-01-02"`
+	To       *time.Time        `form:"to" time_format:"2006-01-02"`
+	Interval AnalyticsInterval `form:"interval"`
+}
+
+// ListingEventTotals counts a listing's events over a period
+type ListingEventTotals struct {
+	Views          int64 `json:"views"`
+	UniqueVisitors int64 `json:"unique_visitors"`
+	MediaViews     int64 `json:"media_views"`
+	TourOpens      int64 `json:"tour_opens"`
+	Inquiries      int64 `json:"inquiries"`
+}
+
+// AnalyticsPoint is one bucket of a time series, starting at Period
+type AnalyticsPoint struct {
+	Period time.Time `json:"period"`
+	ListingEventTotals
+}
+
+// FunnelStage is one step of the conversion funnel. Visitors counts the
+// unique visitors who reached the stage; Rate is their share of the
+// listing's viewers.
+type FunnelStage struct {
+	Stage    ListingEventType `json:"stage"`
+	Visitors int64            `json:"visitors"`
+	Rate     float64          `json:"rate"`
+}
+
+// ListingComparison compares a listing with similar live listings: those of
+// the same property and listing type in the same city with a price within
+// 20%. Averages are per similar listing over the same period; the indexes
+// are the listing's figure over the average, so 1.5 means 50% more.
+type ListingComparison struct {
+	SimilarListings    int                  `json:"similar_listings"`
+	Average            ListingEventAverages `json:"average"`
+	AverageInquiryRate float64              `json:"average_inquiry_rate"`
+	ViewsIndex         *float64             `json:"views_index"`
+	InquiriesIndex     *float64             `json:"inquiries_index"`
+}
+
+// ListingEventAverages averages event totals over several listings
+type ListingEventAverages struct {
+	Views          float64 `json:"views"`
+	UniqueVisitors float64 `json:"unique_visitors"`
+	MediaViews     float64 `json:"media_views"`
+	TourOpens      float64 `json:"tour_opens"`
+	Inquiries      float64 `json:"inquiries"`
+}
+
+// ListingAnalytics is a listing's performance over a period
+type ListingAnalytics struct {
+	PropertyID uint               `json:"property_id"`
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Interval   AnalyticsInterval  `json:"interval"`
+	Totals     ListingEventTotals `json:"totals"`
+	Series     []AnalyticsPoint   `json:"series"`
+	Funnel     []FunnelStage      `json:"funnel"`
+	Comparison ListingComparison  `json:"comparison"`
+}
+
+// ListingPerformance summarizes one of an agent's listings
+type ListingPerformance struct {
+	PropertyID  uint               `json:"property_id"`
+	Title       string             `json:"title"`
+	Totals      ListingEventTotals `json:"totals"`
+	InquiryRate float64            `json:"inquiry_rate"`
+}
+
+// AgentAnalytics is the combined performance of an agent's listings over a
+// period, with each listing's share, most viewed first
+type AgentAnalytics struct {
+	AgentID  uint                 `json:"agent_id"`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Interval AnalyticsInterval    `json:"interval"`
+	Totals   ListingEventTotals   `json:"totals"`
+	Series   []AnalyticsPoint     `json:"series"`
+	Funnel   []FunnelStage        `json:"funnel"`
+	Listings []ListingPerformance `json:"listings"`
+}
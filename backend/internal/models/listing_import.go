@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// ListingImport represents a bulk listing import job
+type ListingImport struct {
+	ID            uint                `json:"id" gorm:"primaryKey"`
+	AgentID       uint                `json:"agent_id" gorm:"not null;index"`
+	FileName      string              `json:"file_name" gorm:"not null"`
+	Format        string              `json:"format" gorm:"not null"`
+	Status        ListingImportStatus `json:"status" gorm:"not null;default:'validated'"`
+	TotalRows     int                 `json:"total_rows"`
+	ValidRows     int                 `json:"valid_rows"`
+	ProcessedRows int                 `json:"processed_rows"`
+	CreatedRows   int                 `json:"created_rows"`
+	FailedRows    int                 `json:"failed_rows"`
+	Rows          []ListingImportRow  `json:"-" gorm:"type:json;serializer:json"`
+	Errors        []ImportRowError    `json:"errors" gorm:"type:json;serializer:json"`
+	StartedAt     *time.Time          `json:"started_at"`
+	CompletedAt   *time.Time          `json:"completed_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// ListingImportStatus represents the state of an import job
+type ListingImportStatus string
+
+const (
+	ListingImportStatusValidated  ListingImportStatus = "validated"
+	ListingImportStatusProcessing ListingImportStatus = "processing"
+	ListingImportStatusCompleted  ListingImportStatus = "completed"
+	ListingImportStatusFailed     ListingImportStatus = "failed"
+)
+
+// ListingImportRow is a validated row waiting to be committed
+type ListingImportRow struct {
+	Row     int                   `json:"row"`
+	Request PropertyCreateRequest `json:"request"`
+}
+
+// ImportRowError lists the validation errors of a single spreadsheet row
+type ImportRowError struct {
+	Row    int          `json:"row"`
+	Errors []FieldError `json:"errors"`
+}
+
+// ListingImportResponse represents an import job with its validation report
+type ListingImportResponse struct {
+	ID            uint                `json:"id"`
+	FileName      string              `json:"file_name"`
+	Format        string              `json:"format"`
+	Status        ListingImportStatus `json:"status"`
+	TotalRows     int                 `json:"total_rows"`
+	ValidRows     int                 `json:"valid_rows"`
+	InvalidRows   int                 `json:"invalid_rows"`
+	ProcessedRows int                 `json:"processed_rows"`
+	CreatedRows   int                 `json:"created_rows"`
+	FailedRows    int                 `json:"failed_rows"`
+	Progress      float64             `json:"progress"`
+	Errors        []ImportRowError    `json:"errors"`
+	StartedAt     *time.Time          `json:"started_at"`
+	CompletedAt   *time.Time          `json:"completed_at"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
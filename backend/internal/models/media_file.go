@@ -8,17 +8,22 @@ import (
 
 // MediaFile represents a media file (image, video, etc.)
 type MediaFile struct {
-	ID         uint           `json:"id" gorm:"primaryKey"`
-	PropertyID uint           `json:"property_id" gorm:"not null"`
-	Property   Property       `json:"property" gorm:"foreignKey:PropertyID"`
-	FileName   string         `json:"file_name" gorm:"not null"`
-	FileURL    string         `json:"file_url" gorm:"not null"`
-	FileType   string         `json:"file_type" gorm:"not null"`
-	FileSize   int64          `json:"file_size"`
-	IsActive   bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID               uint             `json:"id" gorm:"primaryKey"`
+	PropertyID       uint             `json:"property_id" gorm:"not null"`
+	Property         Property         `json:"property" gorm:"foreignKey:PropertyID"`
+	FileName         string           `json:"file_name" gorm:"not null"`
+	FileURL          string           `json:"file_url" gorm:"not null"`
+	FileType         string           `json:"file_type" gorm:"not null"`
+	FileSize         int64            `json:"file_size"`
+	Width            *int             `json:"width"`
+	Height           *int             `json:"height"`
+	IsActive         bool             `json:"is_active" gorm:"default:true"`
+	ModerationStatus ModerationStatus `json:"moderation_status" gorm:"not null;default:'approved';index"`
+	SourceMediaKey   *string          `json:"source_media_key,omitempty" gorm:"index"`
+	SortOrder        int              `json:"sort_order"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt   `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // MediaFileCreateRequest represents media file creation request
@@ -32,13 +37,17 @@ type MediaFileCreateRequest struct {
 
 // MediaFileResponse represents media file response
 type MediaFileResponse struct {
-	ID         uint      `json:"id"`
-	PropertyID uint      `json:"property_id"`
-	FileName   string    `json:"file_name"`
-	FileURL    string    `json:"file_url"`
-	FileType   string    `json:"file_type"`
-	FileSize   int64     `json:"file_size"`
-	IsActive   bool      `json:"is_active"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID               uint             `json:"id"`
+	PropertyID       uint             `json:"property_id"`
+	FileName         string           `json:"file_name"`
+	FileURL          string           `json:"file_url"`
+	FileType         string           `json:"file_type"`
+	FileSize         int64            `json:"file_size"`
+	Width            *int             `json:"width,omitempty"`
+	Height           *int             `json:"height,omitempty"`
+	IsActive         bool             `json:"is_active"`
+	ModerationStatus ModerationStatus `json:"moderation_status"`
+	SortOrder        int              `json:"sort_order"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
 }
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+// MLSFeed represents a RESO Web API endpoint that listings are ingested from
+type MLSFeed struct {
+	ID                        uint       `json:"id" gorm:"primaryKey"`
+	Name                      string     `json:"name" gorm:"not null"`
+	BaseURL                   string     `json:"base_url" gorm:"not null"`
+	AccessToken               string     `json:"-"`
+	DefaultAgentID            uint       `json:"default_agent_id" gorm:"not null"`
+	IsActive                  bool       `json:"is_active" gorm:"default:true"`
+	LastModificationTimestamp *time.Time `json:"last_modification_timestamp"`
+	LastSyncedAt              *time.Time `json:"last_synced_at"`
+	LastSyncStatus            string     `json:"last_sync_status"`
+	LastSyncError             string     `json:"last_sync_error"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+}
+
+// MLSFeedCreateRequest represents MLS feed creation request
+type MLSFeedCreateRequest struct {
+	Name           string `json:"name" binding:"required"`
+	BaseURL        string `json:"base_url" binding:"required,url"`
+	AccessToken    string `json:"access_token"`
+	DefaultAgentID uint   `json:"default_agent_id" binding:"required"`
+}
+
+// MLSFeedResponse represents MLS feed response
+type MLSFeedResponse struct {
+	ID                        uint       `json:"id"`
+	Name                      string     `json:"name"`
+	BaseURL                   string     `json:"base_url"`
+	DefaultAgentID            uint       `json:"default_agent_id"`
+	IsActive                  bool       `json:"is_active"`
+	LastModificationTimestamp *time.Time `json:"last_modification_timestamp"`
+	LastSyncedAt              *time.Time `json:"last_synced_at"`
+	LastSyncStatus            string     `json:"last_sync_status"`
+	LastSyncError             string     `json:"last_sync_error"`
+	CreatedAt                 time.Time  `json:"created_at"`
+	UpdatedAt                 time.Time  `json:"updated_at"`
+}
+
+// MLSSyncResult summarizes a single feed synchronization run
+type MLSSyncResult struct {
+	FeedID        uint       `json:"feed_id"`
+	Fetched       int        `json:"fetched"`
+	Created       int        `json:"created"`
+	Updated       int        `json:"updated"`
+	Removed       int        `json:"removed"`
+	Skipped       int        `json:"skipped"`
+	MediaSynced   int        `json:"media_synced"`
+	Errors        []string   `json:"errors"`
+	HighWatermark *time.Time `json:"high_watermark"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    time.Time  `json:"finished_at"`
+}
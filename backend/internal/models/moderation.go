@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+)
+
+// ModerationStatus represents whether a listing or media file may be shown.
+// Content no rule flagged is approved automatically; pending content stays
+// visible until an admin reviews it and rejected content is hidden.
+type ModerationStatus string
+
+const (
+	ModerationStatusApproved ModerationStatus = "approved"
+	ModerationStatusPending  ModerationStatus = "pending"
+	ModerationStatusRejected ModerationStatus = "rejected"
+)
+
+// IsValid reports whether the moderation status is one of the known statuses
+func (s ModerationStatus) IsValid() bool {
+	switch s {
+	case ModerationStatusApproved, ModerationStatusPending, ModerationStatusRejected:
+		return true
+	}
+	return false
+}
+
+// ModerationItemType represents what a moderation queue item reviews
+type ModerationItemType string
+
+const (
+	ModerationItemProperty ModerationItemType = "property"
+	ModerationItemMedia    ModerationItemType = "media"
+)
+
+// IsValid reports whether the moderation item type is one of the known types
+func (t ModerationItemType) IsValid() bool {
+	return t == ModerationItemProperty || t == ModerationItemMedia
+}
+
+// ModerationFlag is one reason content was sent for review: a listing rule
+// that matched or a user report
+type ModerationFlag struct {
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// ModerationItem is a listing or one of its media files waiting for, or
+// having had, admin review. Each piece of content has at most one pending
+// item; new flags are added to it.
+type ModerationItem struct {
+	ID           uint               `json:"id" gorm:"primaryKey"`
+	ItemType     ModerationItemType `json:"item_type" gorm:"not null"`
+	PropertyID   uint               `json:"property_id" gorm:"not null;index"`
+	Property     Property           `json:"property" gorm:"foreignKey:PropertyID"`
+	MediaFileID  *uint              `json:"media_file_id" gorm:"index"`
+	MediaFile    *MediaFile         `json:"media_file,omitempty" gorm:"foreignKey:MediaFileID"`
+	Flags        []ModerationFlag   `json:"flags" gorm:"type:json;serializer:json"`
+	Status       ModerationStatus   `json:"status" gorm:"not null;default:'pending';index"`
+	ReportedByID *uint              `json:"reported_by_id"`
+	ReviewReason string             `json:"review_reason"`
+	ReviewedByID *uint              `json:"reviewed_by_id"`
+	ReviewedAt   *time.Time         `json:"reviewed_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// ModerationListRequest represents moderation queue request
+type ModerationListRequest struct {
+	Status   ModerationStatus   `form:"status"`
+	ItemType ModerationItemType `form:"item_type"`
+	Page     int                `form:"page"`
+	PageSize int                `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// ModerationReviewRequest records an admin's reason for a decision. A
+// reason is required to reject content.
+type ModerationReviewRequest struct {
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// ModerationReportRequest reports a listing, or one of its media files, as
+// inappropriate
+type ModerationReportRequest struct {
+	MediaFileID *uint  `json:"media_file_id"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+// ModerationItemResponse represents a moderation queue item
+type ModerationItemResponse struct {
+	ID            uint               `json:"id"`
+	ItemType      ModerationItemType `json:"item_type"`
+	Status        ModerationStatus   `json:"status"`
+	Flags         []ModerationFlag   `json:"flags"`
+	PropertyID    uint               `json:"property_id"`
+	PropertyTitle string             `json:"property_title"`
+	Description   string             `json:"description,omitempty"`
+	AgentID       uint               `json:"agent_id"`
+	MediaFileID   *uint              `json:"media_file_id,omitempty"`
+	MediaURL      string             `json:"media_url,omitempty"`
+	ReportedByID  *uint              `json:"reported_by_id,omitempty"`
+	ReviewReason  string             `json:"review_reason,omitempty"`
+	ReviewedByID  *uint              `json:"reviewed_by_id"`
+	ReviewedAt    *time.Time         `json:"reviewed_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
@@ -0,0 +1,90 @@
+package models
+
+// MortgageRequest represents a monthly payment calculation. The price is
+// taken from PropertyID when given. Rates are annual percentages; the down
+// payment is given either as an amount or as a percentage of the price.
+type MortgageRequest struct {
+	PropertyID         *uint    `json:"property_id"`
+	Price              float64  `json:"price" binding:"min=0"`
+	DownPayment        *float64 `json:"down_payment" binding:"omitempty,min=0"`
+	DownPaymentPercent *float64 `json:"down_payment_percent" binding:"omitempty,min=0,max=100"`
+	InterestRate       *float64 `json:"interest_rate" binding:"required,min=0,max=30"`
+	TermYears          int      `json:"term_years" binding:"omitempty,min=1,max=50"`
+	PropertyTaxRate    *float64 `json:"property_tax_rate" binding:"omitempty,min=0,max=10"`
+	AnnualPropertyTax  *float64 `json:"annual_property_tax" binding:"omitempty,min=0"`
+	AnnualInsurance    float64  `json:"annual_insurance" binding:"min=0"`
+	MonthlyHOA         float64  `json:"monthly_hoa" binding:"min=0"`
+	PMIRate            float64  `json:"pmi_rate" binding:"min=0,max=5"`
+	Schedule           string   `json:"schedule" binding:"omitempty,oneof=none monthly yearly"`
+}
+
+// PaymentBreakdown splits a monthly housing payment into principal and
+// interest, taxes, insurance, HOA dues and mortgage insurance (PITI)
+type PaymentBreakdown struct {
+	PrincipalAndInterest float64 `json:"principal_and_interest"`
+	PropertyTax          float64 `json:"property_tax"`
+	Insurance            float64 `json:"insurance"`
+	HOA                  float64 `json:"hoa"`
+	PMI                  float64 `json:"pmi"`
+	Total                float64 `json:"total"`
+}
+
+// AmortizationEntry is one month, or one year, of an amortization schedule.
+// Balance is the loan balance at the end of the period.
+type AmortizationEntry struct {
+	Period    int     `json:"period"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	PMI       float64 `json:"pmi"`
+	Balance   float64 `json:"balance"`
+}
+
+// MortgageResponse represents a monthly payment calculation. PMIMonths is
+// how many payments carry mortgage insurance before the loan reaches 80% of
+// the price.
+type MortgageResponse struct {
+	PropertyID         *uint               `json:"property_id,omitempty"`
+	Price              float64             `json:"price"`
+	DownPayment        float64             `json:"down_payment"`
+	DownPaymentPercent float64             `json:"down_payment_percent"`
+	LoanAmount         float64             `json:"loan_amount"`
+	InterestRate       float64             `json:"interest_rate"`
+	TermYears          int                 `json:"term_years"`
+	Monthly            PaymentBreakdown    `json:"monthly"`
+	PMIMonths          int                 `json:"pmi_months"`
+	TotalInterest      float64             `json:"total_interest"`
+	TotalOfPayments    float64             `json:"total_of_payments"`
+	ScheduleType       string              `json:"schedule_type"`
+	Schedule           []AmortizationEntry `json:"schedule,omitempty"`
+}
+
+// AffordabilityRequest represents a reverse affordability search: the most
+// a buyer can pay given their income and debts, and the listings within
+// that price. The front-end ratio caps housing costs and the back-end ratio
+// caps housing costs plus other debts, both as fractions of gross monthly
+// income. Search narrows the listings returned.
+type AffordabilityRequest struct {
+	AnnualIncome    float64                `json:"annual_income" binding:"required,gt=0"`
+	MonthlyDebts    float64                `json:"monthly_debts" binding:"min=0"`
+	DownPayment     float64                `json:"down_payment" binding:"min=0"`
+	InterestRate    *float64               `json:"interest_rate" binding:"required,min=0,max=30"`
+	TermYears       int                    `json:"term_years" binding:"omitempty,min=1,max=50"`
+	PropertyTaxRate float64                `json:"property_tax_rate" binding:"min=0,max=10"`
+	AnnualInsurance float64                `json:"annual_insurance" binding:"min=0"`
+	MonthlyHOA      float64                `json:"monthly_hoa" binding:"min=0"`
+	FrontEndRatio   float64                `json:"front_end_ratio" binding:"omitempty,gt=0,max=1"`
+	BackEndRatio    float64                `json:"back_end_ratio" binding:"omitempty,gt=0,max=1"`
+	Search          *PropertySearchRequest `json:"search" binding:"-"`
+}
+
+// AffordabilityResponse represents a reverse affordability search. LimitedBy
+// names the ratio that set the budget.
+type AffordabilityResponse struct {
+	MaxPrice          float64             `json:"max_price"`
+	MaxLoanAmount     float64             `json:"max_loan_amount"`
+	MaxMonthlyPayment float64             `json:"max_monthly_payment"`
+	LimitedBy         string              `json:"limited_by"`
+	Monthly           PaymentBreakdown    `json:"monthly"`
+	Listings          *PaginationResponse `json:"listings"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// Offer represents a purchase offer on a property. A counter-offer is a new
+// offer linked to the one it answers through ParentID; ProposedBy is the
+// buyer or agent who made this round of the negotiation.
+type Offer struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	PropertyID    uint          `json:"property_id" gorm:"not null;index"`
+	Property      Property      `json:"property" gorm:"foreignKey:PropertyID"`
+	BuyerID       uint          `json:"buyer_id" gorm:"not null;index"`
+	Buyer         User          `json:"buyer" gorm:"foreignKey:BuyerID"`
+	AgentID       uint          `json:"agent_id" gorm:"not null;index"`
+	ParentID      *uint         `json:"parent_id" gorm:"index"`
+	ProposedBy    uint          `json:"proposed_by" gorm:"not null"`
+	Amount        float64       `json:"amount" gorm:"not null"`
+	EarnestMoney  float64       `json:"earnest_money"`
+	Contingencies []Contingency `json:"contingencies" gorm:"type:json;serializer:json"`
+	ClosingDate   *time.Time    `json:"closing_date" gorm:"type:date"`
+	ExpiresAt     time.Time     `json:"expires_at" gorm:"not null"`
+	Message       string        `json:"message" gorm:"type:text"`
+	Status        OfferStatus   `json:"status" gorm:"not null;index"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// OfferStatus represents the status of an offer
+type OfferStatus string
+
+const (
+	OfferStatusSubmitted OfferStatus = "submitted"
+	OfferStatusCountered OfferStatus = "countered"
+	OfferStatusAccepted  OfferStatus = "accepted"
+	OfferStatusRejected  OfferStatus = "rejected"
+	OfferStatusWithdrawn OfferStatus = "withdrawn"
+	OfferStatusExpired   OfferStatus = "expired"
+)
+
+// Contingency is a condition an offer depends on
+type Contingency string
+
+const (
+	ContingencyInspection Contingency = "inspection"
+	ContingencyAppraisal  Contingency = "appraisal"
+	ContingencyFinancing  Contingency = "financing"
+	ContingencySaleOfHome Contingency = "sale_of_home"
+)
+
+// IsValid reports whether the contingency is a known contingency
+func (c Contingency) IsValid() bool {
+	switch c {
+	case ContingencyInspection, ContingencyAppraisal, ContingencyFinancing, ContingencySaleOfHome:
+		return true
+	}
+	return false
+}
+
+// OfferRequest represents an offer or counter-offer. ClosingDate is in
+// YYYY-MM-DD format.
+type OfferRequest struct {
+	Amount        float64       `json:"amount" binding:"required,gt=0"`
+	EarnestMoney  float64       `json:"earnest_money" binding:"min=0"`
+	Contingencies []Contingency `json:"contingencies"`
+	ClosingDate   string        `json:"closing_date" binding:"omitempty,datetime=2006-01-02"`
+	ExpiresAt     time.Time     `json:"expires_at" binding:"required"`
+	Message       string        `json:"message" binding:"max=5000"`
+}
+
+// OfferListRequest represents an offer list request
+type OfferListRequest struct {
+	Status   *OfferStatus `form:"status"`
+	Page     int          `form:"page"`
+	PageSize int          `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// OfferResponse represents offer response. History lists the earlier rounds
+// of the negotiation, oldest first, when a single offer is fetched.
+type OfferResponse struct {
+	ID            uint            `json:"id"`
+	PropertyID    uint            `json:"property_id"`
+	PropertyTitle string          `json:"property_title"`
+	BuyerID       uint            `json:"buyer_id"`
+	BuyerName     string          `json:"buyer_name"`
+	AgentID       uint            `json:"agent_id"`
+	ParentID      *uint           `json:"parent_id,omitempty"`
+	ProposedBy    uint            `json:"proposed_by"`
+	Amount        float64         `json:"amount"`
+	EarnestMoney  float64         `json:"earnest_money"`
+	Contingencies []Contingency   `json:"contingencies"`
+	ClosingDate   *time.Time      `json:"closing_date"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+	Message       string          `json:"message"`
+	Status        OfferStatus     `json:"status"`
+	TransactionID *uint           `json:"transaction_id,omitempty"`
+	History       []OfferResponse `json:"history,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
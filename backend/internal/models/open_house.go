@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OpenHouse represents an open house event for a property. StartsAt and
+// EndsAt are stored in UTC; TimeZone is the IANA zone the event is held in.
+type OpenHouse struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	PropertyID     uint           `json:"property_id" gorm:"not null;index"`
+	Property       Property       `json:"property" gorm:"foreignKey:PropertyID"`
+	StartsAt       time.Time      `json:"starts_at" gorm:"not null;index"`
+	EndsAt         time.Time      `json:"ends_at" gorm:"not null"`
+	TimeZone       string         `json:"time_zone" gorm:"not null"`
+	Capacity       int            `json:"capacity"`
+	Notes          string         `json:"notes"`
+	ConfirmedCount int            `json:"confirmed_count" gorm:"not null;default:0"`
+	WaitlistCount  int            `json:"waitlist_count" gorm:"not null;default:0"`
+	Sequence       int            `json:"sequence" gorm:"not null;default:0"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// OpenHouseRSVP represents a buyer's reservation for an open house
+type OpenHouseRSVP struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	OpenHouseID uint       `json:"open_house_id" gorm:"not null;uniqueIndex:idx_open_house_rsvps_user"`
+	UserID      uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_open_house_rsvps_user"`
+	User        User       `json:"user" gorm:"foreignKey:UserID"`
+	PartySize   int        `json:"party_size" gorm:"not null;default:1"`
+	Status      RSVPStatus `json:"status" gorm:"not null;index"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// RSVPStatus represents the status of an open house RSVP
+type RSVPStatus string
+
+const (
+	RSVPStatusConfirmed  RSVPStatus = "confirmed"
+	RSVPStatusWaitlisted RSVPStatus = "waitlisted"
+	RSVPStatusCancelled  RSVPStatus = "cancelled"
+)
+
+// OpenHouseLocalTimeFormat is the wall-clock format of open house times in
+// requests, interpreted in the request's time zone
+const OpenHouseLocalTimeFormat = "2006-01-02T15:04"
+
+// OpenHouseCreateRequest represents open house creation request. Capacity
+// counts attendees including party members; 0 means unlimited.
+type OpenHouseCreateRequest struct {
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	TimeZone  string `json:"time_zone" binding:"required"`
+	Capacity  int    `json:"capacity" binding:"min=0"`
+	Notes     string `json:"notes"`
+}
+
+// OpenHouseUpdateRequest represents open house update request
+type OpenHouseUpdateRequest struct {
+	StartTime *string `json:"start_time"`
+	EndTime   *string `json:"end_time"`
+	TimeZone  *string `json:"time_zone"`
+	Capacity  *int    `json:"capacity" binding:"omitempty,min=0"`
+	Notes     *string `json:"notes"`
+}
+
+// OpenHouseResponse represents open house response. LocalStart and LocalEnd
+// are the event times in its own time zone.
+type OpenHouseResponse struct {
+	ID             uint      `json:"id"`
+	PropertyID     uint      `json:"property_id"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	TimeZone       string    `json:"time_zone"`
+	LocalStart     string    `json:"local_start"`
+	LocalEnd       string    `json:"local_end"`
+	Capacity       int       `json:"capacity"`
+	Notes          string    `json:"notes"`
+	ConfirmedCount int       `json:"confirmed_count"`
+	WaitlistCount  int       `json:"waitlist_count"`
+	SpotsRemaining *int      `json:"spots_remaining"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// OpenHouseRSVPRequest represents an RSVP request
+type OpenHouseRSVPRequest struct {
+	PartySize int `json:"party_size" binding:"omitempty,min=1,max=10"`
+}
+
+// OpenHouseRSVPResponse represents open house RSVP response.
+// WaitlistPosition is set for waitlisted RSVPs, starting at 1.
+type OpenHouseRSVPResponse struct {
+	ID               uint          `json:"id"`
+	OpenHouseID      uint          `json:"open_house_id"`
+	User             *UserResponse `json:"user,omitempty"`
+	PartySize        int           `json:"party_size"`
+	Status           RSVPStatus    `json:"status"`
+	WaitlistPosition int           `json:"waitlist_position,omitempty"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
@@ -6,32 +6,61 @@ import (
 	"gorm.io/gorm"
 )
 
-// Property represents a real estate property
+// Property represents a real estate property. A purged property was
+// removed from the trash but is kept, stripped of its content, for the
+// offers, leads and other business records that still refer to it.
 type Property struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Title        string         `json:"title" gorm:"not null"`
-	Description  string         `json:"description"`
-	Price        float64        `json:"price" gorm:"not null"`
-	Address      string         `json:"address" gorm:"not null"`
-	City         string         `json:"city" gorm:"not null"`
-	State        string         `json:"state" gorm:"not null"`
-	ZipCode      string         `json:"zip_code" gorm:"not null"`
-	Country      string         `json:"country" gorm:"not null;default:'US'"`
-	PropertyType PropertyType   `json:"property_type" gorm:"not null"`
-	Status       PropertyStatus `json:"status" gorm:"not null;default:'available'"`
-	Bedrooms     int            `json:"bedrooms"`
-	Bathrooms    float64        `json:"bathrooms"`
-	SquareFeet   int            `json:"square_feet"`
-	YearBuilt    int            `json:"year_built"`
-	LotSize      float64        `json:"lot_size"`
-	Features     []string       `json:"features" gorm:"type:json"`
-	Images       []string       `json:"images" gorm:"type:json"`
-	VRModelURL   string         `json:"vr_model_url"`
-	AgentID      uint           `json:"agent_id" gorm:"not null"`
-	Agent        User           `json:"agent" gorm:"foreignKey:AgentID"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID               uint                `json:"id" gorm:"primaryKey"`
+	Title            string              `json:"title" gorm:"not null"`
+	Description      string              `json:"description"`
+	Price            float64             `json:"price" gorm:"not null"`
+	MonthlyRent      *float64            `json:"monthly_rent"`
+	SecurityDeposit  *float64            `json:"security_deposit"`
+	LeaseTermMonths  *int                `json:"lease_term_months"`
+	PetPolicy        PetPolicy           `json:"pet_policy"`
+	AvailableFrom    *time.Time          `json:"available_from" gorm:"type:date"`
+	Address          string              `json:"address" gorm:"not null"`
+	City             string              `json:"city" gorm:"not null"`
+	State            string              `json:"state" gorm:"not null"`
+	ZipCode          string              `json:"zip_code" gorm:"not null"`
+	Country          string              `json:"country" gorm:"not null;default:'US'"`
+	AddressKey       string              `json:"address_key" gorm:"index"`
+	PropertyType     PropertyType        `json:"property_type" gorm:"not null"`
+	ListingType      ListingType         `json:"listing_type" gorm:"not null;default:'sale';index"`
+	Status           PropertyStatus      `json:"status" gorm:"not null;default:'available'"`
+	Bedrooms         int                 `json:"bedrooms"`
+	Bathrooms        float64             `json:"bathrooms"`
+	SquareFeet       int                 `json:"square_feet"`
+	YearBuilt        int                 `json:"year_built"`
+	LotSize          float64             `json:"lot_size"`
+	Commercial       CommercialDetails   `json:"commercial" gorm:"embedded;embeddedPrefix:commercial_"`
+	Land             LandDetails         `json:"land" gorm:"embedded;embeddedPrefix:land_"`
+	Latitude         *float64            `json:"latitude" gorm:"index:idx_properties_location"`
+	Longitude        *float64            `json:"longitude" gorm:"index:idx_properties_location"`
+	GeocodeAccuracy  GeocodeAccuracy     `json:"geocode_accuracy"`
+	Features         []string            `json:"features" gorm:"type:json"`
+	Attributes       []PropertyAttribute `json:"attributes,omitempty" gorm:"foreignKey:PropertyID"`
+	Images           []string            `json:"images" gorm:"type:json"`
+	VRModelURL       string              `json:"vr_model_url"`
+	DevelopmentID    *uint               `json:"development_id" gorm:"index"`
+	Development      *Development        `json:"development,omitempty" gorm:"foreignKey:DevelopmentID"`
+	UnitNumber       string              `json:"unit_number"`
+	AgentID          uint                `json:"agent_id" gorm:"not null;index"`
+	Agent            User                `json:"agent" gorm:"foreignKey:AgentID"`
+	SourceFeedID     *uint               `json:"source_feed_id" gorm:"uniqueIndex:idx_properties_source_listing"`
+	SourceListingKey *string             `json:"source_listing_key" gorm:"uniqueIndex:idx_properties_source_listing"`
+	SourceModifiedAt *time.Time          `json:"source_modified_at"`
+	SoldPrice        *float64            `json:"sold_price"`
+	SoldAt           *time.Time          `json:"sold_at" gorm:"index"`
+	QualityScore     int                 `json:"quality_score" gorm:"not null;default:0;index"`
+	ModerationStatus ModerationStatus    `json:"moderation_status" gorm:"not null;default:'approved';index"`
+	Compliance       ListingCompliance   `json:"compliance" gorm:"embedded;embeddedPrefix:compliance_"`
+	Version          int                 `json:"version" gorm:"not null;default:1"`
+	OpenHouses       []OpenHouse         `json:"open_houses,omitempty" gorm:"foreignKey:PropertyID"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt      `json:"deleted_at,omitempty" gorm:"index"`
+	PurgedAt         *time.Time          `json:"purged_at,omitempty" gorm:"index"`
 }
 
 // PropertyType represents the type of property
@@ -46,6 +75,81 @@ const (
 	PropertyTypeCommercial PropertyType = "commercial"
 )
 
+// IsValid reports whether the property type is one of the known types
+func (t PropertyType) IsValid() bool {
+	switch t {
+	case PropertyTypeHouse, PropertyTypeCondo, PropertyTypeTownhouse,
+		PropertyTypeApartment, PropertyTypeLand, PropertyTypeCommercial:
+		return true
+	}
+	return false
+}
+
+// ListingType represents whether a property is listed for sale or for rent
+type ListingType string
+
+const (
+	ListingTypeSale ListingType = "sale"
+	ListingTypeRent ListingType = "rent"
+)
+
+// IsValid reports whether the listing type is one of the known types
+func (t ListingType) IsValid() bool {
+	return t == ListingTypeSale || t == ListingTypeRent
+}
+
+// AllowsStatus reports whether a listing of this type can take a status.
+// Sale listings close as sold and rentals close as rented.
+func (t ListingType) AllowsStatus(status PropertyStatus) bool {
+	switch status {
+	case PropertyStatusSold:
+		return t != ListingTypeRent
+	case PropertyStatusRented:
+		return t == ListingTypeRent
+	}
+	return true
+}
+
+// PetPolicy represents which pets a rental allows
+type PetPolicy string
+
+const (
+	PetPolicyNone        PetPolicy = "none"
+	PetPolicyCats        PetPolicy = "cats"
+	PetPolicyDogs        PetPolicy = "dogs"
+	PetPolicyCatsAndDogs PetPolicy = "cats_and_dogs"
+	PetPolicyNegotiable  PetPolicy = "negotiable"
+)
+
+// IsValid reports whether the pet policy is one of the known policies
+func (p PetPolicy) IsValid() bool {
+	switch p {
+	case PetPolicyNone, PetPolicyCats, PetPolicyDogs, PetPolicyCatsAndDogs, PetPolicyNegotiable:
+		return true
+	}
+	return false
+}
+
+// GeocodeAccuracy represents how precisely a listing's coordinates locate
+// it, from the building itself down to somewhere in the area
+type GeocodeAccuracy string
+
+const (
+	GeocodeAccuracyRooftop      GeocodeAccuracy = "rooftop"
+	GeocodeAccuracyParcel       GeocodeAccuracy = "parcel"
+	GeocodeAccuracyInterpolated GeocodeAccuracy = "interpolated"
+	GeocodeAccuracyApproximate  GeocodeAccuracy = "approximate"
+)
+
+// IsValid reports whether the geocode accuracy is one of the known levels
+func (a GeocodeAccuracy) IsValid() bool {
+	switch a {
+	case GeocodeAccuracyRooftop, GeocodeAccuracyParcel, GeocodeAccuracyInterpolated, GeocodeAccuracyApproximate:
+		return true
+	}
+	return false
+}
+
 // PropertyStatus represents the status of a property
 type PropertyStatus string
 
@@ -56,24 +160,80 @@ const (
 	PropertyStatusRented    PropertyStatus = "rented"
 )
 
+// IsValid reports whether the property status is one of the known statuses
+func (s PropertyStatus) IsValid() bool {
+	switch s {
+	case PropertyStatusAvailable, PropertyStatusSold, PropertyStatusPending, PropertyStatusRented:
+		return true
+	}
+	return false
+}
+
+// propertyStatusTransitions lists the statuses a listing may move to from
+// each status. Sold and rented listings can only be relisted.
+var propertyStatusTransitions = map[PropertyStatus][]PropertyStatus{
+	PropertyStatusAvailable: {PropertyStatusPending, PropertyStatusSold, PropertyStatusRented},
+	PropertyStatusPending:   {PropertyStatusAvailable, PropertyStatusSold, PropertyStatusRented},
+	PropertyStatusSold:      {PropertyStatusAvailable},
+	PropertyStatusRented:    {PropertyStatusAvailable},
+}
+
+// CanTransitionTo reports whether the status workflow allows a listing to
+// move from s to the given status. Keeping the same status is always allowed.
+func (s PropertyStatus) CanTransitionTo(to PropertyStatus) bool {
+	if s == to {
+		return true
+	}
+	for _, allowed := range propertyStatusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
 // PropertyCreateRequest represents property creation request
 type PropertyCreateRequest struct {
 	Title        string       `json:"title" binding:"required"`
 	Description  string       `json:"description"`
-	Price        float64      `json:"price" binding:"required"`
+	Price        float64      `json:"price"`
 	Address      string       `json:"address" binding:"required"`
 	City         string       `json:"city" binding:"required"`
 	State        string       `json:"state" binding:"required"`
 	ZipCode      string       `json:"zip_code" binding:"required"`
 	Country      string       `json:"country"`
 	PropertyType PropertyType `json:"property_type" binding:"required"`
+	ListingType  ListingType  `json:"listing_type"`
 	Bedrooms     int          `json:"bedrooms"`
 	Bathrooms    float64      `json:"bathrooms"`
 	SquareFeet   int          `json:"square_feet"`
 	YearBuilt    int          `json:"year_built"`
 	LotSize      float64      `json:"lot_size"`
+	Latitude     *float64     `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64     `json:"longitude" binding:"omitempty,min=-180,max=180"`
 	Features     []string     `json:"features"`
 	Images       []string     `json:"images"`
+	UnitNumber   string       `json:"unit_number" binding:"max=20"`
+
+	// GeocodeAccuracy describes how precisely the coordinates locate the
+	// property
+	GeocodeAccuracy GeocodeAccuracy `json:"geocode_accuracy"`
+
+	// Attributes are catalog attribute values by key
+	Attributes map[string]interface{} `json:"attributes"`
+
+	// Type-specific field sets; commercial and land listings have no
+	// bedrooms or bathrooms
+	Commercial *CommercialDetails `json:"commercial"`
+	Land       *LandDetails       `json:"land"`
+
+	// Rental terms apply to rent listings only. AvailableFrom is in
+	// YYYY-MM-DD format.
+	MonthlyRent     *float64  `json:"monthly_rent"`
+	SecurityDeposit *float64  `json:"security_deposit"`
+	LeaseTermMonths *int      `json:"lease_term_months"`
+	PetPolicy       PetPolicy `json:"pet_policy"`
+	AvailableFrom   string    `json:"available_from" binding:"omitempty,datetime=2006-01-02"`
 }
 
 // PropertyUpdateRequest represents property update request
@@ -87,39 +247,88 @@ type PropertyUpdateRequest struct {
 	ZipCode      *string         `json:"zip_code"`
 	Country      *string         `json:"country"`
 	PropertyType *PropertyType   `json:"property_type"`
+	ListingType  *ListingType    `json:"listing_type"`
 	Status       *PropertyStatus `json:"status"`
 	Bedrooms     *int            `json:"bedrooms"`
 	Bathrooms    *float64        `json:"bathrooms"`
 	SquareFeet   *int            `json:"square_feet"`
 	YearBuilt    *int            `json:"year_built"`
 	LotSize      *float64        `json:"lot_size"`
+	Latitude     *float64        `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64        `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	SoldPrice    *float64        `json:"sold_price" binding:"omitempty,gt=0"`
 	Features     []string        `json:"features"`
 	Images       []string        `json:"images"`
 	VRModelURL   *string         `json:"vr_model_url"`
+	UnitNumber   *string         `json:"unit_number" binding:"omitempty,max=20"`
+
+	// GeocodeAccuracy describes how precisely the coordinates locate the
+	// property
+	GeocodeAccuracy *GeocodeAccuracy `json:"geocode_accuracy"`
+
+	// Attributes replaces every catalog attribute value when given
+	Attributes map[string]interface{} `json:"attributes"`
+
+	// Type-specific field sets replace the stored set when given
+	Commercial *CommercialDetails `json:"commercial"`
+	Land       *LandDetails       `json:"land"`
+
+	// Rental terms; an empty AvailableFrom clears the availability date
+	MonthlyRent     *float64   `json:"monthly_rent"`
+	SecurityDeposit *float64   `json:"security_deposit"`
+	LeaseTermMonths *int       `json:"lease_term_months"`
+	PetPolicy       *PetPolicy `json:"pet_policy"`
+	AvailableFrom   *string    `json:"available_from"`
 }
 
 // PropertyResponse represents property response
 type PropertyResponse struct {
-	ID           uint           `json:"id"`
-	Title        string         `json:"title"`
-	Description  string         `json:"description"`
-	Price        float64        `json:"price"`
-	Address      string         `json:"address"`
-	City         string         `json:"city"`
-	State        string         `json:"state"`
-	ZipCode      string         `json:"zip_code"`
-	Country      string         `json:"country"`
-	PropertyType PropertyType   `json:"property_type"`
-	Status       PropertyStatus `json:"status"`
-	Bedrooms     int            `json:"bedrooms"`
-	Bathrooms    float64        `json:"bathrooms"`
-	SquareFeet   int            `json:"square_feet"`
-	YearBuilt    int            `json:"year_built"`
-	LotSize      float64        `json:"lot_size"`
-	Features     []string       `json:"features"`
-	Images       []string       `json:"images"`
-	VRModelURL   string         `json:"vr_model_url"`
-	Agent        UserResponse   `json:"agent"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID               uint                `json:"id"`
+	Title            string              `json:"title"`
+	Description      string              `json:"description"`
+	Price            float64             `json:"price"`
+	MonthlyRent      *float64            `json:"monthly_rent,omitempty"`
+	SecurityDeposit  *float64            `json:"security_deposit,omitempty"`
+	LeaseTermMonths  *int                `json:"lease_term_months,omitempty"`
+	PetPolicy        PetPolicy           `json:"pet_policy,omitempty"`
+	AvailableFrom    *time.Time          `json:"available_from,omitempty"`
+	Address          string              `json:"address"`
+	City             string              `json:"city"`
+	State            string              `json:"state"`
+	ZipCode          string              `json:"zip_code"`
+	Country          string              `json:"country"`
+	PropertyType     PropertyType        `json:"property_type"`
+	ListingType      ListingType         `json:"listing_type"`
+	Status           PropertyStatus      `json:"status"`
+	Bedrooms         int                 `json:"bedrooms"`
+	Bathrooms        float64             `json:"bathrooms"`
+	SquareFeet       int                 `json:"square_feet"`
+	YearBuilt        int                 `json:"year_built"`
+	LotSize          float64             `json:"lot_size"`
+	Commercial       *CommercialDetails  `json:"commercial,omitempty"`
+	Land             *LandDetails        `json:"land,omitempty"`
+	Latitude         *float64            `json:"latitude"`
+	Longitude        *float64            `json:"longitude"`
+	GeocodeAccuracy  GeocodeAccuracy     `json:"geocode_accuracy,omitempty"`
+	Features         []string            `json:"features"`
+	Attributes       []AttributeValue    `json:"attributes"`
+	Images           []string            `json:"images"`
+	VRModelURL       string              `json:"vr_model_url"`
+	DevelopmentID    *uint               `json:"development_id,omitempty"`
+	UnitNumber       string              `json:"unit_number,omitempty"`
+	Development      *DevelopmentSummary `json:"development,omitempty"`
+	Rollup           *DevelopmentRollup  `json:"rollup,omitempty"`
+	Agent            UserResponse        `json:"agent"`
+	SourceFeedID     *uint               `json:"source_feed_id,omitempty"`
+	SourceListingKey *string             `json:"source_listing_key,omitempty"`
+	SoldPrice        *float64            `json:"sold_price,omitempty"`
+	SoldAt           *time.Time          `json:"sold_at,omitempty"`
+	QualityScore     int                 `json:"quality_score"`
+	Quality          *ListingQuality     `json:"quality,omitempty"`
+	ModerationStatus ModerationStatus    `json:"moderation_status"`
+	Compliance       ListingCompliance   `json:"compliance"`
+	Version          int                 `json:"version"`
+	OpenHouses       []OpenHouseResponse `json:"open_houses"`
+	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
 }
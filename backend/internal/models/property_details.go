@@ -0,0 +1,96 @@
+package models
+
+// CommercialDetails are the fields specific to commercial listings. CapRate
+// is a percentage and NetOperatingIncome is annual.
+type CommercialDetails struct {
+	Zoning             string              `json:"zoning"`
+	UsableSquareFeet   *int                `json:"usable_square_feet"`
+	CapRate            *float64            `json:"cap_rate"`
+	NetOperatingIncome *float64            `json:"net_operating_income"`
+	LeaseType          CommercialLeaseType `json:"lease_type"`
+}
+
+// IsZero reports whether no commercial field is set
+func (d *CommercialDetails) IsZero() bool {
+	return d.Zoning == "" && d.UsableSquareFeet == nil && d.CapRate == nil &&
+		d.NetOperatingIncome == nil && d.LeaseType == ""
+}
+
+// CommercialLeaseType represents how expenses are split under a commercial
+// lease
+type CommercialLeaseType string
+
+const (
+	CommercialLeaseGross         CommercialLeaseType = "gross"
+	CommercialLeaseModifiedGross CommercialLeaseType = "modified_gross"
+	CommercialLeaseNet           CommercialLeaseType = "net"
+	CommercialLeaseDoubleNet     CommercialLeaseType = "double_net"
+	CommercialLeaseTripleNet     CommercialLeaseType = "triple_net"
+	CommercialLeaseAbsoluteNet   CommercialLeaseType = "absolute_net"
+)
+
+// IsValid reports whether the lease type is one of the known types
+func (t CommercialLeaseType) IsValid() bool {
+	switch t {
+	case CommercialLeaseGross, CommercialLeaseModifiedGross, CommercialLeaseNet,
+		CommercialLeaseDoubleNet, CommercialLeaseTripleNet, CommercialLeaseAbsoluteNet:
+		return true
+	}
+	return false
+}
+
+// LandDetails are the fields specific to land listings
+type LandDetails struct {
+	Acreage    *float64   `json:"acreage"`
+	Utilities  []Utility  `json:"utilities" gorm:"type:json"`
+	Topography Topography `json:"topography"`
+	Zoning     string     `json:"zoning"`
+}
+
+// IsZero reports whether no land field is set
+func (d *LandDetails) IsZero() bool {
+	return d.Acreage == nil && len(d.Utilities) == 0 && d.Topography == "" && d.Zoning == ""
+}
+
+// Utility represents a utility available on a lot
+type Utility string
+
+const (
+	UtilityWater       Utility = "water"
+	UtilityWell        Utility = "well"
+	UtilitySewer       Utility = "sewer"
+	UtilitySeptic      Utility = "septic"
+	UtilityElectricity Utility = "electricity"
+	UtilityGas         Utility = "gas"
+	UtilityInternet    Utility = "internet"
+)
+
+// IsValid reports whether the utility is one of the known utilities
+func (u Utility) IsValid() bool {
+	switch u {
+	case UtilityWater, UtilityWell, UtilitySewer, UtilitySeptic,
+		UtilityElectricity, UtilityGas, UtilityInternet:
+		return true
+	}
+	return false
+}
+
+// Topography represents the lie of a lot
+type Topography string
+
+const (
+	TopographyFlat    Topography = "flat"
+	TopographyGentle  Topography = "gentle_slope"
+	TopographyRolling Topography = "rolling"
+	TopographySteep   Topography = "steep"
+	TopographyMixed   Topography = "mixed"
+)
+
+// IsValid reports whether the topography is one of the known kinds
+func (t Topography) IsValid() bool {
+	switch t {
+	case TopographyFlat, TopographyGentle, TopographyRolling, TopographySteep, TopographyMixed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// PropertyRevision is a snapshot of a property's content at one version
+type PropertyRevision struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	PropertyID   uint           `json:"property_id" gorm:"not null;uniqueIndex:idx_property_revisions_version"`
+	Revision     int            `json:"revision" gorm:"not null;uniqueIndex:idx_property_revisions_version"`
+	Action       RevisionAction `json:"action" gorm:"not null"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Features     []string       `json:"features" gorm:"type:json;serializer:json"`
+	Images       []string       `json:"images" gorm:"type:json;serializer:json"`
+	EditorID     *uint          `json:"editor_id"`
+	Editor       *User          `json:"editor,omitempty" gorm:"foreignKey:EditorID"`
+	RestoredFrom *int           `json:"restored_from,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// RevisionAction records what produced a property revision
+type RevisionAction string
+
+const (
+	RevisionActionBaseline RevisionAction = "baseline"
+	RevisionActionCreated  RevisionAction = "created"
+	RevisionActionUpdated  RevisionAction = "updated"
+	RevisionActionRestored RevisionAction = "restored"
+	RevisionActionSynced   RevisionAction = "synced"
+	RevisionActionMerged   RevisionAction = "merged"
+)
+
+// RevisionFieldChange describes how one content field differs between two
+// revisions. Added and Removed are set for list fields.
+type RevisionFieldChange struct {
+	Field   string      `json:"field"`
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+// PropertyRevisionResponse represents property revision response. Changes
+// are relative to the previous revision.
+type PropertyRevisionResponse struct {
+	ID           uint                  `json:"id"`
+	PropertyID   uint                  `json:"property_id"`
+	Revision     int                   `json:"revision"`
+	Action       RevisionAction        `json:"action"`
+	Title        string                `json:"title"`
+	Description  string                `json:"description"`
+	Features     []string              `json:"features"`
+	Images       []string              `json:"images"`
+	Editor       *UserResponse         `json:"editor,omitempty"`
+	RestoredFrom *int                  `json:"restored_from,omitempty"`
+	Changes      []RevisionFieldChange `json:"changes"`
+	CreatedAt    time.Time             `json:"created_at"`
+}
+
+// PropertyRevisionDiff represents the changes between two property revisions
+type PropertyRevisionDiff struct {
+	PropertyID   uint                  `json:"property_id"`
+	FromRevision int                   `json:"from_revision"`
+	ToRevision   int                   `json:"to_revision"`
+	Changes      []RevisionFieldChange `json:"changes"`
+}
+
+// PropertyRevisionDiffRequest represents the revisions to compare
+type PropertyRevisionDiffRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// QualityCheck is one item of a listing's completeness checklist. Points
+// are out of MaxPoints, and Suggestion says how to earn the rest.
+type QualityCheck struct {
+	Key        string `json:"key"`
+	Label      string `json:"label"`
+	Points     int    `json:"points"`
+	MaxPoints  int    `json:"max_points"`
+	Complete   bool   `json:"complete"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ListingQuality scores how complete a listing is, out of 100, with the
+// checklist behind the score. Suggestions lists the open checklist items,
+// worth the most points first.
+type ListingQuality struct {
+	PropertyID  uint           `json:"property_id"`
+	Score       int            `json:"score"`
+	Checklist   []QualityCheck `json:"checklist"`
+	Suggestions []string       `json:"suggestions"`
+}
+
+// QualityRefreshResult reports how many listing scores a refresh changed
+type QualityRefreshResult struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// ReportMetric names a platform metric admins can report on
+type ReportMetric string
+
+const (
+	ReportRegistrations    ReportMetric = "registrations"
+	ReportListingsCreated  ReportMetric = "listings_created"
+	ReportListingsSold     ReportMetric = "listings_sold"
+	ReportMediaStorage     ReportMetric = "media_storage"
+	ReportVRToursPublished ReportMetric = "vr_tours_published"
+	ReportActiveAgents     ReportMetric = "active_agents"
+)
+
+// ReportRequest represents a report query. The range works as for
+// analytics but may span up to five years; GroupBy splits each period by
+// one of the metric's dimensions. Format selects a csv or xlsx download
+// instead of JSON.
+type ReportRequest struct {
+	AnalyticsRequest
+	GroupBy string `form:"group_by"`
+	Format  string `form:"format"`
+}
+
+// ReportRow is a metric's figure for one period and group. Value is set
+// for metrics that also sum an amount, such as sales volume or bytes.
+type ReportRow struct {
+	Period time.Time `json:"period"`
+	Group  string    `json:"group,omitempty"`
+	Count  int64     `json:"count"`
+	Value  *float64  `json:"value,omitempty"`
+}
+
+// ReportTotal is a metric's figure for one group over the whole range
+type ReportTotal struct {
+	Group string   `json:"group,omitempty"`
+	Count int64    `json:"count"`
+	Value *float64 `json:"value,omitempty"`
+}
+
+// Report is a metric aggregated over a date range. Rows only cover periods
+// with data.
+type Report struct {
+	Metric     ReportMetric      `json:"metric"`
+	Label      string            `json:"label"`
+	ValueLabel string            `json:"value_label,omitempty"`
+	From       time.Time         `json:"from"`
+	To         time.Time         `json:"to"`
+	Interval   AnalyticsInterval `json:"interval"`
+	GroupBy    string            `json:"group_by,omitempty"`
+	Rows       []ReportRow       `json:"rows"`
+	Totals     []ReportTotal     `json:"totals"`
+}
+
+// ReportMetricInfo describes a metric and the dimensions it can be grouped
+// by
+type ReportMetricInfo struct {
+	Metric      ReportMetric `json:"metric"`
+	Label       string       `json:"label"`
+	Description string       `json:"description"`
+	ValueLabel  string       `json:"value_label,omitempty"`
+	Dimensions  []string     `json:"dimensions"`
+}
+
+// ReportFrequency is how often a scheduled report is sent
+type ReportFrequency string
+
+const (
+	ReportFrequencyDaily   ReportFrequency = "daily"
+	ReportFrequencyWeekly  ReportFrequency = "weekly"
+	ReportFrequencyMonthly ReportFrequency = "monthly"
+)
+
+// Interval returns the period a report of this frequency covers
+func (f ReportFrequency) Interval() AnalyticsInterval {
+	switch f {
+	case ReportFrequencyWeekly:
+		return AnalyticsIntervalWeek
+	case ReportFrequencyMonthly:
+		return AnalyticsIntervalMonth
+	default:
+		return AnalyticsIntervalDay
+	}
+}
+
+// IsValid reports whether the frequency is known
+func (f ReportFrequency) IsValid() bool {
+	return f == ReportFrequencyDaily || f == ReportFrequencyWeekly || f == ReportFrequencyMonthly
+}
+
+// ReportSchedule emails a report to its recipients after every full day,
+// week (Monday to Sunday) or calendar month, in UTC. Each report covers the
+// period just ended, broken down by day.
+type ReportSchedule struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	Name          string          `json:"name" gorm:"not null"`
+	Metric        ReportMetric    `json:"metric" gorm:"not null"`
+	GroupBy       string          `json:"group_by"`
+	Frequency     ReportFrequency `json:"frequency" gorm:"not null"`
+	Recipients    []string        `json:"recipients" gorm:"type:json;serializer:json"`
+	IsActive      bool            `json:"is_active" gorm:"not null"`
+	NextRunAt     time.Time       `json:"next_run_at" gorm:"not null;index"`
+	LastRunAt     *time.Time      `json:"last_run_at"`
+	LastRunStatus string          `json:"last_run_status"`
+	LastRunError  string          `json:"last_run_error"`
+	CreatedByID   uint            `json:"created_by_id" gorm:"not null"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// ReportScheduleRequest represents a report schedule creation or update
+type ReportScheduleRequest struct {
+	Name       string          `json:"name" binding:"required"`
+	Metric     ReportMetric    `json:"metric" binding:"required"`
+	GroupBy    string          `json:"group_by"`
+	Frequency  ReportFrequency `json:"frequency" binding:"required"`
+	Recipients []string        `json:"recipients" binding:"required,min=1,dive,email"`
+	IsActive   *bool           `json:"is_active"`
+}
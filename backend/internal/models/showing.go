@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+)
+
+// AgentAvailability holds an agent's private showing availability rules.
+// Weekly hours are wall-clock times in TimeZone.
+type AgentAvailability struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	AgentID       uint          `json:"agent_id" gorm:"not null;uniqueIndex"`
+	TimeZone      string        `json:"time_zone" gorm:"not null"`
+	SlotMinutes   int           `json:"slot_minutes" gorm:"not null;default:30"`
+	BufferMinutes int           `json:"buffer_minutes" gorm:"not null;default:0"`
+	NoticeMinutes int           `json:"notice_minutes" gorm:"not null;default:60"`
+	WeeklyHours   []WeeklyHours `json:"weekly_hours" gorm:"type:json;serializer:json"`
+	CalendarToken string        `json:"-" gorm:"uniqueIndex;not null"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// WeeklyHours is a recurring window of availability. Weekday is 0 for
+// Sunday through 6 for Saturday; Start and End are "HH:MM" local times.
+type WeeklyHours struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"`
+	Start   string `json:"start" binding:"required"`
+	End     string `json:"end" binding:"required"`
+}
+
+// AvailabilityBlackout blocks showings on a range of the agent's local dates.
+// StartsAt and EndsAt are the UTC instants covering those dates.
+type AvailabilityBlackout struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AgentID   uint      `json:"agent_id" gorm:"not null;index"`
+	StartDate string    `json:"start_date" gorm:"not null"`
+	EndDate   string    `json:"end_date" gorm:"not null"`
+	StartsAt  time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt    time.Time `json:"ends_at" gorm:"not null"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Showing represents a private showing appointment. AgentID is copied from
+// the property so conflicts can be checked across all the agent's listings.
+type Showing struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	PropertyID   uint          `json:"property_id" gorm:"not null;index"`
+	Property     Property      `json:"property" gorm:"foreignKey:PropertyID"`
+	AgentID      uint          `json:"agent_id" gorm:"not null;index:idx_showings_agent_time"`
+	Agent        User          `json:"agent" gorm:"foreignKey:AgentID"`
+	BuyerID      uint          `json:"buyer_id" gorm:"not null;index"`
+	Buyer        User          `json:"buyer" gorm:"foreignKey:BuyerID"`
+	StartsAt     time.Time     `json:"starts_at" gorm:"not null;index:idx_showings_agent_time"`
+	EndsAt       time.Time     `json:"ends_at" gorm:"not null"`
+	TimeZone     string        `json:"time_zone" gorm:"not null"`
+	Status       ShowingStatus `json:"status" gorm:"not null;index"`
+	Message      string        `json:"message"`
+	CancelReason string        `json:"cancel_reason"`
+	CancelledBy  *uint         `json:"cancelled_by"`
+	Sequence     int           `json:"sequence" gorm:"not null;default:0"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// ShowingStatus represents the status of a showing
+type ShowingStatus string
+
+const (
+	ShowingStatusRequested   ShowingStatus = "requested"
+	ShowingStatusConfirmed   ShowingStatus = "confirmed"
+	ShowingStatusRescheduled ShowingStatus = "rescheduled"
+	ShowingStatusCancelled   ShowingStatus = "cancelled"
+)
+
+// AgentAvailabilityRequest represents an availability rules update
+type AgentAvailabilityRequest struct {
+	TimeZone      string        `json:"time_zone" binding:"required"`
+	SlotMinutes   int           `json:"slot_minutes" binding:"omitempty,min=15,max=240"`
+	BufferMinutes int           `json:"buffer_minutes" binding:"min=0,max=240"`
+	NoticeMinutes int           `json:"notice_minutes" binding:"min=0,max=10080"`
+	WeeklyHours   []WeeklyHours `json:"weekly_hours" binding:"dive"`
+}
+
+// AvailabilityBlackoutRequest represents a blackout creation request.
+// Dates are inclusive and in YYYY-MM-DD format.
+type AvailabilityBlackoutRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// AgentAvailabilityResponse represents agent availability response
+type AgentAvailabilityResponse struct {
+	AgentID       uint                   `json:"agent_id"`
+	TimeZone      string                 `json:"time_zone"`
+	SlotMinutes   int                    `json:"slot_minutes"`
+	BufferMinutes int                    `json:"buffer_minutes"`
+	NoticeMinutes int                    `json:"notice_minutes"`
+	WeeklyHours   []WeeklyHours          `json:"weekly_hours"`
+	Blackouts     []AvailabilityBlackout `json:"blackouts"`
+	CalendarURL   string                 `json:"calendar_url"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// ShowingSlotsRequest represents a slot search. Dates are in the agent's
+// time zone; the range may span at most 14 days.
+type ShowingSlotsRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+// ShowingSlot represents a bookable showing time
+type ShowingSlot struct {
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	LocalStart string    `json:"local_start"`
+}
+
+// ShowingCreateRequest represents a showing request. StartsAt must be one
+// of the property's available slots.
+type ShowingCreateRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	Message  string    `json:"message"`
+}
+
+// ShowingRescheduleRequest represents a showing reschedule request
+type ShowingRescheduleRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	Message  string    `json:"message"`
+}
+
+// ShowingCancelRequest represents a showing cancellation request
+type ShowingCancelRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ShowingListRequest represents a showing list request
+type ShowingListRequest struct {
+	Status   *ShowingStatus `form:"status"`
+	Upcoming bool           `form:"upcoming"`
+	Page     int            `form:"page"`
+	PageSize int            `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// ShowingResponse represents showing response
+type ShowingResponse struct {
+	ID            uint          `json:"id"`
+	PropertyID    uint          `json:"property_id"`
+	PropertyTitle string        `json:"property_title"`
+	Address       string        `json:"address"`
+	AgentID       uint          `json:"agent_id"`
+	Buyer         UserResponse  `json:"buyer"`
+	StartsAt      time.Time     `json:"starts_at"`
+	EndsAt        time.Time     `json:"ends_at"`
+	LocalStart    string        `json:"local_start"`
+	TimeZone      string        `json:"time_zone"`
+	Status        ShowingStatus `json:"status"`
+	Message       string        `json:"message"`
+	CancelReason  string        `json:"cancel_reason,omitempty"`
+	CancelledBy   *uint         `json:"cancelled_by,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// SyndicationPartner represents a portal or partner site that consumes listing feeds
+type SyndicationPartner struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	FeedKey   string    `json:"feed_key" gorm:"uniqueIndex;not null"`
+	Fields    []string  `json:"fields" gorm:"type:json;serializer:json"`
+	IsActive  bool      `json:"is_active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyndicationPartnerCreateRequest represents syndication partner creation request.
+// Fields lists the RESO Data Dictionary fields the partner may receive; an
+// empty list means every field.
+type SyndicationPartnerCreateRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Fields []string `json:"fields"`
+}
+
+// SyndicationPartnerUpdateRequest represents syndication partner update request
+type SyndicationPartnerUpdateRequest struct {
+	Name     *string  `json:"name"`
+	Fields   []string `json:"fields"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// SyndicationPartnerResponse represents syndication partner response
+type SyndicationPartnerResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	FeedKey   string    `json:"feed_key"`
+	Fields    []string  `json:"fields"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FeedFormat represents an outbound listing feed format
+type FeedFormat string
+
+const (
+	FeedFormatRESO FeedFormat = "reso"
+	FeedFormatXML  FeedFormat = "xml"
+	FeedFormatRSS  FeedFormat = "rss"
+	FeedFormatAtom FeedFormat = "atom"
+)
+
+// FeedRequest represents the parameters of a feed request
+type FeedRequest struct {
+	Since *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit int        `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+)
+
+// Transaction tracks a sale from an accepted offer to closing. Both the
+// buyer and the listing agent can see it and its milestones.
+type Transaction struct {
+	ID          uint                   `json:"id" gorm:"primaryKey"`
+	PropertyID  uint                   `json:"property_id" gorm:"not null;index"`
+	Property    Property               `json:"property" gorm:"foreignKey:PropertyID"`
+	OfferID     uint                   `json:"offer_id" gorm:"not null;uniqueIndex"`
+	BuyerID     uint                   `json:"buyer_id" gorm:"not null;index"`
+	Buyer       User                   `json:"buyer" gorm:"foreignKey:BuyerID"`
+	AgentID     uint                   `json:"agent_id" gorm:"not null;index"`
+	Price       float64                `json:"price" gorm:"not null"`
+	ClosingDate *time.Time             `json:"closing_date" gorm:"type:date"`
+	Status      TransactionStatus      `json:"status" gorm:"not null;index"`
+	Milestones  []TransactionMilestone `json:"milestones" gorm:"foreignKey:TransactionID"`
+	ClosedAt    *time.Time             `json:"closed_at"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// TransactionStatus represents the status of a transaction
+type TransactionStatus string
+
+const (
+	TransactionStatusOpen      TransactionStatus = "open"
+	TransactionStatusClosed    TransactionStatus = "closed"
+	TransactionStatusCancelled TransactionStatus = "cancelled"
+)
+
+// TransactionMilestone is a checklist item of a transaction
+type TransactionMilestone struct {
+	ID            uint          `json:"id" gorm:"primaryKey"`
+	TransactionID uint          `json:"transaction_id" gorm:"not null;index"`
+	Type          MilestoneType `json:"type" gorm:"not null"`
+	Title         string        `json:"title" gorm:"not null"`
+	DueDate       *time.Time    `json:"due_date" gorm:"type:date"`
+	CompletedAt   *time.Time    `json:"completed_at"`
+	CompletedBy   *uint         `json:"completed_by"`
+	SortOrder     int           `json:"sort_order"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// MilestoneType represents the kind of a transaction milestone
+type MilestoneType string
+
+const (
+	MilestoneInspection MilestoneType = "inspection"
+	MilestoneAppraisal  MilestoneType = "appraisal"
+	MilestoneFinancing  MilestoneType = "financing"
+	MilestoneClosing    MilestoneType = "closing"
+	MilestoneCustom     MilestoneType = "custom"
+)
+
+// MilestoneCreateRequest adds a custom milestone. DueDate is in YYYY-MM-DD
+// format.
+type MilestoneCreateRequest struct {
+	Title   string `json:"title" binding:"required,max=200"`
+	DueDate string `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// MilestoneUpdateRequest updates a milestone's due date or completion. An
+// empty DueDate clears it.
+type MilestoneUpdateRequest struct {
+	DueDate   *string `json:"due_date"`
+	Completed *bool   `json:"completed"`
+}
+
+// TransactionListRequest represents a transaction list request
+type TransactionListRequest struct {
+	Status   *TransactionStatus `form:"status"`
+	Page     int                `form:"page"`
+	PageSize int                `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// TransactionResponse represents transaction response
+type TransactionResponse struct {
+	ID            uint                   `json:"id"`
+	PropertyID    uint                   `json:"property_id"`
+	PropertyTitle string                 `json:"property_title"`
+	Address       string                 `json:"address"`
+	OfferID       uint                   `json:"offer_id"`
+	BuyerID       uint                   `json:"buyer_id"`
+	BuyerName     string                 `json:"buyer_name"`
+	AgentID       uint                   `json:"agent_id"`
+	Price         float64                `json:"price"`
+	ClosingDate   *time.Time             `json:"closing_date"`
+	Status        TransactionStatus      `json:"status"`
+	Milestones    []TransactionMilestone `json:"milestones"`
+	ClosedAt      *time.Time             `json:"closed_at"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// TrashItemType identifies the kind of record in the trash
+type TrashItemType string
+
+const (
+	TrashItemProperty TrashItemType = "property"
+	TrashItemMedia    TrashItemType = "media"
+	TrashItemVRTour   TrashItemType = "vr_tour"
+)
+
+// IsValid reports whether the trash item type is one of the known types
+func (t TrashItemType) IsValid() bool {
+	switch t {
+	case TrashItemProperty, TrashItemMedia, TrashItemVRTour:
+		return true
+	}
+	return false
+}
+
+// TrashItem represents a soft-deleted record that can still be restored.
+// PurgeAt is when the retention job will delete it permanently, if enabled.
+type TrashItem struct {
+	Type       TrashItemType `json:"type"`
+	ID         uint          `json:"id"`
+	PropertyID uint          `json:"property_id"`
+	Title      string        `json:"title"`
+	DeletedAt  time.Time     `json:"deleted_at"`
+	PurgeAt    *time.Time    `json:"purge_at,omitempty"`
+}
+
+// TrashRequest represents trash listing request
+type TrashRequest struct {
+	Type     TrashItemType `form:"type"`
+	Page     int           `form:"page"`
+	PageSize int           `form:"page_size" binding:"omitempty,max=100"`
+}
+
+// TrashPurgeResult reports how many records a purge permanently deleted
+type TrashPurgeResult struct {
+	Properties int `json:"properties"`
+	MediaFiles int `json:"media_files"`
+	VRTours    int `json:"vr_tours"`
+	Revisions  int `json:"revisions"`
+	FilesFreed int `json:"files_freed"`
+}
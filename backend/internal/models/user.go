@@ -8,18 +8,21 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	FirstName string         `json:"first_name" gorm:"not null"`
-	LastName  string         `json:"last_name" gorm:"not null"`
-	Role      UserRole       `json:"role" gorm:"not null;default:'buyer'"`
-	Phone     string         `json:"phone"`
-	Avatar    string         `json:"avatar"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Email       string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password    string         `json:"-" gorm:"not null"`
+	FirstName   string         `json:"first_name" gorm:"not null"`
+	LastName    string         `json:"last_name" gorm:"not null"`
+	Role        UserRole       `json:"role" gorm:"not null;default:'buyer'"`
+	Phone       string         `json:"phone"`
+	Avatar      string         `json:"avatar"`
+	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	BrokerageID *uint          `json:"brokerage_id" gorm:"index"`
+	IsBroker    bool           `json:"is_broker" gorm:"not null;default:false"`
+	Version     int            `json:"version" gorm:"not null;default:1"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // UserRole represents the role of a user
@@ -48,16 +51,27 @@ type UserRegisterRequest struct {
 	Phone     string   `json:"phone"`
 }
 
+// UserProfileUpdateRequest represents profile update request
+type UserProfileUpdateRequest struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Phone     *string `json:"phone"`
+	Avatar    *string `json:"avatar"`
+}
+
 // UserResponse represents user response without sensitive data
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Email     string    `json:"email"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Role      UserRole  `json:"role"`
-	Phone     string    `json:"phone"`
-	Avatar    string    `json:"avatar"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint      `json:"id"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	Role        UserRole  `json:"role"`
+	Phone       string    `json:"phone"`
+	Avatar      string    `json:"avatar"`
+	IsActive    bool      `json:"is_active"`
+	BrokerageID *uint     `json:"brokerage_id,omitempty"`
+	IsBroker    bool      `json:"is_broker,omitempty"`
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
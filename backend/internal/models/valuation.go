@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+)
+
+// ValuationAdjustments are the dollar amounts a comparable sale's price is
+// adjusted by per unit of difference from the subject property.
+// MonthlyAppreciation is the fraction the market moves per month and brings
+// older sales up to date.
+type ValuationAdjustments struct {
+	PerSquareFoot       float64 `json:"per_square_foot" binding:"min=0"`
+	PerBedroom          float64 `json:"per_bedroom" binding:"min=0"`
+	PerBathroom         float64 `json:"per_bathroom" binding:"min=0"`
+	PerYearBuilt        float64 `json:"per_year_built" binding:"min=0"`
+	PerLotAcre          float64 `json:"per_lot_acre" binding:"min=0"`
+	MonthlyAppreciation float64 `json:"monthly_appreciation" binding:"min=-0.05,max=0.05"`
+}
+
+// ValuationOptions controls how comparable sales are searched for
+type ValuationOptions struct {
+	RadiusMiles float64 `json:"radius_miles" form:"radius_miles" binding:"omitempty,gt=0,max=25"`
+	Months      int     `json:"months" form:"months" binding:"omitempty,min=1,max=36"`
+	MaxComps    int     `json:"max_comps" form:"max_comps" binding:"omitempty,min=1,max=20"`
+}
+
+// ValuationRequest asks for a valuation of a property described by its
+// address and attributes. Comparable sales are found by distance when
+// coordinates are given, otherwise by zip code, otherwise by city.
+type ValuationRequest struct {
+	ValuationOptions
+	Address      string                `json:"address"`
+	City         string                `json:"city"`
+	State        string                `json:"state"`
+	ZipCode      string                `json:"zip_code"`
+	Latitude     *float64              `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude    *float64              `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	PropertyType PropertyType          `json:"property_type" binding:"required"`
+	Bedrooms     int                   `json:"bedrooms" binding:"min=0"`
+	Bathrooms    float64               `json:"bathrooms" binding:"min=0"`
+	SquareFeet   int                   `json:"square_feet" binding:"min=0"`
+	YearBuilt    int                   `json:"year_built" binding:"min=0"`
+	LotSize      float64               `json:"lot_size" binding:"min=0"`
+	Adjustments  *ValuationAdjustments `json:"adjustments"`
+}
+
+// PriceAdjustment is one adjustment made to a comparable sale's price
+type PriceAdjustment struct {
+	Reason string  `json:"reason"`
+	Amount float64 `json:"amount"`
+}
+
+// ComparableSale is a sold property used in a valuation. DistanceMiles is
+// nil when either property has no coordinates.
+type ComparableSale struct {
+	PropertyID    uint              `json:"property_id"`
+	Title         string            `json:"title"`
+	Address       string            `json:"address"`
+	City          string            `json:"city"`
+	SoldPrice     float64           `json:"sold_price"`
+	SoldAt        time.Time         `json:"sold_at"`
+	Bedrooms      int               `json:"bedrooms"`
+	Bathrooms     float64           `json:"bathrooms"`
+	SquareFeet    int               `json:"square_feet"`
+	YearBuilt     int               `json:"year_built"`
+	LotSize       float64           `json:"lot_size"`
+	DistanceMiles *float64          `json:"distance_miles"`
+	Adjustments   []PriceAdjustment `json:"adjustments"`
+	AdjustedPrice float64           `json:"adjusted_price"`
+	Weight        float64           `json:"weight"`
+}
+
+// ValueEstimate is an estimated value with its likely range
+type ValueEstimate struct {
+	Value        float64  `json:"value"`
+	Low          float64  `json:"low"`
+	High         float64  `json:"high"`
+	PricePerSqFt *float64 `json:"price_per_sqft"`
+	Confidence   string   `json:"confidence"`
+}
+
+// ValuationResponse represents a valuation. Estimate is nil when no
+// comparable sales were found.
+type ValuationResponse struct {
+	PropertyID  *uint                `json:"property_id,omitempty"`
+	Estimate    *ValueEstimate       `json:"estimate"`
+	Comps       []ComparableSale     `json:"comps"`
+	MatchedBy   string               `json:"matched_by"`
+	Options     ValuationOptions     `json:"options"`
+	Adjustments ValuationAdjustments `json:"adjustments"`
+}
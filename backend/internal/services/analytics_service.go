@@ -0,0 +1,384 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Analytics query limits
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 366
+	// similarListingsPriceBand is how far a similar listing's price may be
+	// from the compared listing's, as a fraction of its price
+	similarListingsPriceBand = 0.2
+	// similarListingsLimit caps how many similar listings, closest in price
+	// first, a listing is compared with
+	similarListingsLimit = 100
+)
+
+// funnelStages are the steps of the conversion funnel, in order
+var funnelStages = []models.ListingEventType{
+	models.ListingEventView,
+	models.ListingEventMediaView,
+	models.ListingEventTourOpen,
+	models.ListingEventInquiry,
+}
+
+// AnalyticsService handles listing and agent performance analytics
+type AnalyticsService struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(db *gorm.DB) *AnalyticsService {
+	return &AnalyticsService{db: db}
+}
+
+// listingEventCount is an aggregate row of listing events
+type listingEventCount struct {
+	PropertyID uint
+	Period     time.Time
+	Type       models.ListingEventType
+	Events     int64
+	Visitors   int64
+}
+
+// analyticsRange is a validated analytics query: the half-open range
+// [from, end) and the series bucket size
+type analyticsRange struct {
+	from     time.Time
+	to       time.Time
+	end      time.Time
+	interval models.AnalyticsInterval
+}
+
+// GetListingAnalytics reports a listing's performance to its agent
+func (s *AnalyticsService) GetListingAnalytics(propertyID, agentID uint, req *models.AnalyticsRequest) (*models.ListingAnalytics, error) {
+	r, err := resolveAnalyticsRange(req, time.Now(), analyticsMaxDays)
+	if err != nil {
+		return nil, err
+	}
+
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	ids := []uint{property.ID}
+	totals, funnel, err := s.summarize(ids, r)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.series(ids, r)
+	if err != nil {
+		return nil, err
+	}
+	comparison, err := s.compare(&property, totals, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ListingAnalytics{
+		PropertyID: property.ID,
+		From:       r.from,
+		To:         r.to,
+		Interval:   r.interval,
+		Totals:     totals,
+		Series:     series,
+		Funnel:     funnel,
+		Comparison: comparison,
+	}, nil
+}
+
+// GetAgentAnalytics reports the combined performance of an agent's
+// listings
+func (s *AnalyticsService) GetAgentAnalytics(agentID uint, req *models.AnalyticsRequest) (*models.AgentAnalytics, error) {
+	r, err := resolveAnalyticsRange(req, time.Now(), analyticsMaxDays)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []models.Property
+	if err := s.db.Select("id", "title").Where("agent_id = ?", agentID).Order("id").Find(&properties).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(properties))
+	for i, property := range properties {
+		ids[i] = property.ID
+	}
+
+	totals, funnel, err := s.summarize(ids, r)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.series(ids, r)
+	if err != nil {
+		return nil, err
+	}
+
+	byListing := make(map[uint]*models.ListingEventTotals, len(properties))
+	if len(ids) > 0 {
+		var counts []listingEventCount
+		if err := s.eventsIn(ids, r).
+			Select("property_id, type, COUNT(*) AS events, COUNT(DISTINCT visitor_id) AS visitors").
+			Group("property_id, type").
+			Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+		for _, count := range counts {
+			if byListing[count.PropertyID] == nil {
+				byListing[count.PropertyID] = &models.ListingEventTotals{}
+			}
+			addEventCount(byListing[count.PropertyID], count)
+		}
+	}
+
+	listings := make([]models.ListingPerformance, len(properties))
+	for i, property := range properties {
+		listings[i] = models.ListingPerformance{PropertyID: property.ID, Title: property.Title}
+		if listingTotals := byListing[property.ID]; listingTotals != nil {
+			listings[i].Totals = *listingTotals
+			listings[i].InquiryRate = ratio(listingTotals.Inquiries, listingTotals.UniqueVisitors)
+		}
+	}
+	sort.SliceStable(listings, func(i, j int) bool {
+		return listings[i].Totals.Views > listings[j].Totals.Views
+	})
+
+	return &models.AgentAnalytics{
+		AgentID:  agentID,
+		From:     r.from,
+		To:       r.to,
+		Interval: r.interval,
+		Totals:   totals,
+		Series:   series,
+		Funnel:   funnel,
+		Listings: listings,
+	}, nil
+}
+
+// summarize totals the events of listings over the range and builds the
+// conversion funnel from the unique visitors at each stage
+func (s *AnalyticsService) summarize(ids []uint, r *analyticsRange) (models.ListingEventTotals, []models.FunnelStage, error) {
+	var totals models.ListingEventTotals
+	visitors := make(map[models.ListingEventType]int64)
+	if len(ids) > 0 {
+		var counts []listingEventCount
+		if err := s.eventsIn(ids, r).
+			Select("type, COUNT(*) AS events, COUNT(DISTINCT visitor_id) AS visitors").
+			Group("type").
+			Scan(&counts).Error; err != nil {
+			return totals, nil, err
+		}
+		for _, count := range counts {
+			addEventCount(&totals, count)
+			visitors[count.Type] = count.Visitors
+		}
+	}
+
+	funnel := make([]models.FunnelStage, len(funnelStages))
+	for i, stage := range funnelStages {
+		funnel[i] = models.FunnelStage{
+			Stage:    stage,
+			Visitors: visitors[stage],
+			Rate:     ratio(visitors[stage], totals.UniqueVisitors),
+		}
+	}
+	return totals, funnel, nil
+}
+
+// series buckets the events of listings by the range's interval. Every
+// bucket in the range is returned, including empty ones.
+func (s *AnalyticsService) series(ids []uint, r *analyticsRange) ([]models.AnalyticsPoint, error) {
+	var points []models.AnalyticsPoint
+	index := make(map[time.Time]int)
+	for period := r.from; period.Before(r.end); period = nextPeriod(period, r.interval) {
+		index[period] = len(points)
+		points = append(points, models.AnalyticsPoint{Period: period})
+	}
+	if len(ids) == 0 {
+		return points, nil
+	}
+
+	var counts []listingEventCount
+	if err := s.eventsIn(ids, r).
+		Select("date_trunc(?, occurred_at AT TIME ZONE 'UTC') AS period, type, COUNT(*) AS events, COUNT(DISTINCT visitor_id) AS visitors", string(r.interval)).
+		Group("1, 2").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		// The first bucket may start before the range when it does not
+		// begin on a bucket boundary
+		period := time.Date(count.Period.Year(), count.Period.Month(), count.Period.Day(), 0, 0, 0, 0, time.UTC)
+		if period.Before(r.from) {
+			period = r.from
+		}
+		if i, ok := index[period]; ok {
+			addEventCount(&points[i].ListingEventTotals, count)
+		}
+	}
+	return points, nil
+}
+
+// compare compares a listing's totals with the average of similar live
+// listings over the same range
+func (s *AnalyticsService) compare(property *models.Property, totals models.ListingEventTotals, r *analyticsRange) (models.ListingComparison, error) {
+	var comparison models.ListingComparison
+
+	priceColumn, price := "price", property.Price
+	if property.ListingType == models.ListingTypeRent && property.MonthlyRent != nil {
+		priceColumn, price = "COALESCE(monthly_rent, price)", *property.MonthlyRent
+	}
+
+	var ids []uint
+	if err := publishedListings(s.db.Model(&models.Property{})).
+		Where("id <> ?", property.ID).
+		Where("property_type = ? AND listing_type = ?", property.PropertyType, property.ListingType).
+		Where("LOWER(city) = ? AND LOWER(state) = ?", strings.ToLower(property.City), strings.ToLower(property.State)).
+		Where(priceColumn+" BETWEEN ? AND ?", price*(1-similarListingsPriceBand), price*(1+similarListingsPriceBand)).
+		Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "ABS(" + priceColumn + " - ?), id", Vars: []interface{}{price}, WithoutParentheses: true}}).
+		Limit(similarListingsLimit).
+		Pluck("id", &ids).Error; err != nil {
+		return comparison, err
+	}
+	comparison.SimilarListings = len(ids)
+	if len(ids) == 0 {
+		return comparison, nil
+	}
+
+	// Unique visitors are counted per listing so the average matches what
+	// each listing would report on its own
+	var counts []listingEventCount
+	if err := s.eventsIn(ids, r).
+		Select("type, COUNT(*) AS events, COUNT(DISTINCT (property_id, visitor_id)) AS visitors").
+		Group("type").
+		Scan(&counts).Error; err != nil {
+		return comparison, err
+	}
+	var sum models.ListingEventTotals
+	for _, count := range counts {
+		addEventCount(&sum, count)
+	}
+
+	n := int64(len(ids))
+	comparison.Average = models.ListingEventAverages{
+		Views:          average(sum.Views, n),
+		UniqueVisitors: average(sum.UniqueVisitors, n),
+		MediaViews:     average(sum.MediaViews, n),
+		TourOpens:      average(sum.TourOpens, n),
+		Inquiries:      average(sum.Inquiries, n),
+	}
+	comparison.AverageInquiryRate = ratio(sum.Inquiries, sum.UniqueVisitors)
+	comparison.ViewsIndex = analyticsIndex(totals.Views, sum.Views, n)
+	comparison.InquiriesIndex = analyticsIndex(totals.Inquiries, sum.Inquiries, n)
+	return comparison, nil
+}
+
+// eventsIn queries the events of listings within the range
+func (s *AnalyticsService) eventsIn(ids []uint, r *analyticsRange) *gorm.DB {
+	return s.db.Model(&models.ListingEvent{}).
+		Where("property_id IN ? AND occurred_at >= ? AND occurred_at < ?", ids, r.from, r.end)
+}
+
+// resolveAnalyticsRange validates an analytics query and applies its
+// defaults. The range may span at most maxDays.
+func resolveAnalyticsRange(req *models.AnalyticsRequest, now time.Time, maxDays int) (*analyticsRange, error) {
+	r := &analyticsRange{interval: req.Interval}
+	if r.interval == "" {
+		r.interval = models.AnalyticsIntervalDay
+	}
+
+	var fieldErrors []models.FieldError
+	if !r.interval.IsValid() {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "interval", Message: "must be day, week or month"})
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	r.to = today
+	if req.To != nil {
+		r.to = time.Date(req.To.Year(), req.To.Month(), req.To.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	r.from = r.to.AddDate(0, 0, 1-analyticsDefaultDays)
+	if req.From != nil {
+		r.from = time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	r.end = r.to.AddDate(0, 0, 1)
+
+	switch {
+	case r.from.After(r.to):
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "from", Message: "must not be after to"})
+	case r.end.Sub(r.from) > time.Duration(maxDays)*24*time.Hour:
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "from", Message: fmt.Sprintf("range may span at most %d days", maxDays)})
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+	return r, nil
+}
+
+// nextPeriod returns the start of the bucket after the one containing t.
+// Weeks start on Monday, as they do in Postgres.
+func nextPeriod(t time.Time, interval models.AnalyticsInterval) time.Time {
+	switch interval {
+	case models.AnalyticsIntervalWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, 7-daysSinceMonday)
+	case models.AnalyticsIntervalMonth:
+		return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// addEventCount adds an aggregate row to totals. Unique visitors are the
+// visitors who viewed the listing.
+func addEventCount(totals *models.ListingEventTotals, count listingEventCount) {
+	switch count.Type {
+	case models.ListingEventView:
+		totals.Views += count.Events
+		totals.UniqueVisitors += count.Visitors
+	case models.ListingEventMediaView:
+		totals.MediaViews += count.Events
+	case models.ListingEventTourOpen:
+		totals.TourOpens += count.Events
+	case models.ListingEventInquiry:
+		totals.Inquiries += count.Events
+	}
+}
+
+// ratio returns part/whole rounded to four places, or 0 when whole is 0
+func ratio(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 10000
+}
+
+// average returns sum/n rounded to two places
+func average(sum, n int64) float64 {
+	return math.Round(float64(sum)/float64(n)*100) / 100
+}
+
+// analyticsIndex compares a listing's figure with the average of n similar
+// listings, or returns nil when the average is 0
+func analyticsIndex(value, sum, n int64) *float64 {
+	if sum == 0 {
+		return nil
+	}
+	index := math.Round(float64(value)*float64(n)/float64(sum)*100) / 100
+	return &index
+}
@@ -0,0 +1,583 @@
+package services
+
+import (
+	"fmt"
+	"galactavista/internal/models"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxAttributeTextLength is the longest text attribute value
+const maxAttributeTextLength = 500
+
+// maxUnmatchedFeatures is the number of unmatched feature strings a feature
+// migration reports
+const maxUnmatchedFeatures = 50
+
+var (
+	attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	// countedFeaturePattern matches features like "2 car garage" that give
+	// a count for an int attribute
+	countedFeaturePattern = regexp.MustCompile(`^(\d+)\s*(.+)$`)
+	nonAlphanumeric       = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// defaultAttributes is the catalog seeded into an empty attribute table
+var defaultAttributes = []models.Attribute{
+	{Key: "central_air", Label: "Central air", Category: "Climate", Type: models.AttributeTypeBool,
+		Aliases: []string{"central ac", "central a/c", "air conditioning", "a/c", "ac"}},
+	{Key: "heating", Label: "Heating", Category: "Climate", Type: models.AttributeTypeEnum,
+		Options: []string{"forced_air", "radiant", "heat_pump", "baseboard", "none"}},
+	{Key: "fireplace", Label: "Fireplace", Category: "Interior", Type: models.AttributeTypeBool,
+		Aliases: []string{"fire place", "wood burning fireplace", "gas fireplace"}},
+	{Key: "hardwood_floors", Label: "Hardwood floors", Category: "Interior", Type: models.AttributeTypeBool,
+		Aliases: []string{"hardwood", "hardwood flooring", "hard wood floors"}},
+	{Key: "basement", Label: "Basement", Category: "Interior", Type: models.AttributeTypeEnum,
+		Options:       []string{"none", "unfinished", "partially_finished", "finished"},
+		PropertyTypes: []models.PropertyType{models.PropertyTypeHouse, models.PropertyTypeTownhouse}},
+	{Key: "laundry", Label: "Laundry", Category: "Interior", Type: models.AttributeTypeEnum,
+		Options: []string{"in_unit", "shared", "hookups", "none"}},
+	{Key: "garage_spaces", Label: "Garage spaces", Category: "Parking", Type: models.AttributeTypeInt,
+		Aliases: []string{"car garage", "garage spaces", "garage"}},
+	{Key: "pool", Label: "Pool", Category: "Exterior", Type: models.AttributeTypeBool,
+		Aliases: []string{"swimming pool", "in ground pool", "inground pool"}},
+	{Key: "waterfront", Label: "Waterfront", Category: "Exterior", Type: models.AttributeTypeBool,
+		Aliases: []string{"water front", "lakefront", "beachfront", "oceanfront"}},
+	{Key: "fenced_yard", Label: "Fenced yard", Category: "Exterior", Type: models.AttributeTypeBool,
+		Aliases: []string{"fenced backyard", "fenced in yard", "fenced"}},
+	{Key: "elevator", Label: "Elevator", Category: "Building", Type: models.AttributeTypeBool,
+		PropertyTypes: []models.PropertyType{models.PropertyTypeCondo, models.PropertyTypeApartment, models.PropertyTypeCommercial}},
+	{Key: "doorman", Label: "Doorman", Category: "Building", Type: models.AttributeTypeBool,
+		Aliases:       []string{"door man", "concierge"},
+		PropertyTypes: []models.PropertyType{models.PropertyTypeCondo, models.PropertyTypeApartment}},
+}
+
+// AttributeService manages the property attribute catalog
+type AttributeService struct {
+	db *gorm.DB
+}
+
+// NewAttributeService creates a new attribute service
+func NewAttributeService(db *gorm.DB) *AttributeService {
+	return &AttributeService{db: db}
+}
+
+// SeedDefaults fills an empty catalog with the default attributes
+func (s *AttributeService) SeedDefaults() error {
+	var count int64
+	if err := s.db.Model(&models.Attribute{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	attributes := make([]models.Attribute, len(defaultAttributes))
+	copy(attributes, defaultAttributes)
+	for i := range attributes {
+		attributes[i].SortOrder = i + 1
+	}
+	return s.db.Create(&attributes).Error
+}
+
+// GetAttributes lists the catalog by category
+func (s *AttributeService) GetAttributes(req *models.AttributeListRequest) ([]models.Attribute, error) {
+	query := s.db.Order("category, sort_order, id")
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+
+	var attributes []models.Attribute
+	if err := query.Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	if req.PropertyType == nil {
+		return attributes, nil
+	}
+
+	applicable := []models.Attribute{}
+	for _, attribute := range attributes {
+		if attribute.AppliesTo(*req.PropertyType) {
+			applicable = append(applicable, attribute)
+		}
+	}
+	return applicable, nil
+}
+
+// CreateAttribute adds an attribute to the catalog
+func (s *AttributeService) CreateAttribute(req *models.AttributeRequest) (*models.Attribute, error) {
+	if fieldErrors := validateAttributeRequest(req); len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	var count int64
+	if err := s.db.Model(&models.Attribute{}).Where("key = ?", req.Key).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "key", Message: "is already in the catalog"}}}
+	}
+
+	attribute := models.Attribute{
+		Key:           req.Key,
+		Label:         req.Label,
+		Category:      req.Category,
+		Type:          req.Type,
+		Options:       req.Options,
+		PropertyTypes: req.PropertyTypes,
+		Aliases:       req.Aliases,
+		SortOrder:     req.SortOrder,
+	}
+	if err := s.db.Create(&attribute).Error; err != nil {
+		return nil, err
+	}
+
+	return &attribute, nil
+}
+
+// UpdateAttribute replaces a catalog attribute. The type of an attribute in
+// use cannot change, and enum options in use cannot be removed.
+func (s *AttributeService) UpdateAttribute(id uint, req *models.AttributeRequest) (*models.Attribute, error) {
+	if fieldErrors := validateAttributeRequest(req); len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	var attribute models.Attribute
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&attribute, id).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.Attribute{}).Where("key = ? AND id <> ?", req.Key, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return &ValidationError{Errors: []models.FieldError{{Field: "key", Message: "is already in the catalog"}}}
+		}
+
+		values := tx.Model(&models.PropertyAttribute{}).Where("attribute_id = ?", id)
+		if req.Type != attribute.Type {
+			if err := values.Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return &ValidationError{Errors: []models.FieldError{{
+					Field:   "type",
+					Message: fmt.Sprintf("cannot change while %d listing(s) use the attribute", count),
+				}}}
+			}
+		} else if attribute.Type == models.AttributeTypeEnum {
+			var inUse []string
+			if err := values.Distinct("text_value").Pluck("text_value", &inUse).Error; err != nil {
+				return err
+			}
+			for _, option := range inUse {
+				if !containsString(req.Options, option) {
+					return &ValidationError{Errors: []models.FieldError{{
+						Field:   "options",
+						Message: fmt.Sprintf("option %q is in use", option),
+					}}}
+				}
+			}
+		}
+
+		attribute.Key = req.Key
+		attribute.Label = req.Label
+		attribute.Category = req.Category
+		attribute.Type = req.Type
+		attribute.Options = req.Options
+		attribute.PropertyTypes = req.PropertyTypes
+		attribute.Aliases = req.Aliases
+		attribute.SortOrder = req.SortOrder
+		return tx.Save(&attribute).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &attribute, nil
+}
+
+// DeleteAttribute removes an attribute and its values from the catalog
+func (s *AttributeService) DeleteAttribute(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var attribute models.Attribute
+		if err := tx.First(&attribute, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("attribute_id = ?", id).Delete(&models.PropertyAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&attribute).Error
+	})
+}
+
+// MigrateFeatures maps free-form feature strings onto catalog attributes.
+// A feature matching an attribute's key, label or one of its aliases sets a
+// bool attribute, or an int attribute when it starts with a count ("2 car
+// garage"), and is removed from the listing's features. Unmatched features
+// are kept and reported so aliases can be added.
+func (s *AttributeService) MigrateFeatures() (*models.FeatureMigrationResponse, error) {
+	var attributes []models.Attribute
+	if err := s.db.Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]*models.Attribute)
+	for i := range attributes {
+		attribute := &attributes[i]
+		if attribute.Type != models.AttributeTypeBool && attribute.Type != models.AttributeTypeInt {
+			continue
+		}
+		for _, name := range append([]string{attribute.Key, attribute.Label}, attribute.Aliases...) {
+			names[normalizeFeature(name)] = attribute
+		}
+	}
+
+	response := &models.FeatureMigrationResponse{}
+	unmatched := make(map[string]int)
+	var batch []models.Property
+	err := s.db.Preload("Attributes").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for i := range batch {
+				response.PropertiesScanned++
+				migrated, err := s.migratePropertyFeatures(&batch[i], names, unmatched)
+				if err != nil {
+					return err
+				}
+				if migrated > 0 {
+					response.PropertiesUpdated++
+					response.FeaturesMigrated += migrated
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	response.Unmatched = []models.UnmatchedFeature{}
+	for feature, listings := range unmatched {
+		response.Unmatched = append(response.Unmatched, models.UnmatchedFeature{Feature: feature, Listings: listings})
+	}
+	sort.Slice(response.Unmatched, func(i, j int) bool {
+		if response.Unmatched[i].Listings != response.Unmatched[j].Listings {
+			return response.Unmatched[i].Listings > response.Unmatched[j].Listings
+		}
+		return response.Unmatched[i].Feature < response.Unmatched[j].Feature
+	})
+	if len(response.Unmatched) > maxUnmatchedFeatures {
+		response.Unmatched = response.Unmatched[:maxUnmatchedFeatures]
+	}
+
+	return response, nil
+}
+
+// migratePropertyFeatures moves the matching features of one property onto
+// attribute values, returning the number of features migrated
+func (s *AttributeService) migratePropertyFeatures(property *models.Property, names map[string]*models.Attribute, unmatched map[string]int) (int, error) {
+	existing := make(map[uint]bool, len(property.Attributes))
+	for _, value := range property.Attributes {
+		existing[value.AttributeID] = true
+	}
+
+	var values []models.PropertyAttribute
+	var kept []string
+	for _, feature := range property.Features {
+		normalized := normalizeFeature(feature)
+
+		attribute, count := names[normalized], 0
+		if match := countedFeaturePattern.FindStringSubmatch(normalized); match != nil {
+			if counted := names[match[2]]; counted != nil && counted.Type == models.AttributeTypeInt {
+				attribute = counted
+				count, _ = strconv.Atoi(match[1])
+			}
+		}
+		if attribute == nil || !attribute.AppliesTo(property.PropertyType) ||
+			(attribute.Type == models.AttributeTypeInt && count == 0) {
+			kept = append(kept, feature)
+			unmatched[normalized]++
+			continue
+		}
+
+		if !existing[attribute.ID] {
+			value := models.PropertyAttribute{PropertyID: property.ID, AttributeID: attribute.ID}
+			if attribute.Type == models.AttributeTypeInt {
+				value.IntValue = &count
+			} else {
+				present := true
+				value.BoolValue = &present
+			}
+			values = append(values, value)
+			existing[attribute.ID] = true
+		}
+	}
+
+	migrated := len(property.Features) - len(kept)
+	if migrated == 0 {
+		return 0, nil
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if len(values) > 0 {
+			if err := tx.Create(&values).Error; err != nil {
+				return err
+			}
+		}
+		if kept == nil {
+			kept = []string{}
+		}
+		return tx.Model(&models.Property{}).Where("id = ?", property.ID).Updates(map[string]interface{}{
+			"features": kept,
+			"version":  gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return migrated, nil
+}
+
+// validateAttributeRequest checks a catalog attribute definition
+func validateAttributeRequest(req *models.AttributeRequest) []models.FieldError {
+	var fieldErrors []models.FieldError
+	if !attributeKeyPattern.MatchString(req.Key) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "key", Message: "must be lowercase snake_case"})
+	}
+	if !req.Type.IsValid() {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "type", Message: fmt.Sprintf("unknown attribute type %q", req.Type)})
+	}
+	if req.Type == models.AttributeTypeEnum && len(req.Options) == 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "options", Message: "enum attributes need options"})
+	}
+	if req.Type != models.AttributeTypeEnum && len(req.Options) > 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "options", Message: "only apply to enum attributes"})
+	}
+	for _, propertyType := range req.PropertyTypes {
+		if !propertyType.IsValid() {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   "property_types",
+				Message: fmt.Sprintf("unknown property type %q", propertyType),
+			})
+		}
+	}
+	return fieldErrors
+}
+
+// resolveAttributeValues validates attribute values given by key against
+// the catalog and the property type, returning them as unsaved rows
+func resolveAttributeValues(db *gorm.DB, propertyType models.PropertyType, values map[string]interface{}) ([]models.PropertyAttribute, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var attributes []models.Attribute
+	if err := db.Where("key IN ?", keys).Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]models.Attribute, len(attributes))
+	for _, attribute := range attributes {
+		catalog[attribute.Key] = attribute
+	}
+
+	var rows []models.PropertyAttribute
+	var fieldErrors []models.FieldError
+	for _, key := range keys {
+		field := "attributes." + key
+		attribute, ok := catalog[key]
+		if !ok {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "unknown attribute"})
+			continue
+		}
+		if !attribute.AppliesTo(propertyType) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: fmt.Sprintf("does not apply to %s listings", propertyType)})
+			continue
+		}
+
+		row := models.PropertyAttribute{AttributeID: attribute.ID, Attribute: attribute}
+		message := ""
+		switch value := values[key]; attribute.Type {
+		case models.AttributeTypeBool:
+			if b, ok := value.(bool); ok {
+				row.BoolValue = &b
+			} else {
+				message = "must be true or false"
+			}
+		case models.AttributeTypeInt:
+			n, ok := value.(float64)
+			if i, isInt := value.(int); isInt {
+				n, ok = float64(i), true
+			}
+			if ok && n == float64(int(n)) && n >= 0 {
+				i := int(n)
+				row.IntValue = &i
+			} else {
+				message = "must be a whole number"
+			}
+		case models.AttributeTypeEnum:
+			if text, ok := value.(string); ok && containsString(attribute.Options, text) {
+				row.TextValue = &text
+			} else {
+				message = "must be one of " + strings.Join(attribute.Options, ", ")
+			}
+		case models.AttributeTypeText:
+			if text, ok := value.(string); ok && len(text) <= maxAttributeTextLength {
+				row.TextValue = &text
+			} else {
+				message = fmt.Sprintf("must be text of at most %d characters", maxAttributeTextLength)
+			}
+		}
+		if message != "" {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: message})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	return rows, nil
+}
+
+// saveAttributeValues stores the resolved attribute values of a property
+func saveAttributeValues(tx *gorm.DB, propertyID uint, values []models.PropertyAttribute) error {
+	if len(values) == 0 {
+		return nil
+	}
+	for i := range values {
+		values[i].PropertyID = propertyID
+	}
+	return tx.Omit("Attribute").Create(&values).Error
+}
+
+// attributeFilter is a search condition on one attribute value
+type attributeFilter struct {
+	attributeID uint
+	condition   string
+	value       interface{}
+}
+
+// resolveAttributeFilters parses attr[key]=value search filters against
+// the catalog
+func resolveAttributeFilters(db *gorm.DB, filters map[string]string) ([]attributeFilter, error) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var attributes []models.Attribute
+	if err := db.Where("key IN ?", keys).Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]models.Attribute, len(attributes))
+	for _, attribute := range attributes {
+		catalog[attribute.Key] = attribute
+	}
+
+	var resolved []attributeFilter
+	var fieldErrors []models.FieldError
+	for _, key := range keys {
+		field := "attr[" + key + "]"
+		attribute, ok := catalog[key]
+		if !ok {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "unknown attribute"})
+			continue
+		}
+
+		value := strings.TrimSpace(filters[key])
+		filter := attributeFilter{attributeID: attribute.ID}
+		switch attribute.Type {
+		case models.AttributeTypeBool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "must be true or false"})
+				continue
+			}
+			filter.condition, filter.value = "bool_value = ?", b
+		case models.AttributeTypeInt:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "must be a whole number"})
+				continue
+			}
+			filter.condition, filter.value = "int_value >= ?", n
+		case models.AttributeTypeEnum:
+			options := strings.Split(value, ",")
+			for i := range options {
+				options[i] = strings.TrimSpace(options[i])
+				if !containsString(attribute.Options, options[i]) {
+					fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "must be one of " + strings.Join(attribute.Options, ", ")})
+					break
+				}
+			}
+			filter.condition, filter.value = "text_value IN ?", options
+		default:
+			filter.condition, filter.value = "text_value ILIKE ?", "%"+value+"%"
+		}
+		resolved = append(resolved, filter)
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	return resolved, nil
+}
+
+// toAttributeValues converts a property's attribute values for responses,
+// ordered as in the catalog
+func toAttributeValues(values []models.PropertyAttribute) []models.AttributeValue {
+	sorted := make([]models.PropertyAttribute, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].Attribute, sorted[j].Attribute
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.ID < b.ID
+	})
+
+	responses := make([]models.AttributeValue, len(sorted))
+	for i, value := range sorted {
+		responses[i] = models.AttributeValue{
+			Key:      value.Attribute.Key,
+			Label:    value.Attribute.Label,
+			Category: value.Attribute.Category,
+			Type:     value.Attribute.Type,
+			Value:    value.Value(),
+		}
+	}
+	return responses
+}
+
+// normalizeFeature lowercases a feature string and collapses punctuation
+// and spacing so spelling variants compare equal
+func normalizeFeature(feature string) string {
+	return strings.TrimSpace(nonAlphanumeric.ReplaceAllString(strings.ToLower(feature), " "))
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -114,6 +114,51 @@ func (s *AuthService) GetUserByID(userID uint) (*models.UserResponse, error) {
 	return s.toUserResponse(&user), nil
 }
 
+// UpdateProfile updates a user's profile if it is still at expectedVersion
+func (s *AuthService) UpdateProfile(userID uint, req *models.UserProfileUpdateRequest, expectedVersion int) (*models.UserResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	if user.Version != expectedVersion {
+		return nil, newVersionConflict(expectedVersion, user.Version, req, &user)
+	}
+
+	updates := map[string]interface{}{"version": expectedVersion + 1}
+	if req.FirstName != nil {
+		updates["first_name"] = *req.FirstName
+	}
+	if req.LastName != nil {
+		updates["last_name"] = *req.LastName
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Avatar != nil {
+		updates["avatar"] = *req.Avatar
+	}
+
+	// Only write if nobody else updated the profile since it was loaded
+	result := s.db.Model(&user).Where("version = ?", expectedVersion).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		var current models.User
+		if err := s.db.First(&current, userID).Error; err != nil {
+			return nil, err
+		}
+		return nil, newVersionConflict(expectedVersion, current.Version, req, &current)
+	}
+
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toUserResponse(&user), nil
+}
+
 // generateJWT generates a JWT token for a user
 func (s *AuthService) generateJWT(user *models.User) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
@@ -135,15 +180,18 @@ func (s *AuthService) generateJWT(user *models.User) (string, error) {
 // toUserResponse converts a User to UserResponse
 func (s *AuthService) toUserResponse(user *models.User) *models.UserResponse {
 	return &models.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Role:      user.Role,
-		Phone:     user.Phone,
-		Avatar:    user.Avatar,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:          user.ID,
+		Email:       user.Email,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Role:        user.Role,
+		Phone:       user.Phone,
+		Avatar:      user.Avatar,
+		IsActive:    user.IsActive,
+		BrokerageID: user.BrokerageID,
+		IsBroker:    user.IsBroker,
+		Version:     user.Version,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
 	}
 }
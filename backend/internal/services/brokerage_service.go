@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"galactavista/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BrokerageService handles brokerages and agent membership
+type BrokerageService struct {
+	db *gorm.DB
+}
+
+// NewBrokerageService creates a new brokerage service
+func NewBrokerageService(db *gorm.DB) *BrokerageService {
+	return &BrokerageService{db: db}
+}
+
+// CreateBrokerage creates a new brokerage
+func (s *BrokerageService) CreateBrokerage(req *models.BrokerageCreateRequest) (*models.BrokerageResponse, error) {
+	brokerage := models.Brokerage{Name: req.Name}
+	if err := s.db.Create(&brokerage).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toBrokerageResponse(&brokerage)
+}
+
+// GetBrokerages lists all brokerages with their agents
+func (s *BrokerageService) GetBrokerages() ([]models.BrokerageResponse, error) {
+	var brokerages []models.Brokerage
+	if err := s.db.Order("name").Find(&brokerages).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.BrokerageResponse, len(brokerages))
+	for i := range brokerages {
+		response, err := s.toBrokerageResponse(&brokerages[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = *response
+	}
+
+	return responses, nil
+}
+
+// GetAgentBrokerage gets the brokerage an agent belongs to
+func (s *BrokerageService) GetAgentBrokerage(agentID uint) (*models.BrokerageResponse, error) {
+	var agent models.User
+	if err := s.db.First(&agent, agentID).Error; err != nil {
+		return nil, err
+	}
+	if agent.BrokerageID == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var brokerage models.Brokerage
+	if err := s.db.First(&brokerage, *agent.BrokerageID).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toBrokerageResponse(&brokerage)
+}
+
+// SetMembership adds an agent to a brokerage, optionally as one of its
+// brokers, or removes them from their brokerage when brokerageID is nil
+func (s *BrokerageService) SetMembership(userID uint, req *models.BrokerageMembershipRequest) error {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Role != models.RoleAgent {
+		return errors.New("only agents can belong to a brokerage")
+	}
+	if req.BrokerageID != nil {
+		if err := s.db.First(&models.Brokerage{}, *req.BrokerageID).Error; err != nil {
+			return err
+		}
+	} else if req.IsBroker {
+		return errors.New("a broker must belong to a brokerage")
+	}
+
+	return s.db.Model(&user).Updates(map[string]interface{}{
+		"brokerage_id": req.BrokerageID,
+		"is_broker":    req.IsBroker,
+	}).Error
+}
+
+// toBrokerageResponse converts Brokerage to BrokerageResponse
+func (s *BrokerageService) toBrokerageResponse(brokerage *models.Brokerage) (*models.BrokerageResponse, error) {
+	var agents []models.User
+	if err := s.db.Where("brokerage_id = ? AND role = ?", brokerage.ID, models.RoleAgent).
+		Order("last_name, first_name").
+		Find(&agents).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.BrokerageResponse{
+		ID:        brokerage.ID,
+		Name:      brokerage.Name,
+		Agents:    make([]models.UserResponse, len(agents)),
+		CreatedAt: brokerage.CreatedAt,
+		UpdatedAt: brokerage.UpdatedAt,
+	}
+	for i, agent := range agents {
+		response.Agents[i] = models.UserResponse{
+			ID:          agent.ID,
+			Email:       agent.Email,
+			FirstName:   agent.FirstName,
+			LastName:    agent.LastName,
+			Role:        agent.Role,
+			Phone:       agent.Phone,
+			Avatar:      agent.Avatar,
+			IsActive:    agent.IsActive,
+			BrokerageID: agent.BrokerageID,
+			IsBroker:    agent.IsBroker,
+			Version:     agent.Version,
+			CreatedAt:   agent.CreatedAt,
+			UpdatedAt:   agent.UpdatedAt,
+		}
+	}
+
+	return response, nil
+}
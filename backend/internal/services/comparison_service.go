@@ -0,0 +1,260 @@
+package services
+
+import (
+	"fmt"
+	"galactavista/internal/models"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// minCompareProperties and maxCompareProperties bound how many listings
+	// can be compared at once
+	minCompareProperties = 2
+	maxCompareProperties = 5
+)
+
+// ComparisonService builds side-by-side property comparisons
+type ComparisonService struct {
+	db *gorm.DB
+}
+
+// NewComparisonService creates a new comparison service
+func NewComparisonService(db *gorm.DB) *ComparisonService {
+	return &ComparisonService{db: db}
+}
+
+// CompareProperties compares 2 to 5 properties given as a comma-separated
+// ID list. Columns keep the order the IDs were given in. Listings hidden
+// from viewer are reported as not found.
+func (s *ComparisonService) CompareProperties(ids string, viewer ListingViewer) (*models.PropertyComparisonResponse, error) {
+	propertyIDs, err := parseCompareIDs(ids)
+	if err != nil {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "ids", Message: err.Error()}}}
+	}
+
+	var properties []models.Property
+	if err := s.db.Preload("Attributes.Attribute").
+		Scopes(visibleListings(viewer, "properties")).
+		Where("id IN ?", propertyIDs).
+		Find(&properties).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Property, len(properties))
+	for i := range properties {
+		byID[properties[i].ID] = &properties[i]
+	}
+
+	compared := make([]models.ComparedProperty, len(propertyIDs))
+	for i, id := range propertyIDs {
+		property, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("property %d: %w", id, gorm.ErrRecordNotFound)
+		}
+		compared[i] = toComparedProperty(property, time.Now().Year())
+	}
+
+	response := &models.PropertyComparisonResponse{
+		Properties: compared,
+		Rows:       comparisonRows(compared),
+	}
+	response.Features, response.CommonFeatures = compareFeatures(compared)
+	response.DifferingRows = []string{}
+	for _, row := range response.Rows {
+		if row.Differs {
+			response.DifferingRows = append(response.DifferingRows, row.Field)
+		}
+	}
+
+	return response, nil
+}
+
+// parseCompareIDs parses a comma-separated list of distinct property IDs
+func parseCompareIDs(value string) ([]uint, error) {
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid property ID %q", part)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) < minCompareProperties || len(ids) > maxCompareProperties {
+		return nil, fmt.Errorf("must list between %d and %d distinct property IDs", minCompareProperties, maxCompareProperties)
+	}
+	return ids, nil
+}
+
+// toComparedProperty converts Property to a comparison column with its
+// computed metrics. Catalog attribute values are listed with the free-form
+// features so listings whose features were migrated still compare.
+func toComparedProperty(property *models.Property, currentYear int) models.ComparedProperty {
+	compared := models.ComparedProperty{
+		ID:           property.ID,
+		Title:        property.Title,
+		Address:      property.Address,
+		City:         property.City,
+		State:        property.State,
+		ZipCode:      property.ZipCode,
+		PropertyType: property.PropertyType,
+		ListingType:  property.ListingType,
+		Status:       property.Status,
+		Price:        property.Price,
+		MonthlyRent:  property.MonthlyRent,
+		Bedrooms:     property.Bedrooms,
+		Bathrooms:    property.Bathrooms,
+		SquareFeet:   property.SquareFeet,
+		YearBuilt:    property.YearBuilt,
+		LotSize:      property.LotSize,
+		VRModelURL:   property.VRModelURL,
+	}
+	compared.Features = append(append([]string{}, property.Features...), attributeFeatures(property.Attributes)...)
+	if len(property.Images) > 0 {
+		compared.Image = property.Images[0]
+	}
+	if property.SquareFeet > 0 && property.Price > 0 {
+		pricePerSqFt := math.Round(property.Price/float64(property.SquareFeet)*100) / 100
+		compared.PricePerSqFt = &pricePerSqFt
+	}
+	if property.YearBuilt > 0 && property.YearBuilt <= currentYear {
+		age := currentYear - property.YearBuilt
+		compared.Age = &age
+	}
+	return compared
+}
+
+// comparisonRows lays the compared fields out side by side
+func comparisonRows(compared []models.ComparedProperty) []models.ComparisonRow {
+	fields := []struct {
+		name  string
+		value func(p *models.ComparedProperty) interface{}
+	}{
+		{"listing_type", func(p *models.ComparedProperty) interface{} { return p.ListingType }},
+		{"price", func(p *models.ComparedProperty) interface{} { return p.Price }},
+		{"monthly_rent", func(p *models.ComparedProperty) interface{} { return p.MonthlyRent }},
+		{"price_per_sqft", func(p *models.ComparedProperty) interface{} { return p.PricePerSqFt }},
+		{"property_type", func(p *models.ComparedProperty) interface{} { return p.PropertyType }},
+		{"status", func(p *models.ComparedProperty) interface{} { return p.Status }},
+		{"bedrooms", func(p *models.ComparedProperty) interface{} { return p.Bedrooms }},
+		{"bathrooms", func(p *models.ComparedProperty) interface{} { return p.Bathrooms }},
+		{"square_feet", func(p *models.ComparedProperty) interface{} { return p.SquareFeet }},
+		{"lot_size", func(p *models.ComparedProperty) interface{} { return p.LotSize }},
+		{"year_built", func(p *models.ComparedProperty) interface{} { return p.YearBuilt }},
+		{"age", func(p *models.ComparedProperty) interface{} { return p.Age }},
+		{"city", func(p *models.ComparedProperty) interface{} { return p.City }},
+		{"zip_code", func(p *models.ComparedProperty) interface{} { return p.ZipCode }},
+	}
+
+	rows := make([]models.ComparisonRow, len(fields))
+	for i, field := range fields {
+		row := models.ComparisonRow{Field: field.name, Values: make([]interface{}, len(compared))}
+		for j := range compared {
+			row.Values[j] = field.value(&compared[j])
+			if j > 0 && comparisonKey(row.Values[j]) != comparisonKey(row.Values[0]) {
+				row.Differs = true
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// comparisonKey renders a row value for equality checks, looking through
+// pointers so that equal metrics compare equal
+func comparisonKey(value interface{}) string {
+	switch v := value.(type) {
+	case *float64:
+		if v == nil {
+			return "null"
+		}
+		return fmt.Sprint(*v)
+	case *int:
+		if v == nil {
+			return "null"
+		}
+		return fmt.Sprint(*v)
+	}
+	return fmt.Sprint(value)
+}
+
+// attributeFeatures describes the bool, int and enum attribute values of a
+// listing as features: "Pool", "Garage spaces: 2", "Heating: forced air".
+// False, zero and "none" values are left out.
+func attributeFeatures(values []models.PropertyAttribute) []string {
+	var features []string
+	for _, value := range values {
+		label := value.Attribute.Label
+		switch value.Attribute.Type {
+		case models.AttributeTypeBool:
+			if value.BoolValue != nil && *value.BoolValue {
+				features = append(features, label)
+			}
+		case models.AttributeTypeInt:
+			if value.IntValue != nil && *value.IntValue > 0 {
+				features = append(features, fmt.Sprintf("%s: %d", label, *value.IntValue))
+			}
+		case models.AttributeTypeEnum:
+			if value.TextValue != nil && *value.TextValue != "" && *value.TextValue != "none" {
+				features = append(features, label+": "+strings.ReplaceAll(*value.TextValue, "_", " "))
+			}
+		}
+	}
+	return features
+}
+
+// compareFeatures builds the feature matrix of the compared properties.
+// Features are matched case-insensitively and listed alphabetically; the
+// second result lists the features every property has.
+func compareFeatures(compared []models.ComparedProperty) ([]models.FeatureComparison, []string) {
+	labels := map[string]string{}
+	present := map[string][]bool{}
+	for i, property := range compared {
+		for _, feature := range property.Features {
+			key := strings.ToLower(strings.TrimSpace(feature))
+			if key == "" {
+				continue
+			}
+			if _, ok := present[key]; !ok {
+				labels[key] = strings.TrimSpace(feature)
+				present[key] = make([]bool, len(compared))
+			}
+			present[key][i] = true
+		}
+	}
+
+	keys := make([]string, 0, len(present))
+	for key := range present {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	features := make([]models.FeatureComparison, len(keys))
+	common := []string{}
+	for i, key := range keys {
+		comparison := models.FeatureComparison{Feature: labels[key], Present: present[key]}
+		for _, has := range comparison.Present {
+			if !has {
+				comparison.Differs = true
+				break
+			}
+		}
+		if !comparison.Differs {
+			common = append(common, comparison.Feature)
+		}
+		features[i] = comparison
+	}
+	return features, common
+}
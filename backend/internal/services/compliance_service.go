@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/fairhousing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ComplianceService handles fair-housing linting of listing descriptions and
+// broker overrides of blocked listings
+type ComplianceService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewComplianceService creates a new compliance service
+func NewComplianceService(db *gorm.DB, propertyService *PropertyService) *ComplianceService {
+	return &ComplianceService{db: db, propertyService: propertyService}
+}
+
+// LintDescription checks a draft description without saving anything
+func (s *ComplianceService) LintDescription(req *models.ComplianceLintRequest) *models.ComplianceLintResponse {
+	findings := fairhousing.Lint(req.Description)
+	return &models.ComplianceLintResponse{
+		Warnings: toComplianceWarnings(findings),
+		Blocked:  fairhousing.HasSevere(findings),
+	}
+}
+
+// GetBlockedListings lists the blocked listings of a broker's brokerage
+func (s *ComplianceService) GetBlockedListings(brokerID uint) ([]models.BlockedListingResponse, error) {
+	broker, err := s.findBroker(brokerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []models.Property
+	if err := s.db.Preload("Agent").
+		Where("compliance_status = ?", models.ComplianceStatusBlocked).
+		Where("agent_id IN (?)", s.db.Model(&models.User{}).Select("id").Where("brokerage_id = ?", *broker.BrokerageID)).
+		Order("updated_at DESC, id DESC").
+		Find(&properties).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.BlockedListingResponse, len(properties))
+	for i, property := range properties {
+		responses[i] = models.BlockedListingResponse{
+			ID:        property.ID,
+			Title:     property.Title,
+			AgentID:   property.AgentID,
+			AgentName: property.Agent.FirstName + " " + property.Agent.LastName,
+			Warnings:  toComplianceWarnings(fairhousing.Lint(property.Description)),
+			UpdatedAt: property.UpdatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// OverrideBlock publishes a blocked listing of the broker's brokerage
+// despite its severe warnings. The override lasts until the description is
+// next edited.
+func (s *ComplianceService) OverrideBlock(propertyID, brokerID uint, req *models.ComplianceOverrideRequest) (*models.PropertyResponse, error) {
+	broker, err := s.findBroker(brokerID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var property models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Agent").First(&property, propertyID).Error; err != nil {
+			return err
+		}
+		if property.Agent.BrokerageID == nil || *property.Agent.BrokerageID != *broker.BrokerageID {
+			return errors.New("unauthorized")
+		}
+		if property.Compliance.Status != models.ComplianceStatusBlocked {
+			return errors.New("listing is not blocked")
+		}
+
+		// Bump the version so pending edits made against the blocked
+		// listing see the change
+		return tx.Model(&property).Updates(map[string]interface{}{
+			"compliance_status":          models.ComplianceStatusOverridden,
+			"compliance_override_by_id":  brokerID,
+			"compliance_override_reason": req.Reason,
+			"compliance_overridden_at":   time.Now(),
+			"version":                    property.Version + 1,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.propertyService.GetProperty(propertyID)
+}
+
+// findBroker loads a user who is a broker of a brokerage
+func (s *ComplianceService) findBroker(userID uint) (*models.User, error) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	if !user.IsBroker || user.BrokerageID == nil {
+		return nil, errors.New("unauthorized")
+	}
+	return &user, nil
+}
+
+// lintListingDescription lints a property's description and, when it
+// changed, sets the listing's compliance status from the findings: severe
+// findings block publication and any earlier override no longer applies.
+// The findings are attached to the property's compliance.
+func lintListingDescription(property *models.Property, changed bool) {
+	findings := fairhousing.Lint(property.Description)
+	if changed {
+		property.Compliance = models.ListingCompliance{Status: models.ComplianceStatusClear}
+		if fairhousing.HasSevere(findings) {
+			property.Compliance.Status = models.ComplianceStatusBlocked
+		}
+	}
+	property.Compliance.Warnings = toComplianceWarnings(findings)
+}
+
+// ListingViewer identifies who is reading listings. Listings hidden from
+// the public stay visible to their own agent and to admins; the zero value
+// is an anonymous visitor.
+type ListingViewer struct {
+	UserID uint
+	Admin  bool
+}
+
+// publicListings restricts a property query to listings that may be shown
+// publicly: those neither rejected by moderation nor blocked for
+// fair-housing violations
+func publicListings(query *gorm.DB) *gorm.DB {
+	return visibleListings(ListingViewer{}, "properties")(query)
+}
+
+// visibleListings returns a scope restricting a property query to the
+// listings viewer may see: public ones, the viewer's own and, for admins,
+// all of them. table is the name or alias the properties table has in the
+// query. Every read path that can reveal a listing goes through this scope.
+func visibleListings(viewer ListingViewer, table string) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if viewer.Admin {
+			return query
+		}
+		condition := fmt.Sprintf("(%[1]s.moderation_status <> ? AND %[1]s.compliance_status <> ?)", table)
+		args := []interface{}{models.ModerationStatusRejected, models.ComplianceStatusBlocked}
+		if viewer.UserID != 0 {
+			condition += fmt.Sprintf(" OR %s.agent_id = ?", table)
+			args = append(args, viewer.UserID)
+		}
+		return query.Where(condition, args...)
+	}
+}
+
+// listingHidden reports whether a listing is hidden from the public by
+// moderation or compliance, the in-memory counterpart of publicListings
+func listingHidden(property *models.Property) bool {
+	return property.ModerationStatus == models.ModerationStatusRejected ||
+		property.Compliance.Status == models.ComplianceStatusBlocked
+}
+
+func toComplianceWarnings(findings []fairhousing.Finding) []models.ComplianceWarning {
+	warnings := make([]models.ComplianceWarning, len(findings))
+	for i, finding := range findings {
+		warnings[i] = models.ComplianceWarning{
+			RuleID:       finding.RuleID,
+			Category:     string(finding.Category),
+			Severity:     string(finding.Severity),
+			Phrase:       finding.Phrase,
+			Message:      finding.Message,
+			Alternatives: finding.Alternatives,
+			Start:        finding.Start,
+			End:          finding.End,
+		}
+	}
+	return warnings
+}
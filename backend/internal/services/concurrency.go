@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"galactavista/internal/models"
+)
+
+// VersionConflictError is returned when a conditional write targets a version
+// that is no longer current
+type VersionConflictError struct {
+	Details models.VersionConflictDetails
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict: expected version %d but current version is %d",
+		e.Details.ExpectedVersion, e.Details.CurrentVersion)
+}
+
+// newVersionConflict builds a conflict error listing the requested fields
+// whose stored value differs from the value the client submitted
+func newVersionConflict(expectedVersion, currentVersion int, req interface{}, current interface{}) *VersionConflictError {
+	return &VersionConflictError{Details: models.VersionConflictDetails{
+		ExpectedVersion: expectedVersion,
+		CurrentVersion:  currentVersion,
+		Conflicts:       fieldConflicts(req, current),
+	}}
+}
+
+// fieldConflicts compares every field set in an update request (non-nil
+// pointers and slices) with the same-named field of the stored model
+func fieldConflicts(req interface{}, current interface{}) []models.FieldConflict {
+	reqValue := reflect.Indirect(reflect.ValueOf(req))
+	currentValue := reflect.Indirect(reflect.ValueOf(current))
+	reqType := reqValue.Type()
+
+	conflicts := []models.FieldConflict{}
+	for i := 0; i < reqType.NumField(); i++ {
+		field := reqType.Field(i)
+		value := reqValue.Field(i)
+
+		var submitted interface{}
+		switch value.Kind() {
+		case reflect.Ptr:
+			if value.IsNil() {
+				continue
+			}
+			submitted = value.Elem().Interface()
+		case reflect.Slice, reflect.Map:
+			if value.IsNil() {
+				continue
+			}
+			submitted = value.Interface()
+		default:
+			continue
+		}
+
+		stored := currentValue.FieldByName(field.Name)
+		if !stored.IsValid() {
+			continue
+		}
+		current := stored.Interface()
+		if stored.Kind() == reflect.Ptr && !stored.IsNil() {
+			current = stored.Elem().Interface()
+		}
+		if reflect.DeepEqual(submitted, current) {
+			continue
+		}
+
+		conflicts = append(conflicts, models.FieldConflict{
+			Field:        strings.Split(field.Tag.Get("json"), ",")[0],
+			YourValue:    submitted,
+			CurrentValue: current,
+		})
+	}
+
+	return conflicts
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"galactavista/internal/models"
+	"math"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dashboard refresh settings
+const (
+	// dashboardDeltaOverlap is how far before the last refresh a delta
+	// looks for changes, so rows committed late with an earlier timestamp
+	// are still picked up. Deltas replace cached rows, so reading a row
+	// twice is harmless.
+	dashboardDeltaOverlap = time.Minute
+	// dashboardRecentItems is how many recent listings and inquiries a
+	// dashboard shows
+	dashboardRecentItems = 5
+)
+
+// DashboardService builds agent dashboards. The rows behind each agent's
+// statistics are cached in memory and brought up to date incrementally: a
+// refresh reads only the listings, leads and showings changed since the
+// last one and the views recorded since, so it stays fast however many
+// listings the agent has. A cached dashboard is rebuilt from scratch once
+// it is rebuildInterval old, which picks up what a delta cannot see, such
+// as leads reassigned to another agent or views purged with deleted
+// listings.
+type DashboardService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+	leadService     *LeadService
+	rebuildInterval time.Duration
+
+	mu         sync.Mutex
+	dashboards map[uint]*agentDashboard
+}
+
+// agentDashboard is the cached state of one agent's dashboard
+type agentDashboard struct {
+	mu          sync.Mutex
+	builtAt     time.Time
+	refreshedAt time.Time
+	listings    map[uint]dashboardListing
+	leads       map[uint]models.LeadStage
+	showings    map[uint]dashboardShowing
+	views       int64
+	lastEventID uint
+}
+
+// dashboardListing is the part of a listing the statistics are built from
+type dashboardListing struct {
+	ID        uint
+	Status    models.PropertyStatus
+	CreatedAt time.Time
+	SoldAt    *time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// dashboardShowing is the part of a showing the statistics are built from
+type dashboardShowing struct {
+	ID       uint
+	StartsAt time.Time
+	Status   models.ShowingStatus
+}
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(db *gorm.DB, propertyService *PropertyService, leadService *LeadService, rebuildInterval time.Duration) *DashboardService {
+	return &DashboardService{
+		db:              db,
+		propertyService: propertyService,
+		leadService:     leadService,
+		rebuildInterval: rebuildInterval,
+		dashboards:      make(map[uint]*agentDashboard),
+	}
+}
+
+// GetAgentDashboard returns an agent's dashboard, refreshing the cached
+// statistics first
+func (s *DashboardService) GetAgentDashboard(agentID uint) (*models.AgentDashboardData, error) {
+	stats, err := s.refresh(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []models.Property
+	if err := s.db.Preload("Agent").Preload("Development").Preload("Attributes.Attribute").Preload("OpenHouses", upcomingOpenHouses).
+		Where("agent_id = ?", agentID).
+		Order("created_at DESC, id DESC").
+		Limit(dashboardRecentItems).
+		Find(&properties).Error; err != nil {
+		return nil, err
+	}
+	recentProperties := make([]models.PropertyResponse, len(properties))
+	for i := range properties {
+		recentProperties[i] = *s.propertyService.getPropertyResponse(&properties[i])
+	}
+
+	var leads []models.Lead
+	if err := s.db.Preload("Property").Preload("Agent").
+		Where("agent_id = ?", agentID).
+		Order("created_at DESC, id DESC").
+		Limit(dashboardRecentItems).
+		Find(&leads).Error; err != nil {
+		return nil, err
+	}
+	recentInquiries := make([]models.LeadResponse, len(leads))
+	for i := range leads {
+		recentInquiries[i] = *s.leadService.toLeadResponse(&leads[i])
+	}
+
+	return &models.AgentDashboardData{
+		Stats:            *stats,
+		RecentProperties: recentProperties,
+		RecentInquiries:  recentInquiries,
+	}, nil
+}
+
+// refresh brings an agent's cached dashboard up to date and computes its
+// statistics. Refreshes of one agent run one at a time; different agents
+// refresh concurrently.
+func (s *DashboardService) refresh(agentID uint) (*models.DashboardStats, error) {
+	s.mu.Lock()
+	dashboard := s.dashboards[agentID]
+	if dashboard == nil {
+		dashboard = &agentDashboard{}
+		s.dashboards[agentID] = dashboard
+	}
+	s.mu.Unlock()
+
+	dashboard.mu.Lock()
+	defer dashboard.mu.Unlock()
+
+	now := time.Now()
+	var err error
+	if dashboard.builtAt.IsZero() || now.Sub(dashboard.builtAt) >= s.rebuildInterval {
+		err = s.rebuild(dashboard, agentID, now)
+	} else {
+		err = s.update(dashboard, agentID)
+	}
+	if err != nil {
+		// Start over next time rather than build on a partial refresh
+		dashboard.builtAt = time.Time{}
+		return nil, err
+	}
+	dashboard.refreshedAt = now
+
+	return dashboard.stats(now), nil
+}
+
+// rebuild loads an agent's dashboard from scratch
+func (s *DashboardService) rebuild(dashboard *agentDashboard, agentID uint, now time.Time) error {
+	var listings []dashboardListing
+	if err := s.db.Model(&models.Property{}).
+		Select("id", "status", "created_at", "sold_at", "deleted_at").
+		Where("agent_id = ?", agentID).
+		Scan(&listings).Error; err != nil {
+		return err
+	}
+
+	var leads []models.Lead
+	if err := s.db.Select("id", "stage").Where("agent_id = ?", agentID).Find(&leads).Error; err != nil {
+		return err
+	}
+
+	var showings []dashboardShowing
+	if err := s.db.Model(&models.Showing{}).
+		Select("id", "starts_at", "status").
+		Where("agent_id = ? AND starts_at > ?", agentID, now).
+		Scan(&showings).Error; err != nil {
+		return err
+	}
+
+	dashboard.listings = make(map[uint]dashboardListing, len(listings))
+	dashboard.leads = make(map[uint]models.LeadStage, len(leads))
+	dashboard.showings = make(map[uint]dashboardShowing, len(showings))
+	dashboard.views = 0
+	dashboard.lastEventID = 0
+	dashboard.applyListings(listings)
+	dashboard.applyLeads(leads)
+	dashboard.applyShowings(showings)
+	if err := s.countViews(dashboard, agentID); err != nil {
+		return err
+	}
+
+	dashboard.builtAt = now
+	return nil
+}
+
+// update applies the changes made since an agent's dashboard was last
+// refreshed
+func (s *DashboardService) update(dashboard *agentDashboard, agentID uint) error {
+	since := dashboard.refreshedAt.Add(-dashboardDeltaOverlap)
+
+	// Deleting a listing does not touch updated_at, so look at deleted_at
+	// too
+	var listings []dashboardListing
+	if err := s.db.Model(&models.Property{}).Unscoped().
+		Select("id", "status", "created_at", "sold_at", "deleted_at").
+		Where("agent_id = ? AND (updated_at > ? OR deleted_at > ?)", agentID, since, since).
+		Scan(&listings).Error; err != nil {
+		return err
+	}
+
+	var leads []models.Lead
+	if err := s.db.Select("id", "stage").Where("agent_id = ? AND updated_at > ?", agentID, since).Find(&leads).Error; err != nil {
+		return err
+	}
+
+	var showings []dashboardShowing
+	if err := s.db.Model(&models.Showing{}).
+		Select("id", "starts_at", "status").
+		Where("agent_id = ? AND updated_at > ?", agentID, since).
+		Scan(&showings).Error; err != nil {
+		return err
+	}
+
+	dashboard.applyListings(listings)
+	dashboard.applyLeads(leads)
+	dashboard.applyShowings(showings)
+	return s.countViews(dashboard, agentID)
+}
+
+// countViews adds the views of an agent's listings recorded since the last
+// counted event. Events are only ever appended, so counting past the
+// highest event ID seen is enough.
+func (s *DashboardService) countViews(dashboard *agentDashboard, agentID uint) error {
+	var result struct {
+		Views  int64
+		LastID *uint
+	}
+	if err := s.db.Model(&models.ListingEvent{}).
+		Select("COUNT(*) AS views, MAX(listing_events.id) AS last_id").
+		Joins("JOIN properties p ON p.id = listing_events.property_id").
+		Where("p.agent_id = ? AND listing_events.type = ? AND listing_events.id > ?", agentID, models.ListingEventView, dashboard.lastEventID).
+		Scan(&result).Error; err != nil {
+		return err
+	}
+
+	dashboard.views += result.Views
+	if result.LastID != nil {
+		dashboard.lastEventID = *result.LastID
+	}
+	return nil
+}
+
+// applyListings replaces cached listings with their current state,
+// dropping deleted ones
+func (d *agentDashboard) applyListings(listings []dashboardListing) {
+	for _, listing := range listings {
+		if listing.DeletedAt.Valid {
+			delete(d.listings, listing.ID)
+			continue
+		}
+		d.listings[listing.ID] = listing
+	}
+}
+
+// applyLeads replaces cached lead stages with their current state
+func (d *agentDashboard) applyLeads(leads []models.Lead) {
+	for _, lead := range leads {
+		d.leads[lead.ID] = lead.Stage
+	}
+}
+
+// applyShowings replaces cached showings with their current state
+func (d *agentDashboard) applyShowings(showings []dashboardShowing) {
+	for _, showing := range showings {
+		d.showings[showing.ID] = showing
+	}
+}
+
+// stats computes the statistics from the cached rows. Showings that have
+// started are dropped from the cache.
+func (d *agentDashboard) stats(now time.Time) *models.DashboardStats {
+	stats := &models.DashboardStats{
+		TotalProperties: len(d.listings),
+		TotalViews:      d.views,
+		TotalInquiries:  len(d.leads),
+		RefreshedAt:     now,
+	}
+
+	var activeDays, soldDays float64
+	var soldWithDate int
+	for _, listing := range d.listings {
+		switch listing.Status {
+		case models.PropertyStatusAvailable, models.PropertyStatusPending:
+			if listing.Status == models.PropertyStatusAvailable {
+				stats.AvailableProperties++
+			} else {
+				stats.PendingProperties++
+			}
+			activeDays += now.Sub(listing.CreatedAt).Hours() / 24
+		case models.PropertyStatusSold:
+			stats.SoldProperties++
+			if listing.SoldAt != nil {
+				soldDays += listing.SoldAt.Sub(listing.CreatedAt).Hours() / 24
+				soldWithDate++
+			}
+		case models.PropertyStatusRented:
+			stats.RentedProperties++
+		}
+	}
+	if active := stats.AvailableProperties + stats.PendingProperties; active > 0 {
+		stats.AverageDaysOnMarket = math.Round(activeDays/float64(active)*10) / 10
+	}
+	if soldWithDate > 0 {
+		stats.AverageDaysToSell = math.Round(soldDays/float64(soldWithDate)*10) / 10
+	}
+
+	for _, stage := range d.leads {
+		if stage == models.LeadStageNew {
+			stats.NewLeads++
+		}
+	}
+
+	for id, showing := range d.showings {
+		if !showing.StartsAt.After(now) {
+			delete(d.showings, id)
+			continue
+		}
+		if showing.Status != models.ShowingStatusCancelled {
+			stats.UpcomingShowings++
+		}
+	}
+
+	return stats
+}
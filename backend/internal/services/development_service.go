@@ -0,0 +1,452 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/address"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DevelopmentService handles developments and their unit listings
+type DevelopmentService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewDevelopmentService creates a new development service
+func NewDevelopmentService(db *gorm.DB, propertyService *PropertyService) *DevelopmentService {
+	return &DevelopmentService{db: db, propertyService: propertyService}
+}
+
+// CreateDevelopment creates a new development
+func (s *DevelopmentService) CreateDevelopment(req *models.DevelopmentCreateRequest, agentID uint) (*models.DevelopmentResponse, error) {
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "latitude", Message: "latitude and longitude must be given together"}}}
+	}
+
+	development := models.Development{
+		Name:        req.Name,
+		Description: req.Description,
+		Address:     req.Address,
+		City:        req.City,
+		State:       req.State,
+		ZipCode:     req.ZipCode,
+		Country:     req.Country,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+		YearBuilt:   req.YearBuilt,
+		Amenities:   req.Amenities,
+		Images:      req.Images,
+		VRModelURL:  req.VRModelURL,
+		AgentID:     agentID,
+	}
+	normalizeDevelopmentAddress(&development)
+	if err := s.db.Create(&development).Error; err != nil {
+		return nil, err
+	}
+
+	return toDevelopmentResponse(&development), nil
+}
+
+// GetDevelopments lists developments with their unit counts
+func (s *DevelopmentService) GetDevelopments(req *models.DevelopmentListRequest) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Development{})
+	if req.City != "" {
+		query = query.Where("city ILIKE ?", "%"+req.City+"%")
+	}
+	if req.State != "" {
+		query = query.Where("state ILIKE ?", "%"+req.State+"%")
+	}
+	if req.AgentID != nil {
+		query = query.Where("agent_id = ?", *req.AgentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var developments []models.Development
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("name, id").Offset(offset).Limit(req.PageSize).Find(&developments).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.DevelopmentResponse, len(developments))
+	ids := make([]uint, len(developments))
+	for i := range developments {
+		responses[i] = *toDevelopmentResponse(&developments[i])
+		ids[i] = developments[i].ID
+	}
+
+	if len(ids) > 0 {
+		var counts []struct {
+			DevelopmentID  uint
+			UnitCount      int64
+			AvailableUnits int64
+		}
+		if err := publicListings(s.db.Model(&models.Property{})).
+			Select("development_id, COUNT(*) AS unit_count, COUNT(*) FILTER (WHERE status = ?) AS available_units",
+				models.PropertyStatusAvailable).
+			Where("development_id IN ?", ids).
+			Group("development_id").
+			Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+		for _, count := range counts {
+			for i := range responses {
+				if responses[i].ID == count.DevelopmentID {
+					responses[i].UnitCount = count.UnitCount
+					responses[i].AvailableUnits = count.AvailableUnits
+				}
+			}
+		}
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// GetDevelopment gets a development with its unit availability and price
+// tables, leaving out units hidden from viewer
+func (s *DevelopmentService) GetDevelopment(id uint, viewer ListingViewer) (*models.DevelopmentResponse, error) {
+	var development models.Development
+	if err := s.db.First(&development, id).Error; err != nil {
+		return nil, err
+	}
+
+	var units []models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).
+		Where("development_id = ?", id).Order("unit_number, id").Find(&units).Error; err != nil {
+		return nil, err
+	}
+
+	response := toDevelopmentResponse(&development)
+	response.Units = make([]models.DevelopmentUnit, len(units))
+	for i, unit := range units {
+		response.Units[i] = models.DevelopmentUnit{
+			ID:            unit.ID,
+			UnitNumber:    unit.UnitNumber,
+			Title:         unit.Title,
+			PropertyType:  unit.PropertyType,
+			ListingType:   unit.ListingType,
+			Status:        unit.Status,
+			Bedrooms:      unit.Bedrooms,
+			Bathrooms:     unit.Bathrooms,
+			SquareFeet:    unit.SquareFeet,
+			Price:         unit.Price,
+			MonthlyRent:   unit.MonthlyRent,
+			AvailableFrom: unit.AvailableFrom,
+		}
+		response.UnitCount++
+		if unit.Status == models.PropertyStatusAvailable {
+			response.AvailableUnits++
+		}
+	}
+	response.PriceTable = unitPriceTable(units)
+
+	return response, nil
+}
+
+// UpdateDevelopment updates a development and carries address and location
+// changes over to its units
+func (s *DevelopmentService) UpdateDevelopment(id uint, req *models.DevelopmentUpdateRequest, agentID uint) (*models.DevelopmentResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var development models.Development
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&development, id).Error; err != nil {
+			return err
+		}
+		if development.AgentID != agentID {
+			return errors.New("unauthorized")
+		}
+
+		if req.Name != nil {
+			development.Name = *req.Name
+		}
+		if req.Description != nil {
+			development.Description = *req.Description
+		}
+		if req.Address != nil {
+			development.Address = *req.Address
+		}
+		if req.City != nil {
+			development.City = *req.City
+		}
+		if req.State != nil {
+			development.State = *req.State
+		}
+		if req.ZipCode != nil {
+			development.ZipCode = *req.ZipCode
+		}
+		if req.Country != nil {
+			development.Country = *req.Country
+		}
+		if req.Latitude != nil {
+			development.Latitude = req.Latitude
+		}
+		if req.Longitude != nil {
+			development.Longitude = req.Longitude
+		}
+		if req.YearBuilt != nil {
+			development.YearBuilt = *req.YearBuilt
+		}
+		if req.Amenities != nil {
+			development.Amenities = req.Amenities
+		}
+		if req.Images != nil {
+			development.Images = req.Images
+		}
+		if req.VRModelURL != nil {
+			development.VRModelURL = *req.VRModelURL
+		}
+		if (development.Latitude == nil) != (development.Longitude == nil) {
+			return &ValidationError{Errors: []models.FieldError{{Field: "latitude", Message: "latitude and longitude must be given together"}}}
+		}
+		normalizeDevelopmentAddress(&development)
+
+		if err := tx.Omit(clause.Associations).Save(&development).Error; err != nil {
+			return err
+		}
+
+		// Units fall back to the development's photos and VR tour, which
+		// count toward their quality scores
+		if req.Images != nil || req.VRModelURL != nil {
+			var unitIDs []uint
+			if err := tx.Model(&models.Property{}).Where("development_id = ?", development.ID).Pluck("id", &unitIDs).Error; err != nil {
+				return err
+			}
+			for _, unitID := range unitIDs {
+				if _, err := refreshQualityScore(tx, unitID); err != nil {
+					return err
+				}
+			}
+		}
+
+		locationChanged := req.Address != nil || req.City != nil || req.State != nil || req.ZipCode != nil ||
+			req.Country != nil || req.Latitude != nil || req.Longitude != nil || req.YearBuilt != nil
+		if !locationChanged {
+			return nil
+		}
+
+		// Bump unit versions so cached copies and pending edits see the change
+		if err := tx.Model(&models.Property{}).Where("development_id = ?", development.ID).Updates(map[string]interface{}{
+			"address":    development.Address,
+			"city":       development.City,
+			"state":      development.State,
+			"zip_code":   development.ZipCode,
+			"country":    development.Country,
+			"latitude":   development.Latitude,
+			"longitude":  development.Longitude,
+			"year_built": development.YearBuilt,
+			"version":    gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		// Each unit's address key includes its unit number
+		var units []models.Property
+		if err := tx.Where("development_id = ?", development.ID).Find(&units).Error; err != nil {
+			return err
+		}
+		for i := range units {
+			normalizePropertyAddress(&units[i])
+			if err := tx.Model(&units[i]).UpdateColumn("address_key", units[i].AddressKey).Error; err != nil {
+				return err
+			}
+			if _, err := flagDuplicates(tx, &units[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetDevelopment(id, ListingViewer{UserID: agentID})
+}
+
+// DeleteDevelopment deletes a development that has no units left
+func (s *DevelopmentService) DeleteDevelopment(id uint, agentID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var development models.Development
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&development, id).Error; err != nil {
+			return err
+		}
+		if development.AgentID != agentID {
+			return errors.New("unauthorized")
+		}
+
+		var units int64
+		if err := tx.Model(&models.Property{}).Where("development_id = ?", id).Count(&units).Error; err != nil {
+			return err
+		}
+		if units > 0 {
+			return fmt.Errorf("development still has %d unit(s)", units)
+		}
+
+		return tx.Delete(&development).Error
+	})
+}
+
+// CreateUnit lists a unit of a development
+func (s *DevelopmentService) CreateUnit(id uint, req *models.DevelopmentUnitCreateRequest, agentID uint) (*models.PropertyResponse, error) {
+	var development models.Development
+	if err := s.db.First(&development, id).Error; err != nil {
+		return nil, err
+	}
+	if development.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	// Unit numbers are stored normalized, so "apt 4b" matches "4B"
+	unitNumber := address.Normalize(address.Address{Unit: req.UnitNumber}).Unit
+	var count int64
+	if err := s.db.Model(&models.Property{}).
+		Where("development_id = ? AND unit_number = ?", id, unitNumber).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "unit_number", Message: "is already listed in this development"}}}
+	}
+
+	return s.propertyService.createProperty(&models.PropertyCreateRequest{
+		Title:           req.Title,
+		Description:     req.Description,
+		Price:           req.Price,
+		Address:         development.Address,
+		City:            development.City,
+		State:           development.State,
+		ZipCode:         development.ZipCode,
+		Country:         development.Country,
+		PropertyType:    req.PropertyType,
+		ListingType:     req.ListingType,
+		Bedrooms:        req.Bedrooms,
+		Bathrooms:       req.Bathrooms,
+		SquareFeet:      req.SquareFeet,
+		YearBuilt:       development.YearBuilt,
+		Latitude:        development.Latitude,
+		Longitude:       development.Longitude,
+		Features:        req.Features,
+		Images:          req.Images,
+		UnitNumber:      req.UnitNumber,
+		Commercial:      req.Commercial,
+		MonthlyRent:     req.MonthlyRent,
+		SecurityDeposit: req.SecurityDeposit,
+		LeaseTermMonths: req.LeaseTermMonths,
+		PetPolicy:       req.PetPolicy,
+		AvailableFrom:   req.AvailableFrom,
+	}, agentID, &development)
+}
+
+// unitPriceTable groups units by listing type and bedroom count, with price
+// and size ranges over the available units of each group
+func unitPriceTable(units []models.Property) []models.UnitPriceRow {
+	type groupKey struct {
+		listingType models.ListingType
+		bedrooms    int
+	}
+	groups := make(map[groupKey]*models.UnitPriceRow)
+	var rows []*models.UnitPriceRow
+
+	for _, unit := range units {
+		key := groupKey{unit.ListingType, unit.Bedrooms}
+		row, ok := groups[key]
+		if !ok {
+			row = &models.UnitPriceRow{ListingType: unit.ListingType, Bedrooms: unit.Bedrooms}
+			groups[key] = row
+			rows = append(rows, row)
+		}
+		row.Units++
+		if unit.Status != models.PropertyStatusAvailable {
+			continue
+		}
+
+		price := unit.Price
+		if unit.ListingType == models.ListingTypeRent && unit.MonthlyRent != nil {
+			price = *unit.MonthlyRent
+		}
+		if row.AvailableUnits == 0 {
+			row.MinPrice, row.MaxPrice = &price, &price
+			row.MinSquareFeet, row.MaxSquareFeet = unit.SquareFeet, unit.SquareFeet
+		} else {
+			if price < *row.MinPrice {
+				row.MinPrice = &price
+			}
+			if price > *row.MaxPrice {
+				row.MaxPrice = &price
+			}
+			if unit.SquareFeet < row.MinSquareFeet {
+				row.MinSquareFeet = unit.SquareFeet
+			}
+			if unit.SquareFeet > row.MaxSquareFeet {
+				row.MaxSquareFeet = unit.SquareFeet
+			}
+		}
+		row.AvailableUnits++
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].ListingType != rows[j].ListingType {
+			return rows[i].ListingType < rows[j].ListingType
+		}
+		return rows[i].Bedrooms < rows[j].Bedrooms
+	})
+
+	table := make([]models.UnitPriceRow, len(rows))
+	for i, row := range rows {
+		table[i] = *row
+	}
+	return table
+}
+
+// normalizeDevelopmentAddress standardizes a development's address the way
+// its units' addresses are
+func normalizeDevelopmentAddress(development *models.Development) {
+	normalized := address.Normalize(address.Address{
+		Street:  development.Address,
+		City:    development.City,
+		State:   development.State,
+		ZipCode: development.ZipCode,
+	})
+
+	development.Address = normalized.Street
+	development.City = normalized.City
+	development.State = normalized.State
+	development.ZipCode = normalized.ZipCode
+}
+
+// toDevelopmentResponse converts Development to DevelopmentResponse
+func toDevelopmentResponse(development *models.Development) *models.DevelopmentResponse {
+	return &models.DevelopmentResponse{
+		ID:          development.ID,
+		Name:        development.Name,
+		Description: development.Description,
+		Address:     development.Address,
+		City:        development.City,
+		State:       development.State,
+		ZipCode:     development.ZipCode,
+		Country:     development.Country,
+		Latitude:    development.Latitude,
+		Longitude:   development.Longitude,
+		YearBuilt:   development.YearBuilt,
+		Amenities:   development.Amenities,
+		Images:      development.Images,
+		VRModelURL:  development.VRModelURL,
+		AgentID:     development.AgentID,
+		CreatedAt:   development.CreatedAt,
+		UpdatedAt:   development.UpdatedAt,
+	}
+}
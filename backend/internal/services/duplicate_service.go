@@ -0,0 +1,552 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/address"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// duplicateStreetSimilarity is how alike two street lines with the same
+	// house number, unit and ZIP code must be to flag them
+	duplicateStreetSimilarity = 0.8
+	// duplicateLocationMiles is how close two listings of the same unit must
+	// be to flag them regardless of address (about 25 metres)
+	duplicateLocationMiles = 0.015
+	// duplicateCandidateLimit caps how many listings are compared per check
+	duplicateCandidateLimit = 50
+	// duplicateScanBatchSize is how many listings a scan loads at a time
+	duplicateScanBatchSize = 200
+)
+
+// DuplicateService handles the duplicate listing review queue
+type DuplicateService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewDuplicateService creates a new duplicate service
+func NewDuplicateService(db *gorm.DB, propertyService *PropertyService) *DuplicateService {
+	return &DuplicateService{db: db, propertyService: propertyService}
+}
+
+// GetDuplicates lists flagged pairs, newest first. Pairs where either
+// listing has since been deleted are left out.
+func (s *DuplicateService) GetDuplicates(req *models.DuplicateListRequest) (*models.PaginationResponse, error) {
+	if req.Status != "" && !req.Status.IsValid() {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "status", Message: "must be pending, dismissed or merged"}}}
+	}
+
+	query := s.db.Model(&models.DuplicateCandidate{}).
+		Joins("JOIN properties p ON p.id = duplicate_candidates.property_id AND p.deleted_at IS NULL").
+		Joins("JOIN properties d ON d.id = duplicate_candidates.duplicate_of_id AND d.deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("duplicate_candidates.status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var candidates []models.DuplicateCandidate
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property").Preload("DuplicateOf").
+		Order("duplicate_candidates.created_at DESC, duplicate_candidates.id DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.DuplicateCandidateResponse, len(candidates))
+	for i := range candidates {
+		responses[i] = *toDuplicateCandidateResponse(&candidates[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// ScanDuplicates normalizes the address of every live listing, storing the
+// canonical address key where it changed, and flags any duplicates found.
+// It backfills listings created before addresses were normalized.
+func (s *DuplicateService) ScanDuplicates() (*models.DuplicateScanResult, error) {
+	result := &models.DuplicateScanResult{}
+
+	var batch []models.Property
+	err := s.db.Order("id").FindInBatches(&batch, duplicateScanBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			property := &batch[i]
+			before := *property
+			normalizePropertyAddress(property)
+			result.Scanned++
+
+			if property.Address != before.Address || property.UnitNumber != before.UnitNumber || property.City != before.City ||
+				property.State != before.State || property.ZipCode != before.ZipCode || property.AddressKey != before.AddressKey {
+				if err := s.db.Model(property).UpdateColumns(map[string]interface{}{
+					"address":     property.Address,
+					"unit_number": property.UnitNumber,
+					"city":        property.City,
+					"state":       property.State,
+					"zip_code":    property.ZipCode,
+					"address_key": property.AddressKey,
+				}).Error; err != nil {
+					return err
+				}
+				result.Normalized++
+			}
+
+			flagged, err := flagDuplicates(s.db, property)
+			if err != nil {
+				return err
+			}
+			result.Flagged += flagged
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DismissDuplicate marks a flagged pair as not a duplicate
+func (s *DuplicateService) DismissDuplicate(id, adminID uint) (*models.DuplicateCandidateResponse, error) {
+	var candidate models.DuplicateCandidate
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&candidate, id).Error; err != nil {
+			return err
+		}
+		if err := checkDuplicatePending(&candidate); err != nil {
+			return err
+		}
+		return reviewDuplicate(tx, &candidate, models.DuplicateStatusDismissed, adminID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Property").Preload("DuplicateOf").First(&candidate, id).Error; err != nil {
+		return nil, err
+	}
+	return toDuplicateCandidateResponse(&candidate), nil
+}
+
+// MergeDuplicate merges a flagged pair into one listing. The kept listing
+// takes over the other's media files, VR tours, open houses, leads,
+// showings, offers, transactions, conversations and views and gains any
+// images and features it lacked; the other listing is moved to the trash.
+func (s *DuplicateService) MergeDuplicate(id uint, req *models.DuplicateMergeRequest, adminID uint) (*models.PropertyResponse, error) {
+	var keepID uint
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var candidate models.DuplicateCandidate
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&candidate, id).Error; err != nil {
+			return err
+		}
+		if err := checkDuplicatePending(&candidate); err != nil {
+			return err
+		}
+
+		keepID = candidate.DuplicateOfID
+		mergeID := candidate.PropertyID
+		if req.KeepID != nil {
+			switch *req.KeepID {
+			case candidate.DuplicateOfID:
+			case candidate.PropertyID:
+				keepID, mergeID = candidate.PropertyID, candidate.DuplicateOfID
+			default:
+				return &ValidationError{Errors: []models.FieldError{{Field: "keep_id", Message: "must be one of the flagged listings"}}}
+			}
+		}
+
+		var keep, merge models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&keep, keepID).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&merge, mergeID).Error; err != nil {
+			return err
+		}
+		// A feed would bring a merged-away listing back on its next sync
+		if merge.SourceFeedID != nil {
+			return &ValidationError{Errors: []models.FieldError{{Field: "keep_id", Message: "listings synced from an MLS feed cannot be merged away"}}}
+		}
+		// The kept listing's status would not reflect the other's sale
+		if err := checkNoSaleInProgress(tx, mergeID); err != nil {
+			return err
+		}
+
+		if err := moveListingMedia(tx, mergeID, keepID); err != nil {
+			return err
+		}
+		if err := moveListingRecords(tx, &merge, &keep); err != nil {
+			return err
+		}
+		if err := moveListingAttributes(tx, mergeID, keepID); err != nil {
+			return err
+		}
+
+		if err := ensureBaselineRevision(tx, &keep); err != nil {
+			return err
+		}
+		keep.Images = append(keep.Images, missingFrom(merge.Images, keep.Images)...)
+		keep.Features = append(keep.Features, missingFrom(merge.Features, keep.Features)...)
+		if keep.VRModelURL == "" {
+			keep.VRModelURL = merge.VRModelURL
+		}
+		keep.Version++
+		if err := tx.Model(&keep).Select("images", "features", "vr_model_url", "version").Updates(&keep).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, &keep, models.RevisionActionMerged, &adminID, nil); err != nil {
+			return err
+		}
+		if _, err := refreshQualityScore(tx, keepID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&merge).Update("version", merge.Version+1).Error; err != nil {
+			return err
+		}
+		if err := softDeleteProperty(tx, &merge); err != nil {
+			return err
+		}
+
+		return reviewDuplicate(tx, &candidate, models.DuplicateStatusMerged, adminID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.propertyService.GetProperty(keepID)
+}
+
+// normalizePropertyAddress standardizes a property's address fields and
+// sets its canonical address key
+func normalizePropertyAddress(property *models.Property) {
+	normalized := propertyAddress(property)
+
+	property.Address = normalized.Street
+	property.UnitNumber = normalized.Unit
+	property.City = normalized.City
+	property.State = normalized.State
+	property.ZipCode = normalized.ZipCode
+	property.AddressKey = normalized.Key()
+}
+
+// flagDuplicates compares a property against other active listings of the
+// same listing type and adds any likely duplicates to the review queue. It
+// returns how many new pairs were flagged.
+func flagDuplicates(tx *gorm.DB, property *models.Property) (int, error) {
+	if property.Status == models.PropertyStatusSold || property.Status == models.PropertyStatusRented {
+		return 0, nil
+	}
+	subject := propertyAddress(property)
+
+	conditions := []string{"address_key = ?"}
+	args := []interface{}{property.AddressKey}
+	if zip := subject.Zip5(); zip != "" {
+		conditions = append(conditions, "(zip_code LIKE ? AND LOWER(unit_number) = LOWER(?))")
+		args = append(args, zip+"%", property.UnitNumber)
+	}
+	if property.Latitude != nil && property.Longitude != nil {
+		latDelta := duplicateLocationMiles / milesPerDegreeLat
+		lngDelta := latDelta / math.Max(math.Cos(*property.Latitude*math.Pi/180), 0.01)
+		conditions = append(conditions, "(latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?)")
+		args = append(args, *property.Latitude-latDelta, *property.Latitude+latDelta,
+			*property.Longitude-lngDelta, *property.Longitude+lngDelta)
+	}
+
+	var candidates []models.Property
+	if err := tx.Where("id <> ? AND listing_type = ? AND status NOT IN ?", property.ID, property.ListingType,
+		[]models.PropertyStatus{models.PropertyStatusSold, models.PropertyStatusRented}).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("id").
+		Limit(duplicateCandidateLimit).
+		Find(&candidates).Error; err != nil {
+		return 0, err
+	}
+
+	flagged := 0
+	for i := range candidates {
+		reason, score, ok := matchDuplicate(property, subject, &candidates[i])
+		if !ok {
+			continue
+		}
+
+		// Pairs are stored with the later listing first and flagged once
+		later, earlier := property.ID, candidates[i].ID
+		if later < earlier {
+			later, earlier = earlier, later
+		}
+		var count int64
+		if err := tx.Model(&models.DuplicateCandidate{}).
+			Where("property_id = ? AND duplicate_of_id = ?", later, earlier).
+			Count(&count).Error; err != nil {
+			return flagged, err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := tx.Create(&models.DuplicateCandidate{
+			PropertyID:    later,
+			DuplicateOfID: earlier,
+			Reason:        reason,
+			Score:         score,
+			Status:        models.DuplicateStatusPending,
+		}).Error; err != nil {
+			return flagged, err
+		}
+		flagged++
+	}
+
+	return flagged, nil
+}
+
+// matchDuplicate decides whether a candidate listing duplicates a property.
+// The score is how alike their street lines are, from 0 to 1.
+func matchDuplicate(property *models.Property, subject address.Address, candidate *models.Property) (models.DuplicateReason, float64, bool) {
+	if property.AddressKey != "" && candidate.AddressKey == property.AddressKey {
+		return models.DuplicateReasonSameAddress, 1, true
+	}
+
+	other := propertyAddress(candidate)
+	if !strings.EqualFold(subject.Unit, other.Unit) {
+		return "", 0, false
+	}
+	score := address.Similarity(subject.Street, other.Street)
+
+	if subject.Zip5() != "" && subject.Zip5() == other.Zip5() &&
+		subject.HouseNumber() != "" && subject.HouseNumber() == other.HouseNumber() &&
+		score >= duplicateStreetSimilarity {
+		return models.DuplicateReasonSimilarAddress, score, true
+	}
+
+	if property.Latitude != nil && property.Longitude != nil && candidate.Latitude != nil && candidate.Longitude != nil &&
+		distanceMiles(*property.Latitude, *property.Longitude, *candidate.Latitude, *candidate.Longitude) <= duplicateLocationMiles {
+		return models.DuplicateReasonSameLocation, score, true
+	}
+
+	return "", 0, false
+}
+
+// propertyAddress returns a property's address in normalized form
+func propertyAddress(property *models.Property) address.Address {
+	return address.Normalize(address.Address{
+		Street:  property.Address,
+		Unit:    property.UnitNumber,
+		City:    property.City,
+		State:   property.State,
+		ZipCode: property.ZipCode,
+	})
+}
+
+// moveListingAttributes moves the catalog attribute values of one listing
+// to another. Values for attributes the target already has are left behind.
+func moveListingAttributes(tx *gorm.DB, fromID, toID uint) error {
+	return tx.Model(&models.PropertyAttribute{}).
+		Where("property_id = ? AND attribute_id NOT IN (?)", fromID,
+			tx.Model(&models.PropertyAttribute{}).Select("attribute_id").Where("property_id = ?", toID)).
+		Update("property_id", toID).Error
+}
+
+// moveListingMedia moves the media files and VR tours of one listing to
+// another. Media files are appended after the target's own, and files the
+// target already has are left behind.
+func moveListingMedia(tx *gorm.DB, fromID, toID uint) error {
+	var existing []models.MediaFile
+	if err := tx.Where("property_id = ?", toID).Find(&existing).Error; err != nil {
+		return err
+	}
+	urls := make(map[string]bool, len(existing))
+	nextSortOrder := 0
+	for _, mediaFile := range existing {
+		urls[mediaFile.FileURL] = true
+		if mediaFile.SortOrder >= nextSortOrder {
+			nextSortOrder = mediaFile.SortOrder + 1
+		}
+	}
+
+	var mediaFiles []models.MediaFile
+	if err := tx.Where("property_id = ?", fromID).Find(&mediaFiles).Error; err != nil {
+		return err
+	}
+	sort.SliceStable(mediaFiles, func(i, j int) bool {
+		if mediaFiles[i].SortOrder != mediaFiles[j].SortOrder {
+			return mediaFiles[i].SortOrder < mediaFiles[j].SortOrder
+		}
+		return mediaFiles[i].ID < mediaFiles[j].ID
+	})
+	for _, mediaFile := range mediaFiles {
+		if urls[mediaFile.FileURL] {
+			continue
+		}
+		if err := tx.Model(&mediaFile).Updates(map[string]interface{}{
+			"property_id": toID,
+			"sort_order":  nextSortOrder,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ModerationItem{}).Where("media_file_id = ?", mediaFile.ID).
+			Update("property_id", toID).Error; err != nil {
+			return err
+		}
+		urls[mediaFile.FileURL] = true
+		nextSortOrder++
+	}
+
+	return tx.Model(&models.VRTour{}).Where("property_id = ?", fromID).Update("property_id", toID).Error
+}
+
+// moveListingRecords moves the open houses, leads, showings, offers,
+// transactions, conversations and views of one listing to another. Records
+// handled by the listing's agent pass to the target's agent. A buyer who
+// wrote about both listings ends up with one conversation holding all
+// their messages.
+func moveListingRecords(tx *gorm.DB, from, to *models.Property) error {
+	var conversations []models.Conversation
+	if err := tx.Where("property_id = ?", from.ID).Find(&conversations).Error; err != nil {
+		return err
+	}
+	for _, conversation := range conversations {
+		var existing models.Conversation
+		// Every row counts against the (property_id, buyer_id) unique index
+		err := tx.Unscoped().Where("property_id = ? AND buyer_id = ?", to.ID, conversation.BuyerID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Message{}).Where("conversation_id = ?", conversation.ID).
+			Update("conversation_id", existing.ID).Error; err != nil {
+			return err
+		}
+		if conversation.LastMessageAt != nil && (existing.LastMessageAt == nil || conversation.LastMessageAt.After(*existing.LastMessageAt)) {
+			if err := tx.Model(&existing).Update("last_message_at", conversation.LastMessageAt).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&conversation).Error; err != nil {
+			return err
+		}
+	}
+
+	records := []interface{}{
+		&models.Lead{}, &models.Showing{}, &models.Offer{}, &models.Transaction{}, &models.Conversation{},
+	}
+	if from.AgentID != to.AgentID {
+		for _, record := range records {
+			if err := tx.Model(record).Where("property_id = ? AND agent_id = ?", from.ID, from.AgentID).
+				Update("agent_id", to.AgentID).Error; err != nil {
+				return err
+			}
+		}
+	}
+	for _, record := range append(records, &models.ListingEvent{}) {
+		if err := tx.Model(record).Where("property_id = ?", from.ID).Update("property_id", to.ID).Error; err != nil {
+			return err
+		}
+	}
+	// Cancelled open houses move too so calendar feeds keep dropping them
+	return tx.Unscoped().Model(&models.OpenHouse{}).Where("property_id = ?", from.ID).Update("property_id", to.ID).Error
+}
+
+// checkNoSaleInProgress rejects merging away a listing with an accepted
+// offer or an open transaction
+func checkNoSaleInProgress(tx *gorm.DB, propertyID uint) error {
+	var accepted int64
+	if err := tx.Model(&models.Offer{}).
+		Where("property_id = ? AND status = ?", propertyID, models.OfferStatusAccepted).
+		Count(&accepted).Error; err != nil {
+		return err
+	}
+	var open int64
+	if err := tx.Model(&models.Transaction{}).
+		Where("property_id = ? AND status = ?", propertyID, models.TransactionStatusOpen).
+		Count(&open).Error; err != nil {
+		return err
+	}
+	if accepted > 0 || open > 0 {
+		return &ValidationError{Errors: []models.FieldError{{
+			Field:   "keep_id",
+			Message: "listings with an accepted offer or open transaction cannot be merged away",
+		}}}
+	}
+	return nil
+}
+
+// checkDuplicatePending rejects review of a pair that was already reviewed
+func checkDuplicatePending(candidate *models.DuplicateCandidate) error {
+	if candidate.Status != models.DuplicateStatusPending {
+		return &ValidationError{Errors: []models.FieldError{{
+			Field:   "status",
+			Message: fmt.Sprintf("duplicate is already %s", candidate.Status),
+		}}}
+	}
+	return nil
+}
+
+// reviewDuplicate records an admin's decision on a flagged pair
+func reviewDuplicate(tx *gorm.DB, candidate *models.DuplicateCandidate, status models.DuplicateStatus, adminID uint) error {
+	now := time.Now()
+	candidate.Status = status
+	candidate.ReviewedByID = &adminID
+	candidate.ReviewedAt = &now
+	return tx.Model(candidate).Updates(map[string]interface{}{
+		"status":         status,
+		"reviewed_by_id": adminID,
+		"reviewed_at":    now,
+	}).Error
+}
+
+// toDuplicateCandidateResponse converts a flagged pair with its listings
+// loaded to a response
+func toDuplicateCandidateResponse(candidate *models.DuplicateCandidate) *models.DuplicateCandidateResponse {
+	return &models.DuplicateCandidateResponse{
+		ID:           candidate.ID,
+		Reason:       candidate.Reason,
+		Score:        candidate.Score,
+		Status:       candidate.Status,
+		Property:     toDuplicateListing(&candidate.Property),
+		DuplicateOf:  toDuplicateListing(&candidate.DuplicateOf),
+		ReviewedByID: candidate.ReviewedByID,
+		ReviewedAt:   candidate.ReviewedAt,
+		CreatedAt:    candidate.CreatedAt,
+	}
+}
+
+func toDuplicateListing(property *models.Property) models.DuplicateListing {
+	return models.DuplicateListing{
+		ID:         property.ID,
+		Title:      property.Title,
+		Address:    property.Address,
+		UnitNumber: property.UnitNumber,
+		City:       property.City,
+		State:      property.State,
+		ZipCode:    property.ZipCode,
+		AddressKey: property.AddressKey,
+		Price:      property.Price,
+		Status:     property.Status,
+		AgentID:    property.AgentID,
+		ImageCount: len(property.Images),
+		CreatedAt:  property.CreatedAt,
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/tabular"
+
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is the number of properties loaded per query while exporting
+const exportBatchSize = 500
+
+// exportColumns are the exported columns; they match the import column names
+// so an export can be edited and imported again
+var exportColumns = []string{
+	"id", "title", "description", "price", "address", "city", "state", "zip_code", "country",
+	"property_type", "status", "bedrooms", "bathrooms", "square_feet", "year_built", "lot_size",
+	"features", "images", "vr_model_url", "agent_email", "created_at", "updated_at",
+	"listing_type", "monthly_rent", "security_deposit", "lease_term_months", "pet_policy", "available_from",
+	"zoning", "usable_square_feet", "cap_rate", "net_operating_income", "commercial_lease_type",
+	"acreage", "utilities", "topography",
+}
+
+// ExportService handles listing exports
+type ExportService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewExportService creates a new export service
+func NewExportService(db *gorm.DB, propertyService *PropertyService) *ExportService {
+	return &ExportService{db: db, propertyService: propertyService}
+}
+
+// ExportSearch writes every property matching the search filters
+func (s *ExportService) ExportSearch(w io.Writer, req *models.PropertySearchRequest, format tabular.Format) error {
+	query := s.propertyService.applySearchFilters(s.db.Model(&models.Property{}), req)
+	return s.export(w, query, format)
+}
+
+// ExportAgentPortfolio writes every property of an agent
+func (s *ExportService) ExportAgentPortfolio(w io.Writer, agentID uint, format tabular.Format) error {
+	query := s.db.Model(&models.Property{}).Where("agent_id = ?", agentID)
+	return s.export(w, query, format)
+}
+
+// export loads the queried properties in batches and writes each batch as
+// it is loaded. Nothing is written if the query itself is invalid, so the
+// caller can still report the error.
+func (s *ExportService) export(w io.Writer, query *gorm.DB, format tabular.Format) error {
+	if query.Error != nil {
+		return query.Error
+	}
+
+	writer, err := tabular.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := writer.WriteRow(exportColumns); err != nil {
+		return err
+	}
+
+	var batch []models.Property
+	err = query.Preload("Agent").FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+		for _, property := range batch {
+			if err := writer.WriteRow(exportRow(&property)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// exportRow converts a property into a row following exportColumns
+func exportRow(property *models.Property) []string {
+	return []string{
+		strconv.FormatUint(uint64(property.ID), 10),
+		property.Title,
+		property.Description,
+		strconv.FormatFloat(property.Price, 'f', -1, 64),
+		property.Address,
+		property.City,
+		property.State,
+		property.ZipCode,
+		property.Country,
+		string(property.PropertyType),
+		string(property.Status),
+		strconv.Itoa(property.Bedrooms),
+		strconv.FormatFloat(property.Bathrooms, 'f', -1, 64),
+		strconv.Itoa(property.SquareFeet),
+		strconv.Itoa(property.YearBuilt),
+		strconv.FormatFloat(property.LotSize, 'f', -1, 64),
+		strings.Join(property.Features, listValueSeparator),
+		strings.Join(property.Images, listValueSeparator),
+		property.VRModelURL,
+		property.Agent.Email,
+		property.CreatedAt.UTC().Format(time.RFC3339),
+		property.UpdatedAt.UTC().Format(time.RFC3339),
+		string(property.ListingType),
+		formatOptionalFloat(property.MonthlyRent),
+		formatOptionalFloat(property.SecurityDeposit),
+		formatOptionalInt(property.LeaseTermMonths),
+		string(property.PetPolicy),
+		formatOptionalDate(property.AvailableFrom),
+		property.Commercial.Zoning + property.Land.Zoning,
+		formatOptionalInt(property.Commercial.UsableSquareFeet),
+		formatOptionalFloat(property.Commercial.CapRate),
+		formatOptionalFloat(property.Commercial.NetOperatingIncome),
+		string(property.Commercial.LeaseType),
+		formatOptionalFloat(property.Land.Acreage),
+		strings.Join(utilityNames(property.Land.Utilities), listValueSeparator),
+		string(property.Land.Topography),
+	}
+}
+
+// utilityNames converts utilities to strings
+func utilityNames(utilities []models.Utility) []string {
+	names := make([]string, len(utilities))
+	for i, utility := range utilities {
+		names[i] = string(utility)
+	}
+	return names
+}
+
+// formatOptionalFloat formats an optional number, or empty when unset
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// formatOptionalInt formats an optional whole number, or empty when unset
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// formatOptionalDate formats an optional date as YYYY-MM-DD, or empty when
+// unset
+func formatOptionalDate(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(listingDateFormat)
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/tabular"
+
+	"gorm.io/gorm"
+)
+
+// listValueSeparator separates multiple values (features, images) within one cell
+const listValueSeparator = "|"
+
+// listingDateFormat is the format of date cells (available_from) in imports
+// and exports
+const listingDateFormat = "2006-01-02"
+
+// ImportService handles bulk listing imports
+type ImportService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+	maxXLSXPartSize int64
+}
+
+// NewImportService creates a new import service. maxXLSXPartSize caps how
+// large each part of an uploaded XLSX workbook may decompress to.
+func NewImportService(db *gorm.DB, propertyService *PropertyService, maxXLSXPartSize int64) *ImportService {
+	return &ImportService{db: db, propertyService: propertyService, maxXLSXPartSize: maxXLSXPartSize}
+}
+
+// ValidateImport parses a CSV/XLSX file, validates every row and stores the
+// result as a dry-run import that can later be committed
+func (s *ImportService) ValidateImport(agentID uint, fileName string, r io.ReaderAt, size int64) (*models.ListingImportResponse, error) {
+	format, err := tabular.FormatFromFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tabular.Read(r, size, format, s.maxXLSXPartSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	if err := checkImportHeader(header); err != nil {
+		return nil, err
+	}
+
+	job := &models.ListingImport{
+		AgentID:  agentID,
+		FileName: fileName,
+		Format:   string(format),
+		Status:   models.ListingImportStatusValidated,
+	}
+
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+
+		// Spreadsheet row numbers are 1-based and include the header
+		rowNumber := i + 2
+		job.TotalRows++
+
+		req, fieldErrors := parseImportRow(header, row)
+		fieldErrors = mergeFieldErrors(fieldErrors, ValidatePropertyCreateRequest(req))
+		if len(fieldErrors) > 0 {
+			job.Errors = append(job.Errors, models.ImportRowError{Row: rowNumber, Errors: fieldErrors})
+			continue
+		}
+
+		job.Rows = append(job.Rows, models.ListingImportRow{Row: rowNumber, Request: *req})
+		job.ValidRows++
+	}
+
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to save import: %w", err)
+	}
+
+	return s.toResponse(job), nil
+}
+
+// CommitImport starts creating the valid rows of a validated import in the
+// background. A failed import that stopped before its last row is retried
+// from the row after the one it stopped at.
+func (s *ImportService) CommitImport(importID uint, agentID uint) (*models.ListingImportResponse, error) {
+	job, err := s.getImport(importID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	retry := job.Status == models.ListingImportStatusFailed && job.ProcessedRows < len(job.Rows)
+	if job.Status != models.ListingImportStatusValidated && !retry {
+		return nil, fmt.Errorf("import is already %s", job.Status)
+	}
+	if job.ValidRows == 0 {
+		return nil, errors.New("import has no valid rows to commit")
+	}
+
+	startedAt := time.Now()
+	if job.StartedAt != nil {
+		startedAt = *job.StartedAt
+	}
+	result := s.db.Model(&models.ListingImport{}).
+		Where("id = ? AND status = ?", job.ID, job.Status).
+		Updates(map[string]interface{}{
+			"status":       models.ListingImportStatusProcessing,
+			"started_at":   startedAt,
+			"completed_at": nil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("import is already being processed")
+	}
+
+	job.Status = models.ListingImportStatusProcessing
+	job.StartedAt = &startedAt
+	job.CompletedAt = nil
+
+	go s.processImport(job)
+
+	return s.toResponse(job), nil
+}
+
+// GetImport returns an import job with its progress
+func (s *ImportService) GetImport(importID uint, agentID uint) (*models.ListingImportResponse, error) {
+	job, err := s.getImport(importID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(job), nil
+}
+
+// GetImportsByAgent returns the import jobs of an agent
+func (s *ImportService) GetImportsByAgent(agentID uint) ([]models.ListingImportResponse, error) {
+	var jobs []models.ListingImport
+	if err := s.db.Omit("rows").Where("agent_id = ?", agentID).Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ListingImportResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = *s.toResponse(&job)
+	}
+
+	return responses, nil
+}
+
+// ResumeImports restarts the imports that were still processing when the
+// server stopped. Each picks up after its last recorded row.
+func (s *ImportService) ResumeImports() error {
+	var jobs []models.ListingImport
+	if err := s.db.Where("status = ?", models.ListingImportStatusProcessing).Find(&jobs).Error; err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		log.Printf("import %d: resuming at row %d of %d", jobs[i].ID, jobs[i].ProcessedRows+1, len(jobs[i].Rows))
+		go s.processImport(&jobs[i])
+	}
+	return nil
+}
+
+// processImport creates the listings of a committed import and tracks
+// progress, starting after the rows already processed. If creating a row
+// panics, that row is recorded as failed and the import stops as failed.
+func (s *ImportService) processImport(job *models.ListingImport) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("import %d: panic at row %d: %v", job.ID, job.ProcessedRows+1, r)
+			s.failImport(job, fmt.Sprintf("unexpected error: %v", r))
+		}
+	}()
+
+	for job.ProcessedRows < len(job.Rows) {
+		row := job.Rows[job.ProcessedRows]
+		req := row.Request
+		_, err := s.propertyService.CreateProperty(&req, job.AgentID)
+
+		columns := []string{"processed_rows", "created_rows", "failed_rows"}
+		job.ProcessedRows++
+		if err != nil {
+			job.FailedRows++
+			job.Errors = append(job.Errors, models.ImportRowError{
+				Row:    row.Row,
+				Errors: []models.FieldError{{Message: err.Error()}},
+			})
+			columns = append(columns, "errors")
+		} else {
+			job.CreatedRows++
+		}
+
+		if err := s.db.Model(job).Select(columns).Updates(job).Error; err != nil {
+			log.Printf("import %d: failed to update progress: %v", job.ID, err)
+		}
+	}
+
+	status := models.ListingImportStatusCompleted
+	if job.CreatedRows == 0 {
+		status = models.ListingImportStatusFailed
+	}
+	s.finishImport(job, status)
+}
+
+// failImport records the row being processed as failed with message and
+// stops the import as failed
+func (s *ImportService) failImport(job *models.ListingImport, message string) {
+	if job.ProcessedRows < len(job.Rows) {
+		job.Errors = append(job.Errors, models.ImportRowError{
+			Row:    job.Rows[job.ProcessedRows].Row,
+			Errors: []models.FieldError{{Message: message}},
+		})
+		job.ProcessedRows++
+		job.FailedRows++
+	}
+	s.finishImport(job, models.ListingImportStatusFailed)
+}
+
+// finishImport stores the final state of an import
+func (s *ImportService) finishImport(job *models.ListingImport, status models.ListingImportStatus) {
+	now := time.Now()
+	job.Status = status
+	job.CompletedAt = &now
+
+	if err := s.db.Model(job).
+		Select("status", "processed_rows", "created_rows", "failed_rows", "errors", "completed_at").
+		Updates(job).Error; err != nil {
+		log.Printf("import %d: failed to complete: %v", job.ID, err)
+	}
+}
+
+// getImport loads an import job owned by the agent
+func (s *ImportService) getImport(importID uint, agentID uint) (*models.ListingImport, error) {
+	var job models.ListingImport
+	if err := s.db.First(&job, importID).Error; err != nil {
+		return nil, err
+	}
+
+	if job.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return &job, nil
+}
+
+// toResponse converts ListingImport to ListingImportResponse
+func (s *ImportService) toResponse(job *models.ListingImport) *models.ListingImportResponse {
+	progress := 0.0
+	if job.ValidRows > 0 {
+		progress = float64(job.ProcessedRows) / float64(job.ValidRows)
+	}
+
+	return &models.ListingImportResponse{
+		ID:            job.ID,
+		FileName:      job.FileName,
+		Format:        job.Format,
+		Status:        job.Status,
+		TotalRows:     job.TotalRows,
+		ValidRows:     job.ValidRows,
+		InvalidRows:   job.TotalRows - job.ValidRows,
+		ProcessedRows: job.ProcessedRows,
+		CreatedRows:   job.CreatedRows,
+		FailedRows:    job.FailedRows,
+		Progress:      progress,
+		Errors:        job.Errors,
+		StartedAt:     job.StartedAt,
+		CompletedAt:   job.CompletedAt,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+	}
+}
+
+// checkImportHeader ensures the header names the required columns
+func checkImportHeader(header []string) error {
+	present := make(map[string]bool, len(header))
+	for _, name := range header {
+		present[name] = true
+	}
+
+	var missing []string
+	for _, name := range []string{"title", "address", "city", "state", "zip_code", "property_type"} {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	// Sale listings need a price and rentals a monthly rent
+	if !present["price"] && !present["monthly_rent"] {
+		missing = append(missing, "price")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// parseImportRow maps a spreadsheet row onto a create request
+func parseImportRow(header []string, row []string) (*models.PropertyCreateRequest, []models.FieldError) {
+	req := &models.PropertyCreateRequest{}
+	var fieldErrors []models.FieldError
+
+	parseInt := func(field, value string, dst *int) {
+		n, err := strconv.Atoi(strings.ReplaceAll(value, ",", ""))
+		if err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "must be a whole number"})
+			return
+		}
+		*dst = n
+	}
+	parseFloat := func(field, value string, dst *float64) {
+		n, err := strconv.ParseFloat(strings.TrimPrefix(strings.ReplaceAll(value, ",", ""), "$"), 64)
+		if err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field, Message: "must be a number"})
+			return
+		}
+		*dst = n
+	}
+	parseOptionalFloat := func(field, value string) *float64 {
+		var n float64
+		parseFloat(field, value, &n)
+		return &n
+	}
+	var commercial models.CommercialDetails
+	var land models.LandDetails
+	var zoning string
+
+	for i, name := range header {
+		if i >= len(row) {
+			break
+		}
+		value := strings.TrimSpace(row[i])
+		if value == "" {
+			continue
+		}
+
+		switch name {
+		case "title":
+			req.Title = value
+		case "description":
+			req.Description = value
+		case "price":
+			parseFloat(name, value, &req.Price)
+		case "address":
+			req.Address = value
+		case "city":
+			req.City = value
+		case "state":
+			req.State = value
+		case "zip_code":
+			req.ZipCode = value
+		case "country":
+			req.Country = value
+		case "property_type":
+			req.PropertyType = models.PropertyType(strings.ToLower(value))
+		case "bedrooms":
+			parseInt(name, value, &req.Bedrooms)
+		case "bathrooms":
+			parseFloat(name, value, &req.Bathrooms)
+		case "square_feet":
+			parseInt(name, value, &req.SquareFeet)
+		case "year_built":
+			parseInt(name, value, &req.YearBuilt)
+		case "lot_size":
+			parseFloat(name, value, &req.LotSize)
+		case "listing_type":
+			req.ListingType = models.ListingType(strings.ToLower(value))
+		case "monthly_rent":
+			req.MonthlyRent = parseOptionalFloat(name, value)
+		case "security_deposit":
+			req.SecurityDeposit = parseOptionalFloat(name, value)
+		case "lease_term_months":
+			var months int
+			parseInt(name, value, &months)
+			req.LeaseTermMonths = &months
+		case "pet_policy":
+			req.PetPolicy = models.PetPolicy(strings.ToLower(value))
+		case "available_from":
+			if _, err := time.Parse(listingDateFormat, value); err != nil {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: name, Message: "must be a date in YYYY-MM-DD format"})
+				continue
+			}
+			req.AvailableFrom = value
+		case "zoning":
+			zoning = value
+		case "usable_square_feet":
+			var squareFeet int
+			parseInt(name, value, &squareFeet)
+			commercial.UsableSquareFeet = &squareFeet
+		case "cap_rate":
+			commercial.CapRate = parseOptionalFloat(name, value)
+		case "net_operating_income":
+			commercial.NetOperatingIncome = parseOptionalFloat(name, value)
+		case "commercial_lease_type":
+			commercial.LeaseType = models.CommercialLeaseType(strings.ToLower(value))
+		case "acreage":
+			land.Acreage = parseOptionalFloat(name, value)
+		case "utilities":
+			for _, utility := range splitListValue(value) {
+				land.Utilities = append(land.Utilities, models.Utility(strings.ToLower(utility)))
+			}
+		case "topography":
+			land.Topography = models.Topography(strings.ToLower(value))
+		case "features":
+			req.Features = splitListValue(value)
+		case "images":
+			req.Images = splitListValue(value)
+		}
+	}
+
+	// Zoning is one column for both commercial and land listings
+	switch req.PropertyType {
+	case models.PropertyTypeCommercial:
+		commercial.Zoning = zoning
+	case models.PropertyTypeLand:
+		land.Zoning = zoning
+	}
+	if !commercial.IsZero() {
+		req.Commercial = &commercial
+	}
+	if !land.IsZero() {
+		req.Land = &land
+	}
+
+	return req, fieldErrors
+}
+
+// splitListValue splits a multi-value cell
+func splitListValue(value string) []string {
+	var values []string
+	for _, part := range strings.Split(value, listValueSeparator) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// mergeFieldErrors appends validation errors for fields that did not already
+// fail to parse, so an unparsable value is not also reported as missing
+func mergeFieldErrors(parseErrors, validationErrors []models.FieldError) []models.FieldError {
+	failed := make(map[string]bool, len(parseErrors))
+	for _, fieldErr := range parseErrors {
+		failed[fieldErr.Field] = true
+	}
+
+	for _, fieldErr := range validationErrors {
+		if !failed[fieldErr.Field] {
+			parseErrors = append(parseErrors, fieldErr)
+		}
+	}
+	return parseErrors
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
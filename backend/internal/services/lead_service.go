@@ -0,0 +1,349 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/tabular"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// leadExportColumns are the columns of a lead export
+var leadExportColumns = []string{
+	"id", "created_at", "stage", "source", "name", "email", "phone",
+	"property_id", "property_title", "agent_email", "message",
+}
+
+// LeadService handles buyer inquiries and the agent lead pipeline
+type LeadService struct {
+	db       *gorm.DB
+	notifier Notifier
+}
+
+// NewLeadService creates a new lead service
+func NewLeadService(db *gorm.DB, notifier Notifier) *LeadService {
+	return &LeadService{db: db, notifier: notifier}
+}
+
+// CreateInquiry records a buyer inquiry about a property as a new lead for
+// the listing agent. userID is nil for guests, who must leave a name and
+// email address.
+func (s *LeadService) CreateInquiry(propertyID uint, userID *uint, req *models.InquiryRequest) (*models.LeadResponse, error) {
+	var property models.Property
+	if err := s.db.Preload("Agent").First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
+	lead := models.Lead{
+		PropertyID:  property.ID,
+		AgentID:     property.AgentID,
+		BrokerageID: property.Agent.BrokerageID,
+		BuyerID:     userID,
+		Name:        strings.TrimSpace(req.Name),
+		Email:       strings.TrimSpace(req.Email),
+		Phone:       strings.TrimSpace(req.Phone),
+		Message:     strings.TrimSpace(req.Message),
+		Source:      models.LeadSourceInquiry,
+		Stage:       models.LeadStageNew,
+	}
+
+	if userID != nil {
+		var user models.User
+		if err := s.db.First(&user, *userID).Error; err != nil {
+			return nil, err
+		}
+		if lead.Name == "" {
+			lead.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		}
+		if lead.Email == "" {
+			lead.Email = user.Email
+		}
+		if lead.Phone == "" {
+			lead.Phone = user.Phone
+		}
+	}
+
+	var fieldErrors []models.FieldError
+	if lead.Name == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "name", Message: "is required"})
+	}
+	if lead.Email == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "email", Message: "is required"})
+	}
+	if lead.Message == "" {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "message", Message: "is required"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	if err := s.db.Create(&lead).Error; err != nil {
+		return nil, err
+	}
+	lead.Property = property
+	lead.Agent = property.Agent
+
+	s.notifyAgent(&property.Agent, "New inquiry: "+property.Title,
+		fmt.Sprintf("%s <%s> asked about %s:\n\n%s", lead.Name, lead.Email, property.Title, lead.Message))
+
+	return s.toLeadResponse(&lead), nil
+}
+
+// GetLeads lists the leads visible to an agent matching the filters
+func (s *LeadService) GetLeads(agentID uint, req *models.LeadListRequest) (*models.PaginationResponse, error) {
+	query, err := s.visibleLeads(agentID)
+	if err != nil {
+		return nil, err
+	}
+	query = applyLeadFilters(query, req)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var leads []models.Lead
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property").Preload("Agent").
+		Order("leads.created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&leads).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.LeadResponse, len(leads))
+	for i := range leads {
+		responses[i] = *s.toLeadResponse(&leads[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// ExportLeads writes every lead visible to an agent matching the filters
+func (s *LeadService) ExportLeads(w io.Writer, agentID uint, req *models.LeadListRequest, format tabular.Format) error {
+	query, err := s.visibleLeads(agentID)
+	if err != nil {
+		return err
+	}
+	query = applyLeadFilters(query, req)
+
+	rows := [][]string{leadExportColumns}
+
+	var batch []models.Lead
+	err = query.Preload("Property").Preload("Agent").Order("leads.id").
+		FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, lead := range batch {
+				rows = append(rows, []string{
+					strconv.FormatUint(uint64(lead.ID), 10),
+					lead.CreatedAt.UTC().Format(time.RFC3339),
+					string(lead.Stage),
+					string(lead.Source),
+					lead.Name,
+					lead.Email,
+					lead.Phone,
+					strconv.FormatUint(uint64(lead.PropertyID), 10),
+					lead.Property.Title,
+					lead.Agent.Email,
+					lead.Message,
+				})
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return tabular.Write(w, rows, format)
+}
+
+// GetLead gets a lead and its notes
+func (s *LeadService) GetLead(id, agentID uint) (*models.LeadResponse, error) {
+	lead, err := s.findLead(id, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toLeadResponse(lead), nil
+}
+
+// UpdateStage moves a lead to another pipeline stage
+func (s *LeadService) UpdateStage(id, agentID uint, req *models.LeadStageRequest) (*models.LeadResponse, error) {
+	if !req.Stage.IsValid() {
+		return nil, fmt.Errorf("invalid stage %q", req.Stage)
+	}
+
+	lead, err := s.findLead(id, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&models.Lead{ID: lead.ID}).Update("stage", req.Stage).Error; err != nil {
+		return nil, err
+	}
+	lead.Stage = req.Stage
+
+	return s.toLeadResponse(lead), nil
+}
+
+// AddNote records a note against a lead
+func (s *LeadService) AddNote(id, agentID uint, req *models.LeadNoteRequest) (*models.LeadNote, error) {
+	lead, err := s.findLead(id, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := models.LeadNote{LeadID: lead.ID, AuthorID: agentID, Body: req.Body}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&note).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Lead{ID: lead.ID}).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Author").First(&note, note.ID).Error; err != nil {
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// AssignLead reassigns a lead to another agent of the lead's brokerage
+func (s *LeadService) AssignLead(id, agentID uint, req *models.LeadAssignRequest) (*models.LeadResponse, error) {
+	lead, err := s.findLead(id, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if lead.BrokerageID == nil {
+		return nil, errors.New("only brokerage leads can be reassigned")
+	}
+
+	var assignee models.User
+	if err := s.db.First(&assignee, req.AgentID).Error; err != nil {
+		return nil, err
+	}
+	if assignee.Role != models.RoleAgent || assignee.BrokerageID == nil || *assignee.BrokerageID != *lead.BrokerageID {
+		return nil, errors.New("leads can only be assigned to agents of the same brokerage")
+	}
+
+	if err := s.db.Model(&models.Lead{ID: lead.ID}).Update("agent_id", assignee.ID).Error; err != nil {
+		return nil, err
+	}
+	lead.AgentID = assignee.ID
+	lead.Agent = assignee
+
+	if assignee.ID != agentID {
+		s.notifyAgent(&assignee, "Lead assigned: "+lead.Name,
+			fmt.Sprintf("%s <%s> about %s has been assigned to you.", lead.Name, lead.Email, lead.Property.Title))
+	}
+
+	return s.toLeadResponse(lead), nil
+}
+
+// visibleLeads scopes leads to those assigned to an agent or belonging to
+// the agent's brokerage
+func (s *LeadService) visibleLeads(agentID uint) (*gorm.DB, error) {
+	var agent models.User
+	if err := s.db.First(&agent, agentID).Error; err != nil {
+		return nil, err
+	}
+
+	query := s.db.Model(&models.Lead{})
+	if agent.BrokerageID != nil {
+		return query.Where("leads.agent_id = ? OR leads.brokerage_id = ?", agentID, *agent.BrokerageID), nil
+	}
+	return query.Where("leads.agent_id = ?", agentID), nil
+}
+
+// findLead loads a lead with its notes if it is visible to the agent
+func (s *LeadService) findLead(id, agentID uint) (*models.Lead, error) {
+	var lead models.Lead
+	if err := s.db.Preload("Property").Preload("Agent").
+		Preload("Notes", func(db *gorm.DB) *gorm.DB { return db.Order("created_at DESC") }).
+		Preload("Notes.Author").
+		First(&lead, id).Error; err != nil {
+		return nil, err
+	}
+
+	if lead.AgentID != agentID {
+		var agent models.User
+		if err := s.db.First(&agent, agentID).Error; err != nil {
+			return nil, err
+		}
+		if agent.BrokerageID == nil || lead.BrokerageID == nil || *agent.BrokerageID != *lead.BrokerageID {
+			return nil, errors.New("unauthorized")
+		}
+	}
+
+	return &lead, nil
+}
+
+// notifyAgent notifies an agent about a lead. Delivery failures are logged
+// and do not fail the request.
+func (s *LeadService) notifyAgent(agent *models.User, subject, body string) {
+	if err := s.notifier.Notify(Notification{UserID: agent.ID, Email: agent.Email, Subject: subject, Body: body}); err != nil {
+		log.Printf("failed to notify agent %d about lead: %v", agent.ID, err)
+	}
+}
+
+// applyLeadFilters applies lead list filters to a query
+func applyLeadFilters(query *gorm.DB, req *models.LeadListRequest) *gorm.DB {
+	if req.Stage != nil {
+		query = query.Where("leads.stage = ?", *req.Stage)
+	}
+	if req.PropertyID != nil {
+		query = query.Where("leads.property_id = ?", *req.PropertyID)
+	}
+	if req.AgentID != nil {
+		query = query.Where("leads.agent_id = ?", *req.AgentID)
+	}
+	if q := strings.TrimSpace(req.Query); q != "" {
+		pattern := "%" + q + "%"
+		query = query.Where("leads.name ILIKE ? OR leads.email ILIKE ? OR leads.phone ILIKE ?", pattern, pattern, pattern)
+	}
+	if req.From != nil {
+		query = query.Where("leads.created_at >= ?", *req.From)
+	}
+	if req.To != nil {
+		query = query.Where("leads.created_at < ?", req.To.AddDate(0, 0, 1))
+	}
+	return query
+}
+
+// toLeadResponse converts Lead to LeadResponse
+func (s *LeadService) toLeadResponse(lead *models.Lead) *models.LeadResponse {
+	return &models.LeadResponse{
+		ID:            lead.ID,
+		PropertyID:    lead.PropertyID,
+		PropertyTitle: lead.Property.Title,
+		AgentID:       lead.AgentID,
+		AgentName:     strings.TrimSpace(lead.Agent.FirstName + " " + lead.Agent.LastName),
+		BrokerageID:   lead.BrokerageID,
+		BuyerID:       lead.BuyerID,
+		Name:          lead.Name,
+		Email:         lead.Email,
+		Phone:         lead.Phone,
+		Message:       lead.Message,
+		Source:        lead.Source,
+		Stage:         lead.Stage,
+		Notes:         lead.Notes,
+		CreatedAt:     lead.CreatedAt,
+		UpdatedAt:     lead.UpdatedAt,
+	}
+}
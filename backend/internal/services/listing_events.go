@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"galactavista/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// listingEventBatchSize is the most events written in one insert
+const listingEventBatchSize = 500
+
+// EventRecorder stores listing events in the background so recording never
+// slows a request down. Events are buffered in memory and written in
+// batches by Run; when the buffer is full, further events are dropped
+// rather than blocking. Buffered events are lost if the process exits
+// before they are written.
+type EventRecorder struct {
+	db      *gorm.DB
+	events  chan models.ListingEvent
+	dropped atomic.Int64
+}
+
+// NewEventRecorder creates a new event recorder buffering up to bufferSize
+// events
+func NewEventRecorder(db *gorm.DB, bufferSize int) *EventRecorder {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	return &EventRecorder{db: db, events: make(chan models.ListingEvent, bufferSize)}
+}
+
+// Record queues an event without blocking. It reports whether the event was
+// queued.
+func (r *EventRecorder) Record(event models.ListingEvent) bool {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	select {
+	case r.events <- event:
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Run writes queued events until ctx is done, flushing whenever a batch
+// fills up or flushInterval passes. Events still queued when ctx is done
+// are written before it returns.
+func (r *EventRecorder) Run(ctx context.Context, flushInterval time.Duration) {
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.ListingEvent, 0, listingEventBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-r.events:
+					batch = append(batch, event)
+					if len(batch) == listingEventBatchSize {
+						batch = r.flush(batch)
+					}
+				default:
+					r.flush(batch)
+					return
+				}
+			}
+		case event := <-r.events:
+			batch = append(batch, event)
+			if len(batch) == listingEventBatchSize {
+				batch = r.flush(batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(batch)
+		}
+	}
+}
+
+// flush writes a batch of events and returns the emptied batch. Events of
+// listings that do not exist are discarded, since clients report events by
+// listing ID. A failed write is logged and the batch dropped so a database
+// outage cannot back events up into memory.
+func (r *EventRecorder) flush(batch []models.ListingEvent) []models.ListingEvent {
+	if dropped := r.dropped.Swap(0); dropped > 0 {
+		log.Printf("listing events: buffer full, dropped %d events", dropped)
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+
+	propertyIDs := make([]uint, 0, len(batch))
+	for _, event := range batch {
+		propertyIDs = append(propertyIDs, event.PropertyID)
+	}
+	var existing []uint
+	if err := r.db.Model(&models.Property{}).Where("id IN ?", propertyIDs).Pluck("id", &existing).Error; err != nil {
+		log.Printf("listing events: failed to write %d events: %v", len(batch), err)
+		return batch[:0]
+	}
+	known := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+
+	events := make([]models.ListingEvent, 0, len(batch))
+	for _, event := range batch {
+		if known[event.PropertyID] {
+			events = append(events, event)
+		}
+	}
+	if len(events) > 0 {
+		if err := r.db.Create(&events).Error; err != nil {
+			log.Printf("listing events: failed to write %d events: %v", len(events), err)
+		}
+	}
+	return batch[:0]
+}
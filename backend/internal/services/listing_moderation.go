@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/fairhousing"
+)
+
+// Listing rule names, as used in the LISTING_MODERATION_RULES setting
+const (
+	ListingRuleBannedTerms = "banned_terms"
+	ListingRuleContactInfo = "contact_info"
+	ListingRuleFairHousing = "fair_housing"
+)
+
+// ListingRule checks a listing's text before it is saved. Listings a rule
+// flags are published and queued for admin review.
+type ListingRule interface {
+	CheckListing(property *models.Property) []models.ModerationFlag
+}
+
+// NewListingRules builds the named listing rules in order, or every rule
+// when no names are given
+func NewListingRules(names []string, bannedTerms []string) ([]ListingRule, error) {
+	if len(names) == 0 {
+		names = []string{ListingRuleBannedTerms, ListingRuleContactInfo, ListingRuleFairHousing}
+	}
+
+	rules := make([]ListingRule, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ListingRuleBannedTerms:
+			rules = append(rules, NewBannedTermsRule(bannedTerms))
+		case ListingRuleContactInfo:
+			rules = append(rules, NewContactInfoRule())
+		case ListingRuleFairHousing:
+			rules = append(rules, NewFairHousingRule())
+		default:
+			return nil, fmt.Errorf("unknown listing moderation rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// listingText is the text of a listing the rules check
+func listingText(property *models.Property) string {
+	parts := append([]string{property.Title, property.Description}, property.Features...)
+	return strings.Join(parts, "\n")
+}
+
+// BannedTermsRule flags listings containing any banned term, ignoring case
+type BannedTermsRule struct {
+	terms []string
+}
+
+// NewBannedTermsRule creates a new banned terms rule
+func NewBannedTermsRule(terms []string) *BannedTermsRule {
+	rule := &BannedTermsRule{}
+	for _, term := range terms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			rule.terms = append(rule.terms, term)
+		}
+	}
+	return rule
+}
+
+// CheckListing flags each banned term the listing contains
+func (r *BannedTermsRule) CheckListing(property *models.Property) []models.ModerationFlag {
+	lower := strings.ToLower(listingText(property))
+	var flags []models.ModerationFlag
+	for _, term := range r.terms {
+		if strings.Contains(lower, term) {
+			flags = append(flags, models.ModerationFlag{
+				Rule:   ListingRuleBannedTerms,
+				Reason: fmt.Sprintf("contains banned term %q", term),
+			})
+		}
+	}
+	return flags
+}
+
+var (
+	contactEmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	contactPhonePattern = regexp.MustCompile(`(?:\+?1[\s.-]?)?\(?\b\d{3}\)?[\s.-]?\d{3}[\s.-]?\d{4}\b`)
+	contactURLPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S*[^\s.,;:!?)]`)
+)
+
+// ContactInfoRule flags listings that put email addresses, phone numbers or
+// links in their text to route buyers around the platform
+type ContactInfoRule struct{}
+
+// NewContactInfoRule creates a new contact info rule
+func NewContactInfoRule() *ContactInfoRule {
+	return &ContactInfoRule{}
+}
+
+// CheckListing flags each kind of contact information the listing contains
+func (r *ContactInfoRule) CheckListing(property *models.Property) []models.ModerationFlag {
+	text := listingText(property)
+	var flags []models.ModerationFlag
+	if match := contactEmailPattern.FindString(text); match != "" {
+		flags = append(flags, models.ModerationFlag{Rule: ListingRuleContactInfo, Reason: fmt.Sprintf("contains an email address (%s)", match)})
+	}
+	if match := contactPhonePattern.FindString(text); match != "" {
+		flags = append(flags, models.ModerationFlag{Rule: ListingRuleContactInfo, Reason: fmt.Sprintf("contains a phone number (%s)", match)})
+	}
+	if match := contactURLPattern.FindString(text); match != "" {
+		flags = append(flags, models.ModerationFlag{Rule: ListingRuleContactInfo, Reason: fmt.Sprintf("contains a link (%s)", match)})
+	}
+	return flags
+}
+
+// FairHousingRule flags listings the fair-housing linter finds anything in
+type FairHousingRule struct{}
+
+// NewFairHousingRule creates a new fair-housing rule
+func NewFairHousingRule() *FairHousingRule {
+	return &FairHousingRule{}
+}
+
+// CheckListing flags each fair-housing finding in the listing
+func (r *FairHousingRule) CheckListing(property *models.Property) []models.ModerationFlag {
+	var flags []models.ModerationFlag
+	for _, finding := range fairhousing.Lint(listingText(property)) {
+		flags = append(flags, models.ModerationFlag{
+			Rule:   ListingRuleFairHousing,
+			Reason: fmt.Sprintf("%s language %q: %s", finding.Severity, finding.Phrase, finding.Message),
+		})
+	}
+	return flags
+}
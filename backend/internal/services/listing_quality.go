@@ -0,0 +1,282 @@
+package services
+
+import (
+	"fmt"
+	"galactavista/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// qualityDescriptionLength is the description length, in characters,
+	// that earns full marks
+	qualityDescriptionLength = 500
+	// qualityPhotoCount is the number of photos that earns full marks
+	qualityPhotoCount = 15
+	// qualityHighResolution and qualityMinResolution are the long-edge photo
+	// sizes, in pixels, for full and half marks
+	qualityHighResolution = 1920
+	qualityMinResolution  = 1024
+	// qualityMissingAttributeHints caps how many unfilled attributes a
+	// suggestion names
+	qualityMissingAttributeHints = 3
+	// qualityRefreshBatchSize is how many listings a refresh loads at a time
+	qualityRefreshBatchSize = 200
+)
+
+// qualityGeocodePoints is what each geocode accuracy earns out of 15.
+// Coordinates without a recorded accuracy count as approximate.
+var qualityGeocodePoints = map[models.GeocodeAccuracy]int{
+	models.GeocodeAccuracyRooftop:      15,
+	models.GeocodeAccuracyParcel:       12,
+	models.GeocodeAccuracyInterpolated: 8,
+	models.GeocodeAccuracyApproximate:  4,
+	"":                                 4,
+}
+
+// refreshQualityScore recomputes a live property's quality score, stores it
+// if it changed and returns the full assessment
+func refreshQualityScore(tx *gorm.DB, propertyID uint) (*models.ListingQuality, error) {
+	var property models.Property
+	if err := tx.Preload("Development").Preload("Attributes").First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
+	qualities, err := assessListingQuality(tx, []models.Property{property})
+	if err != nil {
+		return nil, err
+	}
+	quality := qualities[property.ID]
+
+	if quality.Score != property.QualityScore {
+		if err := tx.Model(&property).UpdateColumn("quality_score", quality.Score).Error; err != nil {
+			return nil, err
+		}
+	}
+	return quality, nil
+}
+
+// assessListingQuality scores properties loaded with their development and
+// attribute values, keyed by property ID
+func assessListingQuality(db *gorm.DB, properties []models.Property) (map[uint]*models.ListingQuality, error) {
+	qualities := make(map[uint]*models.ListingQuality, len(properties))
+	if len(properties) == 0 {
+		return qualities, nil
+	}
+
+	ids := make([]uint, len(properties))
+	for i := range properties {
+		ids[i] = properties[i].ID
+	}
+
+	var mediaFiles []models.MediaFile
+	if err := visibleMedia(db).Where("property_id IN ? AND is_active = ? AND file_type = ?", ids, true, "image").
+		Find(&mediaFiles).Error; err != nil {
+		return nil, err
+	}
+	photos := make(map[uint][]models.MediaFile, len(properties))
+	for _, mediaFile := range mediaFiles {
+		photos[mediaFile.PropertyID] = append(photos[mediaFile.PropertyID], mediaFile)
+	}
+
+	var tourCounts []struct {
+		PropertyID uint
+		Tours      int64
+	}
+	if err := db.Model(&models.VRTour{}).
+		Select("property_id, COUNT(*) AS tours").
+		Where("property_id IN ? AND is_active = ?", ids, true).
+		Group("property_id").
+		Scan(&tourCounts).Error; err != nil {
+		return nil, err
+	}
+	tours := make(map[uint]bool, len(tourCounts))
+	for _, count := range tourCounts {
+		tours[count.PropertyID] = count.Tours > 0
+	}
+
+	var catalog []models.Attribute
+	if err := db.Order("sort_order, id").Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range properties {
+		property := &properties[i]
+		qualities[property.ID] = scoreListingQuality(property, photos[property.ID], tours[property.ID], catalog)
+	}
+	return qualities, nil
+}
+
+// scoreListingQuality builds the completeness checklist of a property from
+// its photo media files, whether it has an active VR tour and the attribute
+// catalog
+func scoreListingQuality(property *models.Property, photos []models.MediaFile, hasTour bool, catalog []models.Attribute) *models.ListingQuality {
+	checks := []models.QualityCheck{
+		descriptionCheck(property),
+		photoCountCheck(property, photos),
+		photoResolutionCheck(photos),
+		vrTourCheck(property, hasTour),
+		attributeCheck(property, catalog),
+		geocodeCheck(property),
+	}
+
+	quality := &models.ListingQuality{
+		PropertyID:  property.ID,
+		Checklist:   checks,
+		Suggestions: []string{},
+	}
+	open := make([]models.QualityCheck, 0, len(checks))
+	for _, check := range checks {
+		quality.Score += check.Points
+		if !check.Complete && check.Suggestion != "" {
+			open = append(open, check)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		return open[i].MaxPoints-open[i].Points > open[j].MaxPoints-open[j].Points
+	})
+	for _, check := range open {
+		quality.Suggestions = append(quality.Suggestions, check.Suggestion)
+	}
+
+	return quality
+}
+
+func descriptionCheck(property *models.Property) models.QualityCheck {
+	check := models.QualityCheck{Key: "description", Label: "Description", MaxPoints: 20}
+	length := utf8.RuneCountInString(strings.TrimSpace(property.Description))
+	switch {
+	case length >= qualityDescriptionLength:
+		check.Points = 20
+	case length >= 250:
+		check.Points = 14
+	case length >= 100:
+		check.Points = 7
+	}
+	return completeCheck(check, fmt.Sprintf("Expand the description to at least %d characters (currently %d)",
+		qualityDescriptionLength, length))
+}
+
+func photoCountCheck(property *models.Property, photos []models.MediaFile) models.QualityCheck {
+	check := models.QualityCheck{Key: "photos", Label: "Photos", MaxPoints: 20}
+
+	urls := make(map[string]bool, len(property.Images)+len(photos))
+	for _, image := range property.Images {
+		urls[image] = true
+	}
+	for _, photo := range photos {
+		urls[photo.FileURL] = true
+	}
+	count := len(urls)
+	// Units show their development's photos when they have none of their own
+	if count == 0 && property.Development != nil {
+		count = len(property.Development.Images)
+	}
+
+	switch {
+	case count >= qualityPhotoCount:
+		check.Points = 20
+	case count >= 10:
+		check.Points = 15
+	case count >= 5:
+		check.Points = 10
+	case count >= 1:
+		check.Points = 5
+	}
+	return completeCheck(check, fmt.Sprintf("Add at least %d photos (currently %d)", qualityPhotoCount, count))
+}
+
+func photoResolutionCheck(photos []models.MediaFile) models.QualityCheck {
+	check := models.QualityCheck{Key: "photo_resolution", Label: "Photo resolution", MaxPoints: 15}
+
+	known, high, acceptable := 0, 0, 0
+	for _, photo := range photos {
+		if photo.Width == nil || photo.Height == nil {
+			continue
+		}
+		known++
+		longEdge := *photo.Width
+		if *photo.Height > longEdge {
+			longEdge = *photo.Height
+		}
+		switch {
+		case longEdge >= qualityHighResolution:
+			high++
+		case longEdge >= qualityMinResolution:
+			acceptable++
+		}
+	}
+	if known == 0 {
+		return completeCheck(check, "Upload photos as image files so their resolution can be checked")
+	}
+
+	check.Points = int(math.Round(float64(check.MaxPoints) * (float64(high) + 0.5*float64(acceptable)) / float64(known)))
+	if low := known - high - acceptable; low > 0 {
+		return completeCheck(check, fmt.Sprintf("Replace photos under %d pixels on the long edge (%d of %d)", qualityMinResolution, low, known))
+	}
+	return completeCheck(check, fmt.Sprintf("Upload photos at least %d pixels on the long edge", qualityHighResolution))
+}
+
+func vrTourCheck(property *models.Property, hasTour bool) models.QualityCheck {
+	check := models.QualityCheck{Key: "vr_tour", Label: "VR tour", MaxPoints: 15}
+	if hasTour || property.VRModelURL != "" || (property.Development != nil && property.Development.VRModelURL != "") {
+		check.Points = 15
+	}
+	return completeCheck(check, "Add a VR tour so buyers can walk through remotely")
+}
+
+func attributeCheck(property *models.Property, catalog []models.Attribute) models.QualityCheck {
+	check := models.QualityCheck{Key: "attributes", Label: "Property details", MaxPoints: 15}
+
+	filled := make(map[uint]bool, len(property.Attributes))
+	for _, value := range property.Attributes {
+		filled[value.AttributeID] = true
+	}
+	applicable := 0
+	var missing []string
+	for i := range catalog {
+		if !catalog[i].AppliesTo(property.PropertyType) {
+			continue
+		}
+		applicable++
+		if !filled[catalog[i].ID] {
+			missing = append(missing, catalog[i].Label)
+		}
+	}
+	if applicable == 0 {
+		check.Points = check.MaxPoints
+		check.Complete = true
+		return check
+	}
+
+	check.Points = int(math.Round(float64(check.MaxPoints) * float64(applicable-len(missing)) / float64(applicable)))
+	if len(missing) > qualityMissingAttributeHints {
+		missing = missing[:qualityMissingAttributeHints]
+	}
+	return completeCheck(check, "Fill in details such as "+strings.Join(missing, ", "))
+}
+
+func geocodeCheck(property *models.Property) models.QualityCheck {
+	check := models.QualityCheck{Key: "geocode", Label: "Map location", MaxPoints: 15}
+	if property.Latitude == nil || property.Longitude == nil {
+		return completeCheck(check, "Add map coordinates so the listing appears in map searches")
+	}
+	check.Points = qualityGeocodePoints[property.GeocodeAccuracy]
+	return completeCheck(check, "Place the map pin on the building itself for rooftop accuracy")
+}
+
+// completeCheck marks a check complete at full points and otherwise
+// attaches the suggestion for earning the rest
+func completeCheck(check models.QualityCheck, suggestion string) models.QualityCheck {
+	if check.Points >= check.MaxPoints {
+		check.Points = check.MaxPoints
+		check.Complete = true
+		return check
+	}
+	check.Suggestion = suggestion
+	return check
+}
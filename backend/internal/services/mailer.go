@@ -0,0 +1,47 @@
+package services
+
+import (
+	"log"
+	"strings"
+)
+
+// MailAttachment is a file attached to an email
+type MailAttachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Mail is an email to one or more addresses. Unlike a Notification it may
+// go to people without an account and carry attachments.
+type Mail struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []MailAttachment
+}
+
+// Mailer sends email. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+// LogMailer writes mail to the application log. It is the default mailer
+// until a delivery provider is configured.
+type LogMailer struct{}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the mail and the names of its attachments
+func (m *LogMailer) Send(mail Mail) error {
+	attachments := make([]string, len(mail.Attachments))
+	for i, attachment := range mail.Attachments {
+		attachments[i] = attachment.FileName
+	}
+	log.Printf("mail to %s: %s: %s [attachments: %s]",
+		strings.Join(mail.To, ", "), mail.Subject, mail.Body, strings.Join(attachments, ", "))
+	return nil
+}
@@ -2,6 +2,10 @@ package services
 
 import (
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"mime/multipart"
 	"os"
@@ -14,6 +18,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// mediaUploadDir is where uploaded media files are stored, relative to the
+// working directory and to the public /uploads route
+const mediaUploadDir = "uploads/properties"
+
+// messageAttachmentDir is where message attachments are stored. They are
+// served only to conversation participants.
+const messageAttachmentDir = "uploads/messages"
+
 // MediaService handles media file operations
 type MediaService struct {
 	db *gorm.DB
@@ -35,7 +47,7 @@ func (s *MediaService) UploadFile(propertyID uint, file *multipart.FileHeader) (
 	fileName := s.generateFileName(file.Filename)
 
 	// Create upload directory if it doesn't exist
-	uploadDir := "uploads/properties"
+	uploadDir := mediaUploadDir
 	if err := os.MkdirAll(uploadDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create upload directory: %w", err)
 	}
@@ -50,13 +62,25 @@ func (s *MediaService) UploadFile(propertyID uint, file *multipart.FileHeader) (
 	mediaFile := &models.MediaFile{
 		PropertyID: propertyID,
 		FileName:   fileName,
-		FileURL:    fmt.Sprintf("/uploads/properties/%s", fileName),
+		FileURL:    fmt.Sprintf("/%s/%s", mediaUploadDir, fileName),
 		FileType:   s.getFileType(file.Filename),
 		FileSize:   file.Size,
 		IsActive:   true,
+
+		ModerationStatus: models.ModerationStatusApproved,
+	}
+	if mediaFile.FileType == "image" {
+		mediaFile.Width, mediaFile.Height = imageDimensions(filePath)
 	}
 
-	if err := s.db.Create(mediaFile).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(mediaFile).Error; err != nil {
+			return err
+		}
+		_, err := refreshQualityScore(tx, propertyID)
+		return err
+	})
+	if err != nil {
 		// Clean up file if database insert fails
 		os.Remove(filePath)
 		return nil, fmt.Errorf("failed to create media file record: %w", err)
@@ -65,10 +89,44 @@ func (s *MediaService) UploadFile(propertyID uint, file *multipart.FileHeader) (
 	return s.toResponse(mediaFile), nil
 }
 
-// GetPropertyMedia returns all media files for a property
-func (s *MediaService) GetPropertyMedia(propertyID uint) ([]models.MediaFileResponse, error) {
+// SaveMessageAttachment validates and stores a file attached to a message
+func (s *MediaService) SaveMessageAttachment(file *multipart.FileHeader) (*models.MessageAttachment, error) {
+	if err := s.validateFile(file); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(messageAttachmentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	fileName := s.generateFileName(file.Filename)
+	if err := s.saveFile(file, filepath.Join(messageAttachmentDir, fileName)); err != nil {
+		return nil, err
+	}
+
+	return &models.MessageAttachment{
+		FileName:     fileName,
+		OriginalName: filepath.Base(file.Filename),
+		FileType:     s.getFileType(file.Filename),
+		FileSize:     file.Size,
+	}, nil
+}
+
+// MessageAttachmentPath returns the disk path of a stored message attachment
+func (s *MediaService) MessageAttachmentPath(fileName string) string {
+	return filepath.Join(messageAttachmentDir, filepath.Base(fileName))
+}
+
+// GetPropertyMedia returns all media files for a property viewer may see
+// except those rejected by moderation
+func (s *MediaService) GetPropertyMedia(propertyID uint, viewer ListingViewer) ([]models.MediaFileResponse, error) {
+	var property models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
 	var mediaFiles []models.MediaFile
-	if err := s.db.Where("property_id = ? AND is_active = ?", propertyID, true).Find(&mediaFiles).Error; err != nil {
+	if err := visibleMedia(s.db).Where("property_id = ? AND is_active = ?", propertyID, true).Order("sort_order, id").Find(&mediaFiles).Error; err != nil {
 		return nil, err
 	}
 
@@ -80,21 +138,21 @@ func (s *MediaService) GetPropertyMedia(propertyID uint) ([]models.MediaFileResp
 	return responses, nil
 }
 
-// DeleteMediaFile deletes a media file
+// DeleteMediaFile moves a media file to the trash. The file stays on disk
+// until the record is purged so the media can be restored.
 func (s *MediaService) DeleteMediaFile(mediaFileID uint) error {
 	var mediaFile models.MediaFile
 	if err := s.db.First(&mediaFile, mediaFileID).Error; err != nil {
 		return err
 	}
 
-	// Delete file from disk
-	filePath := filepath.Join("uploads/properties", mediaFile.FileName)
-	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete file from disk: %w", err)
-	}
-
-	// Delete from database
-	return s.db.Delete(&mediaFile).Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&mediaFile).Error; err != nil {
+			return err
+		}
+		_, err := refreshQualityScore(tx, mediaFile.PropertyID)
+		return err
+	})
 }
 
 // validateFile validates the uploaded file
@@ -151,6 +209,22 @@ func (s *MediaService) saveFile(file *multipart.FileHeader, filePath string) err
 	return nil
 }
 
+// imageDimensions reads the pixel size of a stored image. Formats without a
+// registered decoder, such as WebP, have no known size.
+func imageDimensions(filePath string) (*int, *int) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, nil
+	}
+	return &config.Width, &config.Height
+}
+
 // getFileType determines the file type based on extension
 func (s *MediaService) getFileType(filename string) string {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -176,14 +250,18 @@ func (s *MediaService) getFileType(filename string) string {
 // toResponse converts MediaFile to MediaFileResponse
 func (s *MediaService) toResponse(mediaFile *models.MediaFile) *models.MediaFileResponse {
 	return &models.MediaFileResponse{
-		ID:         mediaFile.ID,
-		PropertyID: mediaFile.PropertyID,
-		FileName:   mediaFile.FileName,
-		FileURL:    mediaFile.FileURL,
-		FileType:   mediaFile.FileType,
-		FileSize:   mediaFile.FileSize,
-		IsActive:   mediaFile.IsActive,
-		CreatedAt:  mediaFile.CreatedAt,
-		UpdatedAt:  mediaFile.UpdatedAt,
+		ID:               mediaFile.ID,
+		PropertyID:       mediaFile.PropertyID,
+		FileName:         mediaFile.FileName,
+		FileURL:          mediaFile.FileURL,
+		FileType:         mediaFile.FileType,
+		FileSize:         mediaFile.FileSize,
+		Width:            mediaFile.Width,
+		Height:           mediaFile.Height,
+		IsActive:         mediaFile.IsActive,
+		SortOrder:        mediaFile.SortOrder,
+		ModerationStatus: mediaFile.ModerationStatus,
+		CreatedAt:        mediaFile.CreatedAt,
+		UpdatedAt:        mediaFile.UpdatedAt,
 	}
 }
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"sync"
+
+	"galactavista/internal/models"
+)
+
+// messageEventBuffer is how many undelivered events a subscriber may queue
+// before further events are dropped
+const messageEventBuffer = 16
+
+// MessageHub fans real-time message events out to the connected clients
+// of each user. Subscriptions are held in memory, so events only reach
+// clients connected to the same server instance.
+type MessageHub struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan models.MessageEvent]struct{}
+}
+
+// NewMessageHub creates a new message hub
+func NewMessageHub() *MessageHub {
+	return &MessageHub{subscribers: make(map[uint]map[chan models.MessageEvent]struct{})}
+}
+
+// Subscribe registers a client of a user. The returned function
+// unsubscribes and must be called when the client disconnects.
+func (h *MessageHub) Subscribe(userID uint) (<-chan models.MessageEvent, func()) {
+	ch := make(chan models.MessageEvent, messageEventBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan models.MessageEvent]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Publish delivers an event to every connected client of a user. Events for
+// a client that is not keeping up are dropped; it catches up from history.
+func (h *MessageHub) Publish(userID uint, event models.MessageEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strings"
+)
+
+// ModerationAction is a moderator's decision about a message
+type ModerationAction string
+
+const (
+	ModerationAllow  ModerationAction = "allow"
+	ModerationFlag   ModerationAction = "flag"
+	ModerationReject ModerationAction = "reject"
+)
+
+// ModerationDecision is the outcome of moderating a message
+type ModerationDecision struct {
+	Action ModerationAction
+	Reason string
+}
+
+// MessageModerator inspects messages before they are stored. Rejected
+// messages are not sent; flagged messages are sent and kept for review.
+type MessageModerator interface {
+	ModerateMessage(senderID uint, body string) (ModerationDecision, error)
+}
+
+// BlockedTermsModerator rejects messages containing any blocked term,
+// ignoring case
+type BlockedTermsModerator struct {
+	terms []string
+}
+
+// NewBlockedTermsModerator creates a new blocked terms moderator
+func NewBlockedTermsModerator(terms []string) *BlockedTermsModerator {
+	moderator := &BlockedTermsModerator{}
+	for _, term := range terms {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			moderator.terms = append(moderator.terms, term)
+		}
+	}
+	return moderator
+}
+
+// ModerateMessage rejects the message if it contains a blocked term
+func (m *BlockedTermsModerator) ModerateMessage(senderID uint, body string) (ModerationDecision, error) {
+	lower := strings.ToLower(body)
+	for _, term := range m.terms {
+		if strings.Contains(lower, term) {
+			return ModerationDecision{Action: ModerationReject, Reason: "message contains blocked content"}, nil
+		}
+	}
+	return ModerationDecision{Action: ModerationAllow}, nil
+}
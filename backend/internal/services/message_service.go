@@ -0,0 +1,499 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"strings"
+
+	"galactavista/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxMessageAttachments is the most files a single message may carry
+	maxMessageAttachments = 5
+	// defaultMessageHistoryLimit is the page size of message history
+	defaultMessageHistoryLimit = 50
+)
+
+// MessageService handles buyer-agent conversations and message delivery
+type MessageService struct {
+	db           *gorm.DB
+	mediaService *MediaService
+	hub          *MessageHub
+	moderators   []MessageModerator
+}
+
+// NewMessageService creates a new message service. Moderators run in order
+// on every message before it is stored.
+func NewMessageService(db *gorm.DB, mediaService *MediaService, hub *MessageHub, moderators ...MessageModerator) *MessageService {
+	return &MessageService{db: db, mediaService: mediaService, hub: hub, moderators: moderators}
+}
+
+// Subscribe registers a live event stream for a user. The returned
+// function must be called when the stream closes.
+func (s *MessageService) Subscribe(userID uint) (<-chan models.MessageEvent, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+// StartConversation opens the buyer's conversation with the listing agent
+// of a property, reusing an existing one, and sends the first message if a
+// body is given
+func (s *MessageService) StartConversation(propertyID, buyerID uint, req *models.ConversationCreateRequest) (*models.ConversationResponse, error) {
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID == buyerID {
+		return nil, errors.New("you cannot start a conversation about your own listing")
+	}
+
+	conversation := models.Conversation{PropertyID: propertyID, BuyerID: buyerID, AgentID: property.AgentID}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&conversation).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.Where("property_id = ? AND buyer_id = ?", propertyID, buyerID).First(&conversation).Error; err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Body) != "" {
+		if _, err := s.SendMessage(conversation.ID, buyerID, req.Body, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetConversation(conversation.ID, buyerID)
+}
+
+// GetConversations lists a user's conversations, most recently active first
+func (s *MessageService) GetConversations(userID uint, req *models.ConversationListRequest) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Conversation{}).Where("buyer_id = ? OR agent_id = ?", userID, userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var conversations []models.Conversation
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").Preload("Agent").
+		Order("last_message_at DESC NULLS LAST, id DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&conversations).Error; err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toConversationResponses(conversations, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// GetConversation gets one of a user's conversations
+func (s *MessageService) GetConversation(id, userID uint) (*models.ConversationResponse, error) {
+	var conversation models.Conversation
+	if err := s.db.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").Preload("Agent").
+		First(&conversation, id).Error; err != nil {
+		return nil, err
+	}
+	if !isParticipant(&conversation, userID) {
+		return nil, errors.New("unauthorized")
+	}
+
+	responses, err := s.toConversationResponses([]models.Conversation{conversation}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
+}
+
+// GetUnreadCount counts a user's unread messages across all conversations
+func (s *MessageService) GetUnreadCount(userID uint) (*models.UnreadCountResponse, error) {
+	var counts models.UnreadCountResponse
+	if err := s.unreadMessages(userID).
+		Select("COUNT(*) AS messages, COUNT(DISTINCT messages.conversation_id) AS conversations").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return &counts, nil
+}
+
+// GetMessages pages backwards through a conversation's history
+func (s *MessageService) GetMessages(conversationID, userID uint, req *models.MessageHistoryRequest) (*models.MessageHistoryResponse, error) {
+	conversation, err := s.findConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultMessageHistoryLimit
+	}
+
+	query := s.db.Where("conversation_id = ?", conversationID)
+	if req.Before > 0 {
+		query = query.Where("id < ?", req.Before)
+	}
+
+	var messages []models.Message
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	response := &models.MessageHistoryResponse{Messages: []models.MessageResponse{}}
+	if len(messages) > limit {
+		response.HasMore = true
+		messages = messages[:limit]
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		response.Messages = append(response.Messages, s.toMessageResponse(&messages[i], conversation, userID))
+	}
+
+	return response, nil
+}
+
+// SendMessage sends a message with optional attachments. The message runs
+// through the moderators before it is stored and is then delivered live to
+// both participants.
+func (s *MessageService) SendMessage(conversationID, senderID uint, body string, files []*multipart.FileHeader) (*models.MessageResponse, error) {
+	conversation, err := s.findConversation(conversationID, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" && len(files) == 0 {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "body", Message: "a message needs text or an attachment"}}}
+	}
+	if len(files) > maxMessageAttachments {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "attachments", Message: fmt.Sprintf("at most %d attachments are allowed", maxMessageAttachments)}}}
+	}
+
+	message := models.Message{
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Body:           body,
+		Attachments:    []models.MessageAttachment{},
+	}
+	for _, moderator := range s.moderators {
+		decision, err := moderator.ModerateMessage(senderID, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to moderate message: %w", err)
+		}
+		switch decision.Action {
+		case ModerationReject:
+			return nil, &ValidationError{Errors: []models.FieldError{{Field: "body", Message: decision.Reason}}}
+		case ModerationFlag:
+			message.Flagged = true
+			message.FlagReason = decision.Reason
+		}
+	}
+
+	for _, file := range files {
+		attachment, err := s.mediaService.SaveMessageAttachment(file)
+		if err != nil {
+			s.removeAttachments(message.Attachments)
+			return nil, err
+		}
+		message.Attachments = append(message.Attachments, *attachment)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+
+		// Sending a message marks the conversation read for the sender
+		updates := map[string]interface{}{"last_message_at": message.CreatedAt}
+		if senderID == conversation.BuyerID {
+			updates["buyer_last_read_id"] = message.ID
+			conversation.BuyerLastReadID = message.ID
+		} else {
+			updates["agent_last_read_id"] = message.ID
+			conversation.AgentLastReadID = message.ID
+		}
+		return tx.Model(&models.Conversation{ID: conversation.ID}).Updates(updates).Error
+	})
+	if err != nil {
+		s.removeAttachments(message.Attachments)
+		return nil, err
+	}
+
+	recipientID := conversation.AgentID
+	if senderID == conversation.AgentID {
+		recipientID = conversation.BuyerID
+	}
+	for _, userID := range []uint{recipientID, senderID} {
+		response := s.toMessageResponse(&message, conversation, userID)
+		s.hub.Publish(userID, models.MessageEvent{
+			Type:           models.MessageEventMessage,
+			ConversationID: conversation.ID,
+			Message:        &response,
+		})
+	}
+
+	response := s.toMessageResponse(&message, conversation, senderID)
+	return &response, nil
+}
+
+// MarkRead moves a user's read position forward to a message, or to the
+// latest message when messageID is zero, and sends a read receipt to the
+// other participant
+func (s *MessageService) MarkRead(conversationID, userID uint, req *models.MarkReadRequest) error {
+	conversation, err := s.findConversation(conversationID, userID)
+	if err != nil {
+		return err
+	}
+
+	lastReadID := req.MessageID
+	if lastReadID == 0 {
+		if err := s.db.Model(&models.Message{}).
+			Where("conversation_id = ?", conversationID).
+			Select("COALESCE(MAX(id), 0)").
+			Scan(&lastReadID).Error; err != nil {
+			return err
+		}
+	} else {
+		var message models.Message
+		if err := s.db.Where("id = ? AND conversation_id = ?", lastReadID, conversationID).First(&message).Error; err != nil {
+			return err
+		}
+	}
+
+	column, otherID := "agent_last_read_id", conversation.BuyerID
+	if userID == conversation.BuyerID {
+		column, otherID = "buyer_last_read_id", conversation.AgentID
+	}
+
+	result := s.db.Model(&models.Conversation{}).
+		Where("id = ? AND "+column+" < ?", conversationID, lastReadID).
+		Update(column, lastReadID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		s.hub.Publish(otherID, models.MessageEvent{
+			Type:           models.MessageEventRead,
+			ConversationID: conversationID,
+			ReaderID:       userID,
+			LastReadID:     lastReadID,
+		})
+	}
+
+	return nil
+}
+
+// GetAttachment returns the stored path and original name of an attachment
+// in one of a user's conversations
+func (s *MessageService) GetAttachment(conversationID, userID uint, fileName string) (string, string, error) {
+	if _, err := s.findConversation(conversationID, userID); err != nil {
+		return "", "", err
+	}
+
+	filter, err := json.Marshal([]map[string]string{{"file_name": fileName}})
+	if err != nil {
+		return "", "", err
+	}
+
+	var message models.Message
+	if err := s.db.Where("conversation_id = ? AND attachments::jsonb @> ?::jsonb", conversationID, string(filter)).
+		First(&message).Error; err != nil {
+		return "", "", err
+	}
+
+	for _, attachment := range message.Attachments {
+		if attachment.FileName == fileName {
+			return s.mediaService.MessageAttachmentPath(fileName), attachment.OriginalName, nil
+		}
+	}
+
+	return "", "", gorm.ErrRecordNotFound
+}
+
+// GetFlaggedMessages lists messages flagged by moderators for review
+func (s *MessageService) GetFlaggedMessages(page, pageSize int) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Message{}).Where("flagged = ?", true)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var messages []models.Message
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+
+	return &models.PaginationResponse{
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       messages,
+	}, nil
+}
+
+// findConversation loads a conversation the user participates in
+func (s *MessageService) findConversation(id, userID uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	if err := s.db.First(&conversation, id).Error; err != nil {
+		return nil, err
+	}
+	if !isParticipant(&conversation, userID) {
+		return nil, errors.New("unauthorized")
+	}
+
+	return &conversation, nil
+}
+
+// unreadMessages scopes messages to those a user has not read yet
+func (s *MessageService) unreadMessages(userID uint) *gorm.DB {
+	return s.db.Model(&models.Message{}).
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("messages.sender_id <> ?", userID).
+		Where("(conversations.buyer_id = ? AND messages.id > conversations.buyer_last_read_id) OR (conversations.agent_id = ? AND messages.id > conversations.agent_last_read_id)",
+			userID, userID)
+}
+
+// removeAttachments deletes stored attachment files of a message that was
+// not sent
+func (s *MessageService) removeAttachments(attachments []models.MessageAttachment) {
+	for _, attachment := range attachments {
+		os.Remove(s.mediaService.MessageAttachmentPath(attachment.FileName))
+	}
+}
+
+// toConversationResponses converts conversations to responses for a viewer,
+// loading their last messages and unread counts in two queries
+func (s *MessageService) toConversationResponses(conversations []models.Conversation, userID uint) ([]models.ConversationResponse, error) {
+	responses := make([]models.ConversationResponse, len(conversations))
+	if len(conversations) == 0 {
+		return responses, nil
+	}
+
+	ids := make([]uint, len(conversations))
+	for i, conversation := range conversations {
+		ids[i] = conversation.ID
+	}
+
+	var lastMessages []models.Message
+	if err := s.db.Where("id IN (?)", s.db.Model(&models.Message{}).
+		Select("MAX(id)").
+		Where("conversation_id IN ?", ids).
+		Group("conversation_id")).
+		Find(&lastMessages).Error; err != nil {
+		return nil, err
+	}
+	lastByConversation := make(map[uint]*models.Message, len(lastMessages))
+	for i := range lastMessages {
+		lastByConversation[lastMessages[i].ConversationID] = &lastMessages[i]
+	}
+
+	var unread []struct {
+		ConversationID uint
+		Count          int64
+	}
+	if err := s.unreadMessages(userID).
+		Select("messages.conversation_id, COUNT(*) AS count").
+		Where("messages.conversation_id IN ?", ids).
+		Group("messages.conversation_id").
+		Scan(&unread).Error; err != nil {
+		return nil, err
+	}
+	unreadByConversation := make(map[uint]int64, len(unread))
+	for _, row := range unread {
+		unreadByConversation[row.ConversationID] = row.Count
+	}
+
+	for i := range conversations {
+		conversation := &conversations[i]
+		participant := conversation.Agent
+		if userID == conversation.AgentID {
+			participant = conversation.Buyer
+		}
+
+		responses[i] = models.ConversationResponse{
+			ID:            conversation.ID,
+			PropertyID:    conversation.PropertyID,
+			PropertyTitle: conversation.Property.Title,
+			Participant: models.UserResponse{
+				ID:        participant.ID,
+				Email:     participant.Email,
+				FirstName: participant.FirstName,
+				LastName:  participant.LastName,
+				Role:      participant.Role,
+				Phone:     participant.Phone,
+				Avatar:    participant.Avatar,
+				IsActive:  participant.IsActive,
+				Version:   participant.Version,
+				CreatedAt: participant.CreatedAt,
+				UpdatedAt: participant.UpdatedAt,
+			},
+			UnreadCount:   unreadByConversation[conversation.ID],
+			LastMessageAt: conversation.LastMessageAt,
+			CreatedAt:     conversation.CreatedAt,
+		}
+		if message, ok := lastByConversation[conversation.ID]; ok {
+			lastMessage := s.toMessageResponse(message, conversation, userID)
+			responses[i].LastMessage = &lastMessage
+		}
+	}
+
+	return responses, nil
+}
+
+// toMessageResponse converts Message to MessageResponse for a viewer
+func (s *MessageService) toMessageResponse(message *models.Message, conversation *models.Conversation, userID uint) models.MessageResponse {
+	otherLastReadID := conversation.AgentLastReadID
+	if userID == conversation.AgentID {
+		otherLastReadID = conversation.BuyerLastReadID
+	}
+
+	response := models.MessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Body:           message.Body,
+		Attachments:    make([]models.MessageAttachmentResponse, len(message.Attachments)),
+		Read:           message.SenderID == userID && message.ID <= otherLastReadID,
+		Flagged:        message.Flagged,
+		CreatedAt:      message.CreatedAt,
+	}
+	for i, attachment := range message.Attachments {
+		response.Attachments[i] = models.MessageAttachmentResponse{
+			FileName: attachment.OriginalName,
+			FileURL:  fmt.Sprintf("/api/v1/conversations/%d/attachments/%s", message.ConversationID, attachment.FileName),
+			FileType: attachment.FileType,
+			FileSize: attachment.FileSize,
+		}
+	}
+
+	return response
+}
+
+// isParticipant reports whether a user is the buyer or agent of a conversation
+func isParticipant(conversation *models.Conversation, userID uint) bool {
+	return conversation.BuyerID == userID || conversation.AgentID == userID
+}
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/reso"
+
+	"gorm.io/gorm"
+)
+
+// squareFeetPerAcre converts RESO lot sizes in square feet to acres
+const squareFeetPerAcre = 43560
+
+// MLSService handles RESO Web API listing ingestion
+type MLSService struct {
+	db           *gorm.DB
+	listingRules []ListingRule
+	mu           sync.Mutex
+	running      map[uint]bool
+}
+
+// NewMLSService creates a new MLS ingestion service. Synced listings are
+// moderated with listingRules like listings entered by agents.
+func NewMLSService(db *gorm.DB, listingRules ...ListingRule) *MLSService {
+	return &MLSService{db: db, listingRules: listingRules, running: make(map[uint]bool)}
+}
+
+// CreateFeed registers a new RESO Web API feed
+func (s *MLSService) CreateFeed(req *models.MLSFeedCreateRequest) (*models.MLSFeedResponse, error) {
+	var agent models.User
+	if err := s.db.First(&agent, req.DefaultAgentID).Error; err != nil {
+		return nil, errors.New("default agent not found")
+	}
+
+	feed := models.MLSFeed{
+		Name:           req.Name,
+		BaseURL:        req.BaseURL,
+		AccessToken:    req.AccessToken,
+		DefaultAgentID: req.DefaultAgentID,
+		IsActive:       true,
+	}
+
+	if err := s.db.Create(&feed).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toFeedResponse(&feed), nil
+}
+
+// GetFeeds returns all registered feeds
+func (s *MLSService) GetFeeds() ([]models.MLSFeedResponse, error) {
+	var feeds []models.MLSFeed
+	if err := s.db.Order("id").Find(&feeds).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.MLSFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		responses[i] = *s.toFeedResponse(&feed)
+	}
+
+	return responses, nil
+}
+
+// errMissingListingKey marks records that can never be applied, so they do
+// not hold back the feed's watermark
+var errMissingListingKey = errors.New("missing ListingKey")
+
+// SyncFeed pulls every listing modified since the feed's high watermark and
+// upserts it by source listing key. The watermark is advanced after each
+// page, so an interrupted run resumes where it stopped, but never past a
+// record that failed, so the next run fetches it again.
+func (s *MLSService) SyncFeed(ctx context.Context, feedID uint) (*models.MLSSyncResult, error) {
+	var feed models.MLSFeed
+	if err := s.db.First(&feed, feedID).Error; err != nil {
+		return nil, err
+	}
+
+	if !s.acquire(feed.ID) {
+		return nil, errors.New("feed sync already in progress")
+	}
+	defer s.release(feed.ID)
+
+	result := &models.MLSSyncResult{
+		FeedID:        feed.ID,
+		StartedAt:     time.Now(),
+		HighWatermark: feed.LastModificationTimestamp,
+	}
+
+	client := reso.NewClient(feed.BaseURL, feed.AccessToken)
+	syncErr := syncRecords(ctx, client, result,
+		func(record *reso.Property) error {
+			return s.upsertListing(&feed, record, result)
+		},
+		func(watermark *time.Time) error {
+			return s.db.Model(&feed).Update("last_modification_timestamp", watermark).Error
+		})
+
+	result.FinishedAt = time.Now()
+
+	status, errMessage := "success", ""
+	if syncErr != nil {
+		status, errMessage = "failed", syncErr.Error()
+	} else if len(result.Errors) > 0 {
+		status = "partial"
+	}
+	if err := s.db.Model(&feed).Updates(map[string]interface{}{
+		"last_synced_at":   result.FinishedAt,
+		"last_sync_status": status,
+		"last_sync_error":  errMessage,
+	}).Error; err != nil {
+		log.Printf("mls feed %d: failed to record sync status: %v", feed.ID, err)
+	}
+
+	if syncErr != nil {
+		return result, syncErr
+	}
+	return result, nil
+}
+
+// syncRecords pages through the records modified since result's high
+// watermark, applying each with upsert and saving the watermark with save
+// after every page. The client fetches records at or after the watermark,
+// so once a record fails the watermark stays at its timestamp and the
+// record is fetched again by the next run; records already applied are
+// skipped then by their modification timestamp.
+func syncRecords(ctx context.Context, client *reso.Client, result *models.MLSSyncResult,
+	upsert func(record *reso.Property) error, save func(watermark *time.Time) error) error {
+	held := false
+	return client.FetchProperties(ctx, result.HighWatermark, func(page []reso.Property) error {
+		for i := range page {
+			result.Fetched++
+			err := upsert(&page[i])
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", page[i].ListingKey, err))
+			}
+			if held {
+				continue
+			}
+
+			ts := page[i].ModificationTimestamp
+			if result.HighWatermark == nil || ts.After(*result.HighWatermark) {
+				result.HighWatermark = &ts
+			}
+			held = err != nil && !errors.Is(err, errMissingListingKey)
+		}
+
+		return save(result.HighWatermark)
+	})
+}
+
+// SyncAll synchronizes every active feed
+func (s *MLSService) SyncAll(ctx context.Context) {
+	var feeds []models.MLSFeed
+	if err := s.db.Where("is_active = ?", true).Find(&feeds).Error; err != nil {
+		log.Printf("mls sync: failed to load feeds: %v", err)
+		return
+	}
+
+	for _, feed := range feeds {
+		result, err := s.SyncFeed(ctx, feed.ID)
+		if err != nil {
+			log.Printf("mls sync: feed %d failed: %v", feed.ID, err)
+			continue
+		}
+		log.Printf("mls sync: feed %d fetched=%d created=%d updated=%d removed=%d skipped=%d errors=%d",
+			feed.ID, result.Fetched, result.Created, result.Updated, result.Removed, result.Skipped, len(result.Errors))
+	}
+}
+
+// RunScheduler synchronizes all active feeds every interval until ctx is done
+func (s *MLSService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SyncAll(ctx)
+		}
+	}
+}
+
+// upsertListing creates, updates or removes the local copy of a RESO listing
+func (s *MLSService) upsertListing(feed *models.MLSFeed, record *reso.Property, result *models.MLSSyncResult) error {
+	if record.ListingKey == "" {
+		result.Skipped++
+		return errMissingListingKey
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var property models.Property
+		err := tx.Unscoped().
+			Where("source_feed_id = ? AND source_listing_key = ?", feed.ID, record.ListingKey).
+			First(&property).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// Records at or before our copy were already applied by a previous
+		// run, and listings an admin purged stay purged
+		if exists && (property.PurgedAt != nil ||
+			(property.SourceModifiedAt != nil && !record.ModificationTimestamp.After(*property.SourceModifiedAt))) {
+			result.Skipped++
+			return nil
+		}
+
+		if isOffMarket(record.StandardStatus) {
+			if !exists || property.DeletedAt.Valid {
+				result.Skipped++
+				return nil
+			}
+			if err := tx.Model(&property).Updates(map[string]interface{}{
+				"source_modified_at": record.ModificationTimestamp,
+				"version":            gorm.Expr("version + 1"),
+			}).Error; err != nil {
+				return err
+			}
+			if err := softDeleteProperty(tx, &property); err != nil {
+				return err
+			}
+			result.Removed++
+			return nil
+		}
+
+		original := property
+		if err := s.applyRecord(tx, feed, record, &property); err != nil {
+			return err
+		}
+		normalizePropertyAddress(&property)
+		lintListingDescription(&property, !exists || property.Description != original.Description)
+
+		var previous *models.Property
+		if exists {
+			previous = &original
+			if err := ensureBaselineRevision(tx, &original); err != nil {
+				return err
+			}
+			// A relisted record brings back what was removed with it
+			if original.DeletedAt.Valid {
+				if err := restoreDeletedChildren(tx, property.ID, original.DeletedAt.Time); err != nil {
+					return err
+				}
+			}
+			property.DeletedAt = gorm.DeletedAt{}
+			property.Version++
+			if err := tx.Unscoped().Save(&property).Error; err != nil {
+				return err
+			}
+			result.Updated++
+		} else {
+			if err := tx.Create(&property).Error; err != nil {
+				return err
+			}
+			result.Created++
+		}
+		if _, err := flagDuplicates(tx, &property); err != nil {
+			return err
+		}
+		if err := moderateListing(tx, s.listingRules, &property, previous); err != nil {
+			return err
+		}
+
+		if err := recordRevision(tx, &property, models.RevisionActionSynced, nil, nil); err != nil {
+			return err
+		}
+
+		synced, err := s.syncMedia(tx, property.ID, record.Media)
+		if err != nil {
+			return err
+		}
+		result.MediaSynced += synced
+
+		_, err = refreshQualityScore(tx, property.ID)
+		return err
+	})
+}
+
+// applyRecord maps RESO Data Dictionary fields onto a Property
+func (s *MLSService) applyRecord(tx *gorm.DB, feed *models.MLSFeed, record *reso.Property, property *models.Property) error {
+	address := record.UnparsedAddress
+	if address == "" {
+		address = strings.Join(nonEmpty(record.StreetNumber, record.StreetName, record.StreetSuffix), " ")
+		if record.UnitNumber != "" {
+			address += " #" + record.UnitNumber
+		}
+	}
+	if address == "" {
+		return errors.New("missing address")
+	}
+
+	propertyType, err := mapRESOPropertyType(record.PropertyType, record.PropertySubType)
+	if err != nil {
+		return err
+	}
+
+	bathrooms := float64(record.BathroomsTotalInteger)
+	if record.BathroomsFull > 0 || record.BathroomsHalf > 0 {
+		bathrooms = float64(record.BathroomsFull) + 0.5*float64(record.BathroomsHalf)
+	}
+
+	lotSize := record.LotSizeAcres
+	if lotSize == 0 && record.LotSizeSquareFeet > 0 {
+		lotSize = record.LotSizeSquareFeet / squareFeetPerAcre
+	}
+
+	country := record.Country
+	if country == "" {
+		country = "US"
+	}
+
+	var features []string
+	features = append(features, record.InteriorFeatures...)
+	features = append(features, record.ExteriorFeatures...)
+	features = append(features, record.Appliances...)
+
+	var images []string
+	for _, media := range sortedMedia(record.Media) {
+		if mediaFileType(media) == "image" {
+			images = append(images, media.MediaURL)
+		}
+	}
+
+	agentID := feed.DefaultAgentID
+	if record.ListAgentEmail != "" {
+		var agent models.User
+		if err := tx.Where("LOWER(email) = ?", strings.ToLower(record.ListAgentEmail)).First(&agent).Error; err == nil {
+			agentID = agent.ID
+		}
+	}
+
+	listingKey := record.ListingKey
+	modifiedAt := record.ModificationTimestamp
+
+	property.Title = address
+	property.Description = record.PublicRemarks
+	property.Price = record.ListPrice
+	property.Address = address
+	property.City = record.City
+	property.State = record.StateOrProvince
+	property.ZipCode = record.PostalCode
+	property.Country = country
+	property.PropertyType = propertyType
+	property.Status = mapRESOStatus(record.StandardStatus, record.PropertyType)
+	property.Bedrooms = record.BedroomsTotal
+	property.Bathrooms = bathrooms
+	property.SquareFeet = int(record.LivingArea)
+	property.YearBuilt = record.YearBuilt
+	property.LotSize = lotSize
+	property.Features = features
+	property.Images = images
+	property.VRModelURL = record.VirtualTourURLUnbranded
+	property.AgentID = agentID
+	property.SourceFeedID = &feed.ID
+	property.SourceListingKey = &listingKey
+	property.SourceModifiedAt = &modifiedAt
+
+	property.ListingType = models.ListingTypeSale
+	property.MonthlyRent = nil
+	if isRESOLease(record.PropertyType) {
+		monthlyRent := record.ListPrice
+		property.ListingType = models.ListingTypeRent
+		property.MonthlyRent = &monthlyRent
+		property.Price = 0
+	}
+
+	property.Latitude, property.Longitude = nil, nil
+	if record.Latitude != 0 || record.Longitude != 0 {
+		latitude, longitude := record.Latitude, record.Longitude
+		property.Latitude, property.Longitude = &latitude, &longitude
+	}
+
+	property.SoldPrice, property.SoldAt = nil, nil
+	if property.Status == models.PropertyStatusSold {
+		soldPrice := record.ClosePrice
+		if soldPrice == 0 {
+			soldPrice = record.ListPrice
+		}
+		soldAt := modifiedAt
+		if closeDate, err := time.Parse(reso.DateFormat, record.CloseDate); err == nil {
+			soldAt = closeDate
+		}
+		property.SoldPrice, property.SoldAt = &soldPrice, &soldAt
+	}
+
+	return nil
+}
+
+// syncMedia upserts RESO media by MediaKey and deactivates media that the
+// listing no longer references
+func (s *MLSService) syncMedia(tx *gorm.DB, propertyID uint, media []reso.Media) (int, error) {
+	var existing []models.MediaFile
+	// Media in the trash keeps its key so a sync does not duplicate it
+	if err := tx.Unscoped().Where("property_id = ? AND source_media_key IS NOT NULL", propertyID).Find(&existing).Error; err != nil {
+		return 0, err
+	}
+
+	byKey := make(map[string]*models.MediaFile, len(existing))
+	for i := range existing {
+		byKey[*existing[i].SourceMediaKey] = &existing[i]
+	}
+
+	seen := make(map[string]bool, len(media))
+	synced := 0
+	for _, item := range sortedMedia(media) {
+		if item.MediaKey == "" || item.MediaURL == "" {
+			continue
+		}
+		seen[item.MediaKey] = true
+
+		mediaFile, ok := byKey[item.MediaKey]
+		if !ok {
+			mediaKey := item.MediaKey
+			mediaFile = &models.MediaFile{PropertyID: propertyID, SourceMediaKey: &mediaKey}
+		}
+
+		mediaFile.FileName = item.MediaKey + path.Ext(item.MediaURL)
+		mediaFile.FileURL = item.MediaURL
+		mediaFile.FileType = mediaFileType(item)
+		mediaFile.SortOrder = item.Order
+		mediaFile.IsActive = true
+		if item.ImageWidth > 0 && item.ImageHeight > 0 {
+			width, height := item.ImageWidth, item.ImageHeight
+			mediaFile.Width = &width
+			mediaFile.Height = &height
+		}
+
+		if err := tx.Save(mediaFile).Error; err != nil {
+			return synced, err
+		}
+		synced++
+	}
+
+	for key, mediaFile := range byKey {
+		if !seen[key] && mediaFile.IsActive {
+			if err := tx.Model(mediaFile).Update("is_active", false).Error; err != nil {
+				return synced, err
+			}
+		}
+	}
+
+	return synced, nil
+}
+
+func (s *MLSService) acquire(feedID uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[feedID] {
+		return false
+	}
+	s.running[feedID] = true
+	return true
+}
+
+func (s *MLSService) release(feedID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, feedID)
+}
+
+// toFeedResponse converts MLSFeed to MLSFeedResponse
+func (s *MLSService) toFeedResponse(feed *models.MLSFeed) *models.MLSFeedResponse {
+	return &models.MLSFeedResponse{
+		ID:                        feed.ID,
+		Name:                      feed.Name,
+		BaseURL:                   feed.BaseURL,
+		DefaultAgentID:            feed.DefaultAgentID,
+		IsActive:                  feed.IsActive,
+		LastModificationTimestamp: feed.LastModificationTimestamp,
+		LastSyncedAt:              feed.LastSyncedAt,
+		LastSyncStatus:            feed.LastSyncStatus,
+		LastSyncError:             feed.LastSyncError,
+		CreatedAt:                 feed.CreatedAt,
+		UpdatedAt:                 feed.UpdatedAt,
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
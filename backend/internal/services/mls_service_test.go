@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/reso"
+)
+
+// fixtureRecords returns one record per key, a minute apart from base
+func fixtureRecords(base time.Time, keys ...string) []reso.Property {
+	records := make([]reso.Property, len(keys))
+	for i, key := range keys {
+		records[i] = reso.Property{
+			ListingKey:            key,
+			StandardStatus:        reso.StatusActive,
+			ModificationTimestamp: base.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return records
+}
+
+// runFixtureSync syncs from a fixture server serving pages of two records,
+// failing the records whose keys are in fail. It returns the result and
+// the watermark saved after each page.
+func runFixtureSync(t *testing.T, records []reso.Property, since *time.Time, fail map[string]error) (*models.MLSSyncResult, []time.Time) {
+	t.Helper()
+
+	handler := reso.NewFixtureHandler(records)
+	handler.MaxPageSize = 2
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	result := &models.MLSSyncResult{HighWatermark: since}
+	var saved []time.Time
+	err := syncRecords(context.Background(), reso.NewClient(server.URL, ""), result,
+		func(record *reso.Property) error {
+			return fail[record.ListingKey]
+		},
+		func(watermark *time.Time) error {
+			saved = append(saved, *watermark)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("syncRecords: %v", err)
+	}
+	return result, saved
+}
+
+func TestSyncRecordsPagesAndAdvancesWatermark(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := fixtureRecords(base, "A", "B", "C", "D", "E")
+
+	result, saved := runFixtureSync(t, records, nil, nil)
+
+	if result.Fetched != 5 {
+		t.Errorf("fetched %d records, want 5", result.Fetched)
+	}
+	want := []time.Time{records[1].ModificationTimestamp, records[3].ModificationTimestamp, records[4].ModificationTimestamp}
+	if len(saved) != len(want) {
+		t.Fatalf("saved %d watermarks, want one per page (%d)", len(saved), len(want))
+	}
+	for i := range want {
+		if !saved[i].Equal(want[i]) {
+			t.Errorf("watermark after page %d is %v, want %v", i+1, saved[i], want[i])
+		}
+	}
+}
+
+func TestSyncRecordsResumesFromWatermark(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := fixtureRecords(base, "A", "B", "C", "D", "E")
+	since := records[3].ModificationTimestamp
+
+	result, _ := runFixtureSync(t, records, &since, nil)
+
+	if result.Fetched != 2 {
+		t.Errorf("fetched %d records, want the 2 at or after the watermark", result.Fetched)
+	}
+	if !result.HighWatermark.Equal(records[4].ModificationTimestamp) {
+		t.Errorf("watermark is %v, want %v", result.HighWatermark, records[4].ModificationTimestamp)
+	}
+}
+
+func TestSyncRecordsHoldsWatermarkAtFailedRecord(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := fixtureRecords(base, "A", "B", "C", "D", "E")
+	failedAt := records[2].ModificationTimestamp
+
+	result, saved := runFixtureSync(t, records, nil, map[string]error{"C": errors.New("missing address")})
+
+	if result.Fetched != 5 {
+		t.Errorf("fetched %d records, want 5", result.Fetched)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("got errors %v, want one for C", result.Errors)
+	}
+	for i, watermark := range saved {
+		if watermark.After(failedAt) {
+			t.Errorf("watermark after page %d is %v, past the failed record at %v", i+1, watermark, failedAt)
+		}
+	}
+	if !result.HighWatermark.Equal(failedAt) {
+		t.Fatalf("watermark is %v, want %v", result.HighWatermark, failedAt)
+	}
+
+	// The next run fetches the failed record and everything after it again
+	retry, _ := runFixtureSync(t, records, result.HighWatermark, nil)
+	if retry.Fetched != 3 {
+		t.Errorf("retry fetched %d records, want C, D and E", retry.Fetched)
+	}
+	if !retry.HighWatermark.Equal(records[4].ModificationTimestamp) {
+		t.Errorf("retry watermark is %v, want %v", retry.HighWatermark, records[4].ModificationTimestamp)
+	}
+}
+
+func TestSyncRecordsSkipsRecordsWithoutListingKey(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := fixtureRecords(base, "A", "", "C")
+
+	result, _ := runFixtureSync(t, records, nil, map[string]error{"": errMissingListingKey})
+
+	if len(result.Errors) != 1 {
+		t.Errorf("got errors %v, want one for the record without a key", result.Errors)
+	}
+	if !result.HighWatermark.Equal(records[2].ModificationTimestamp) {
+		t.Errorf("watermark is %v, want %v", result.HighWatermark, records[2].ModificationTimestamp)
+	}
+}
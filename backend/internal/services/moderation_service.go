@@ -0,0 +1,344 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Flag rules recorded for content queued other than by a listing rule
+const (
+	moderationRuleUserReport  = "user_report"
+	moderationRuleResubmitted = "resubmitted"
+)
+
+// ModerationService handles the listing and media moderation queue
+type ModerationService struct {
+	db       *gorm.DB
+	notifier Notifier
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(db *gorm.DB, notifier Notifier) *ModerationService {
+	return &ModerationService{db: db, notifier: notifier}
+}
+
+// GetQueue lists moderation items, oldest first so the queue is worked in
+// order. Items of deleted listings are left out.
+func (s *ModerationService) GetQueue(req *models.ModerationListRequest) (*models.PaginationResponse, error) {
+	if req.Status != "" && !req.Status.IsValid() {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "status", Message: "must be pending, approved or rejected"}}}
+	}
+	if req.ItemType != "" && !req.ItemType.IsValid() {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "item_type", Message: "must be property or media"}}}
+	}
+
+	query := s.db.Model(&models.ModerationItem{}).
+		Joins("JOIN properties p ON p.id = moderation_items.property_id AND p.deleted_at IS NULL")
+	if req.Status != "" {
+		query = query.Where("moderation_items.status = ?", req.Status)
+	}
+	if req.ItemType != "" {
+		query = query.Where("moderation_items.item_type = ?", req.ItemType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var items []models.ModerationItem
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property").Preload("MediaFile", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).
+		Order("moderation_items.created_at, moderation_items.id").
+		Offset(offset).Limit(req.PageSize).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ModerationItemResponse, len(items))
+	for i := range items {
+		responses[i] = *toModerationItemResponse(&items[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// ApproveItem approves a pending item. The listing or media file is shown
+// again if it had been rejected.
+func (s *ModerationService) ApproveItem(id, adminID uint, req *models.ModerationReviewRequest) (*models.ModerationItemResponse, error) {
+	return s.reviewItem(id, adminID, models.ModerationStatusApproved, req.Reason)
+}
+
+// RejectItem rejects a pending item, hiding the listing or media file, and
+// tells the listing agent why
+func (s *ModerationService) RejectItem(id, adminID uint, req *models.ModerationReviewRequest) (*models.ModerationItemResponse, error) {
+	if req.Reason == "" {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "reason", Message: "a reason is required to reject content"}}}
+	}
+
+	item, err := s.reviewItem(id, adminID, models.ModerationStatusRejected, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	var property models.Property
+	if err := s.db.Preload("Agent").First(&property, item.PropertyID).Error; err != nil {
+		return nil, err
+	}
+	subject := fmt.Sprintf("Your listing %q was rejected", property.Title)
+	if item.ItemType == models.ModerationItemMedia {
+		subject = fmt.Sprintf("A photo on your listing %q was rejected", property.Title)
+	}
+	if err := s.notifier.Notify(Notification{UserID: property.Agent.ID, Email: property.Agent.Email, Subject: subject, Body: req.Reason}); err != nil {
+		log.Printf("failed to notify agent %d about rejected listing %d: %v", property.Agent.ID, property.ID, err)
+	}
+
+	return item, nil
+}
+
+// ReportListing reports a listing, or one of its media files, as
+// inappropriate. The content stays visible and is queued for review.
+func (s *ModerationService) ReportListing(propertyID, reporterID uint, req *models.ModerationReportRequest) (*models.ModerationItemResponse, error) {
+	item := &models.ModerationItem{
+		ItemType:     models.ModerationItemProperty,
+		PropertyID:   propertyID,
+		Flags:        []models.ModerationFlag{{Rule: moderationRuleUserReport, Reason: req.Reason}},
+		Status:       models.ModerationStatusPending,
+		ReportedByID: &reporterID,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var property models.Property
+		if err := tx.First(&property, propertyID).Error; err != nil {
+			return err
+		}
+
+		target := tx.Model(&property)
+		status := property.ModerationStatus
+		if req.MediaFileID != nil {
+			var mediaFile models.MediaFile
+			if err := tx.Where("id = ? AND property_id = ?", *req.MediaFileID, propertyID).First(&mediaFile).Error; err != nil {
+				return err
+			}
+			item.ItemType = models.ModerationItemMedia
+			item.MediaFileID = &mediaFile.ID
+			target = tx.Model(&mediaFile)
+			status = mediaFile.ModerationStatus
+		}
+
+		if err := queueModeration(tx, item); err != nil {
+			return err
+		}
+		// Rejected content stays hidden while the report is reviewed
+		if status == models.ModerationStatusApproved {
+			return target.UpdateColumn("moderation_status", models.ModerationStatusPending).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getItem(item.ID)
+}
+
+// reviewItem records an admin decision on a pending item and applies it to
+// the listing or media file
+func (s *ModerationService) reviewItem(id, adminID uint, status models.ModerationStatus, reason string) (*models.ModerationItemResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var item models.ModerationItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error; err != nil {
+			return err
+		}
+		if item.Status != models.ModerationStatusPending {
+			return fmt.Errorf("moderation item has already been %s", item.Status)
+		}
+
+		var property models.Property
+		if err := tx.First(&property, item.PropertyID).Error; err != nil {
+			return err
+		}
+
+		// Decisions touch the listing's updated_at so syndication partners
+		// pulling changes pick them up
+		now := time.Now()
+		switch item.ItemType {
+		case models.ModerationItemMedia:
+			var mediaFile models.MediaFile
+			if err := tx.First(&mediaFile, item.MediaFileID).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&mediaFile).Update("moderation_status", status).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&property).Update("updated_at", now).Error; err != nil {
+				return err
+			}
+			// Rejected photos no longer count towards the listing's quality
+			if _, err := refreshQualityScore(tx, property.ID); err != nil {
+				return err
+			}
+		default:
+			if err := tx.Model(&property).Update("moderation_status", status).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&item).Updates(map[string]interface{}{
+			"status":         status,
+			"review_reason":  reason,
+			"reviewed_by_id": adminID,
+			"reviewed_at":    now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getItem(id)
+}
+
+// getItem loads a moderation item as a response
+func (s *ModerationService) getItem(id uint) (*models.ModerationItemResponse, error) {
+	var item models.ModerationItem
+	if err := s.db.Preload("Property").Preload("MediaFile", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return toModerationItemResponse(&item), nil
+}
+
+// moderateListing runs a live listing's text through the listing rules when
+// it is new or its text changed, and queues it for review if a rule flags
+// it. Flagged listings stay visible as pending. A rejected listing that is
+// edited is queued again and stays hidden until it is approved. previous is
+// the listing before the edit, or nil for a new listing.
+func moderateListing(tx *gorm.DB, rules []ListingRule, property *models.Property, previous *models.Property) error {
+	var flags []models.ModerationFlag
+	if previous == nil || listingText(previous) != listingText(property) {
+		for _, rule := range rules {
+			flags = append(flags, rule.CheckListing(property)...)
+		}
+	}
+
+	// An admin may have reviewed the listing since it was loaded
+	var current models.Property
+	if err := tx.Select("moderation_status").First(&current, property.ID).Error; err != nil {
+		return err
+	}
+	status := current.ModerationStatus
+	switch {
+	case status == models.ModerationStatusRejected:
+		flags = append(flags, models.ModerationFlag{Rule: moderationRuleResubmitted, Reason: "listing was edited after it was rejected"})
+	case len(flags) == 0:
+		return nil
+	default:
+		status = models.ModerationStatusPending
+	}
+
+	if err := queueModeration(tx, &models.ModerationItem{
+		ItemType:   models.ModerationItemProperty,
+		PropertyID: property.ID,
+		Flags:      flags,
+		Status:     models.ModerationStatusPending,
+	}); err != nil {
+		return err
+	}
+
+	property.ModerationStatus = status
+	if status == current.ModerationStatus {
+		return nil
+	}
+	return tx.Model(property).UpdateColumn("moderation_status", status).Error
+}
+
+// queueModeration adds a pending item to the queue, or adds its flags to the
+// content's pending item if it already has one
+func queueModeration(tx *gorm.DB, item *models.ModerationItem) error {
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("property_id = ? AND status = ?", item.PropertyID, models.ModerationStatusPending)
+	if item.MediaFileID != nil {
+		query = query.Where("media_file_id = ?", *item.MediaFileID)
+	} else {
+		query = query.Where("media_file_id IS NULL")
+	}
+
+	var pending models.ModerationItem
+	err := query.First(&pending).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Create(item).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, flag := range item.Flags {
+		if !hasModerationFlag(pending.Flags, flag) {
+			pending.Flags = append(pending.Flags, flag)
+		}
+	}
+	*item = pending
+	return tx.Model(item).Select("flags", "updated_at").Updates(item).Error
+}
+
+func hasModerationFlag(flags []models.ModerationFlag, flag models.ModerationFlag) bool {
+	for _, existing := range flags {
+		if existing == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleMedia restricts a media file query to media not rejected by
+// moderation
+func visibleMedia(query *gorm.DB) *gorm.DB {
+	return query.Where("moderation_status <> ?", models.ModerationStatusRejected)
+}
+
+// toModerationItemResponse converts a moderation item with its listing and
+// media file loaded to a response
+func toModerationItemResponse(item *models.ModerationItem) *models.ModerationItemResponse {
+	response := &models.ModerationItemResponse{
+		ID:            item.ID,
+		ItemType:      item.ItemType,
+		Status:        item.Status,
+		Flags:         item.Flags,
+		PropertyID:    item.PropertyID,
+		PropertyTitle: item.Property.Title,
+		AgentID:       item.Property.AgentID,
+		MediaFileID:   item.MediaFileID,
+		ReportedByID:  item.ReportedByID,
+		ReviewReason:  item.ReviewReason,
+		ReviewedByID:  item.ReviewedByID,
+		ReviewedAt:    item.ReviewedAt,
+		CreatedAt:     item.CreatedAt,
+	}
+	if item.ItemType == models.ModerationItemProperty {
+		response.Description = item.Property.Description
+	}
+	if item.MediaFile != nil {
+		response.MediaURL = item.MediaFile.FileURL
+	}
+	if response.Flags == nil {
+		response.Flags = []models.ModerationFlag{}
+	}
+	return response
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"galactavista/internal/models"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMortgageTermYears = 30
+	defaultFrontEndRatio     = 0.28
+	defaultBackEndRatio      = 0.36
+	// pmiLoanToValue is the loan-to-value ratio at which mortgage insurance
+	// is no longer charged
+	pmiLoanToValue = 0.8
+)
+
+// Amortization schedule granularity
+const (
+	scheduleNone    = "none"
+	scheduleMonthly = "monthly"
+	scheduleYearly  = "yearly"
+)
+
+// MortgageService handles mortgage payment and affordability calculations
+type MortgageService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewMortgageService creates a new mortgage service
+func NewMortgageService(db *gorm.DB, propertyService *PropertyService) *MortgageService {
+	return &MortgageService{db: db, propertyService: propertyService}
+}
+
+// CalculateMortgage calculates the monthly payment breakdown and
+// amortization schedule of a loan
+func (s *MortgageService) CalculateMortgage(req *models.MortgageRequest) (*models.MortgageResponse, error) {
+	price := req.Price
+	if req.PropertyID != nil {
+		var property models.Property
+		if err := s.db.First(&property, *req.PropertyID).Error; err != nil {
+			return nil, err
+		}
+		if property.ListingType == models.ListingTypeRent {
+			return nil, &ValidationError{Errors: []models.FieldError{{Field: "property_id", Message: "rental listings have no purchase price"}}}
+		}
+		price = property.Price
+	}
+
+	var fieldErrors []models.FieldError
+	if price <= 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "price", Message: "a price or property_id is required"})
+	}
+	if req.DownPayment != nil && req.DownPaymentPercent != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "down_payment", Message: "give either down_payment or down_payment_percent"})
+	}
+	if req.PropertyTaxRate != nil && req.AnnualPropertyTax != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "property_tax_rate", Message: "give either property_tax_rate or annual_property_tax"})
+	}
+
+	downPayment := 0.0
+	switch {
+	case req.DownPayment != nil:
+		downPayment = *req.DownPayment
+	case req.DownPaymentPercent != nil:
+		downPayment = price * *req.DownPaymentPercent / 100
+	}
+	if downPayment > price {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "down_payment", Message: "must not exceed the price"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	termYears := req.TermYears
+	if termYears == 0 {
+		termYears = defaultMortgageTermYears
+	}
+	scheduleType := req.Schedule
+	if scheduleType == "" {
+		scheduleType = scheduleYearly
+	}
+
+	annualTax := 0.0
+	switch {
+	case req.AnnualPropertyTax != nil:
+		annualTax = *req.AnnualPropertyTax
+	case req.PropertyTaxRate != nil:
+		annualTax = price * *req.PropertyTaxRate / 100
+	}
+
+	loan := price - downPayment
+	months := termYears * 12
+	payment := loan * paymentFactor(*req.InterestRate, months)
+	monthlyRate := *req.InterestRate / 100 / 12
+	monthlyPMI := 0.0
+	if loan > price*pmiLoanToValue {
+		monthlyPMI = loan * req.PMIRate / 100 / 12
+	}
+
+	response := &models.MortgageResponse{
+		PropertyID:         req.PropertyID,
+		Price:              roundCents(price),
+		DownPayment:        roundCents(downPayment),
+		DownPaymentPercent: roundCents(downPayment / price * 100),
+		LoanAmount:         roundCents(loan),
+		InterestRate:       *req.InterestRate,
+		TermYears:          termYears,
+		ScheduleType:       scheduleType,
+	}
+	response.Monthly = paymentBreakdown(payment, annualTax, req.AnnualInsurance, req.MonthlyHOA, monthlyPMI)
+
+	balance := loan
+	var totalInterest, totalPaid float64
+	var year models.AmortizationEntry
+	for month := 1; month <= months && balance > 0; month++ {
+		interest := balance * monthlyRate
+		principal := math.Min(payment-interest, balance)
+		pmi := 0.0
+		if balance > price*pmiLoanToValue {
+			pmi = monthlyPMI
+			if pmi > 0 {
+				response.PMIMonths++
+			}
+		}
+		balance -= principal
+		if balance < 0.005 {
+			balance = 0
+		}
+		totalInterest += interest
+		totalPaid += principal + interest
+
+		entry := models.AmortizationEntry{
+			Period:    month,
+			Payment:   principal + interest,
+			Principal: principal,
+			Interest:  interest,
+			PMI:       pmi,
+			Balance:   balance,
+		}
+		switch scheduleType {
+		case scheduleMonthly:
+			response.Schedule = append(response.Schedule, roundAmortizationEntry(entry))
+		case scheduleYearly:
+			year.Period = (month-1)/12 + 1
+			year.Payment += entry.Payment
+			year.Principal += entry.Principal
+			year.Interest += entry.Interest
+			year.PMI += entry.PMI
+			year.Balance = balance
+			if month%12 == 0 || month == months || balance == 0 {
+				response.Schedule = append(response.Schedule, roundAmortizationEntry(year))
+				year = models.AmortizationEntry{}
+			}
+		}
+	}
+	response.TotalInterest = roundCents(totalInterest)
+	response.TotalOfPayments = roundCents(totalPaid)
+
+	return response, nil
+}
+
+// FindAffordableListings works out the highest price a buyer can afford and
+// searches listings up to that price
+func (s *MortgageService) FindAffordableListings(req *models.AffordabilityRequest) (*models.AffordabilityResponse, error) {
+	termYears := req.TermYears
+	if termYears == 0 {
+		termYears = defaultMortgageTermYears
+	}
+	frontEndRatio := req.FrontEndRatio
+	if frontEndRatio == 0 {
+		frontEndRatio = defaultFrontEndRatio
+	}
+	backEndRatio := req.BackEndRatio
+	if backEndRatio == 0 {
+		backEndRatio = defaultBackEndRatio
+	}
+
+	monthlyIncome := req.AnnualIncome / 12
+	budget := monthlyIncome * frontEndRatio
+	limitedBy := "front_end_ratio"
+	if backEndBudget := monthlyIncome*backEndRatio - req.MonthlyDebts; backEndBudget < budget {
+		budget = backEndBudget
+		limitedBy = "back_end_ratio"
+	}
+
+	// Housing cost at price P is (P - down) * factor + P * taxRate + fixed,
+	// which is linear in P, so the budget can be solved for P directly
+	factor := paymentFactor(*req.InterestRate, termYears*12)
+	monthlyTaxRate := req.PropertyTaxRate / 100 / 12
+	available := budget - req.AnnualInsurance/12 - req.MonthlyHOA
+	maxPrice := 0.0
+	if available > 0 {
+		maxPrice = (available + req.DownPayment*factor) / (factor + monthlyTaxRate)
+		if maxPrice < req.DownPayment {
+			// The down payment covers the whole price; only taxes limit it
+			maxPrice = req.DownPayment
+			if monthlyTaxRate > 0 {
+				maxPrice = math.Min(maxPrice, available/monthlyTaxRate)
+			}
+		}
+	}
+	maxPrice = math.Floor(maxPrice/1000) * 1000
+	loan := math.Max(maxPrice-req.DownPayment, 0)
+
+	search := models.PropertySearchRequest{}
+	if req.Search != nil {
+		search = *req.Search
+	}
+	if search.MaxPrice == nil || *search.MaxPrice > maxPrice {
+		search.MaxPrice = &maxPrice
+	}
+	if search.Status == nil {
+		status := models.PropertyStatusAvailable
+		search.Status = &status
+	}
+	if search.Page < 1 {
+		search.Page = 1
+	}
+	if search.PageSize < 1 || search.PageSize > 100 {
+		search.PageSize = 10
+	}
+	listings, err := s.propertyService.SearchProperties(&search)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AffordabilityResponse{
+		MaxPrice:          maxPrice,
+		MaxLoanAmount:     roundCents(loan),
+		MaxMonthlyPayment: roundCents(math.Max(budget, 0)),
+		LimitedBy:         limitedBy,
+		Monthly:           paymentBreakdown(loan*factor, maxPrice*req.PropertyTaxRate/100, req.AnnualInsurance, req.MonthlyHOA, 0),
+		Listings:          listings,
+	}, nil
+}
+
+// paymentFactor returns the monthly principal and interest payment per
+// dollar borrowed at an annual percentage rate over a number of months
+func paymentFactor(annualRate float64, months int) float64 {
+	monthlyRate := annualRate / 100 / 12
+	if monthlyRate == 0 {
+		return 1 / float64(months)
+	}
+	return monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(months)))
+}
+
+// paymentBreakdown builds a monthly PITI breakdown
+func paymentBreakdown(principalAndInterest, annualTax, annualInsurance, monthlyHOA, monthlyPMI float64) models.PaymentBreakdown {
+	breakdown := models.PaymentBreakdown{
+		PrincipalAndInterest: roundCents(principalAndInterest),
+		PropertyTax:          roundCents(annualTax / 12),
+		Insurance:            roundCents(annualInsurance / 12),
+		HOA:                  roundCents(monthlyHOA),
+		PMI:                  roundCents(monthlyPMI),
+	}
+	breakdown.Total = roundCents(breakdown.PrincipalAndInterest + breakdown.PropertyTax +
+		breakdown.Insurance + breakdown.HOA + breakdown.PMI)
+	return breakdown
+}
+
+// roundAmortizationEntry rounds an amortization entry's amounts to cents
+func roundAmortizationEntry(entry models.AmortizationEntry) models.AmortizationEntry {
+	entry.Payment = roundCents(entry.Payment)
+	entry.Principal = roundCents(entry.Principal)
+	entry.Interest = roundCents(entry.Interest)
+	entry.PMI = roundCents(entry.PMI)
+	entry.Balance = roundCents(entry.Balance)
+	return entry
+}
+
+// roundCents rounds a dollar amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
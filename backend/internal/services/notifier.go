@@ -0,0 +1,34 @@
+package services
+
+import (
+	"log"
+)
+
+// Notification is a message to a single user
+type Notification struct {
+	UserID  uint
+	Email   string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers notifications to users. Implementations must be safe
+// for concurrent use.
+type Notifier interface {
+	Notify(notification Notification) error
+}
+
+// LogNotifier writes notifications to the application log. It is the
+// default notifier until a delivery provider is configured.
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify logs the notification
+func (n *LogNotifier) Notify(notification Notification) error {
+	log.Printf("notify user %d <%s>: %s: %s", notification.UserID, notification.Email, notification.Subject, notification.Body)
+	return nil
+}
@@ -0,0 +1,519 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// offerDateFormat is the format of offer closing dates and milestone due dates
+const offerDateFormat = "2006-01-02"
+
+// OfferService handles offers and counter-offers on properties
+type OfferService struct {
+	db       *gorm.DB
+	notifier Notifier
+}
+
+// NewOfferService creates a new offer service
+func NewOfferService(db *gorm.DB, notifier Notifier) *OfferService {
+	return &OfferService{db: db, notifier: notifier}
+}
+
+// SubmitOffer submits a buyer's offer on an available property
+func (s *OfferService) SubmitOffer(propertyID, buyerID uint, req *models.OfferRequest) (*models.OfferResponse, error) {
+	closingDate, fieldErrors := validateOfferRequest(req)
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID == buyerID {
+		return nil, errors.New("you cannot make an offer on your own listing")
+	}
+	if property.ListingType == models.ListingTypeRent {
+		return nil, errors.New("rental listings do not take purchase offers")
+	}
+	if property.Status != models.PropertyStatusAvailable {
+		return nil, errors.New("listing is not accepting offers")
+	}
+
+	var open int64
+	if err := s.db.Model(&models.Offer{}).Scopes(openOffers).
+		Where("property_id = ? AND buyer_id = ?", propertyID, buyerID).
+		Count(&open).Error; err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		return nil, errors.New("you already have an open offer on this property")
+	}
+
+	offer := models.Offer{
+		PropertyID:    propertyID,
+		BuyerID:       buyerID,
+		AgentID:       property.AgentID,
+		ProposedBy:    buyerID,
+		Amount:        req.Amount,
+		EarnestMoney:  req.EarnestMoney,
+		Contingencies: normalizeContingencies(req.Contingencies),
+		ClosingDate:   closingDate,
+		ExpiresAt:     req.ExpiresAt,
+		Message:       req.Message,
+		Status:        models.OfferStatusSubmitted,
+	}
+	if err := s.db.Create(&offer).Error; err != nil {
+		return nil, err
+	}
+
+	s.notifyOffer(property.AgentID, "New offer on "+property.Title,
+		fmt.Sprintf("An offer of %.2f was submitted on %s.", offer.Amount, property.Title))
+
+	return s.GetOffer(offer.ID, buyerID)
+}
+
+// GetOffers lists the offers an agent received, or a buyer's own offers
+func (s *OfferService) GetOffers(userID uint, asAgent bool, req *models.OfferListRequest) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Offer{})
+	if asAgent {
+		query = query.Where("agent_id = ?", userID)
+	} else {
+		query = query.Where("buyer_id = ?", userID)
+	}
+	return s.listOffers(query, req)
+}
+
+// GetPropertyOffers lists the offers on one of an agent's properties
+func (s *OfferService) GetPropertyOffers(propertyID, agentID uint, req *models.OfferListRequest) (*models.PaginationResponse, error) {
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return s.listOffers(s.db.Model(&models.Offer{}).Where("property_id = ?", propertyID), req)
+}
+
+// GetOffer gets an offer with the earlier rounds of its negotiation
+func (s *OfferService) GetOffer(id, userID uint) (*models.OfferResponse, error) {
+	var offer models.Offer
+	if err := s.db.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").
+		First(&offer, id).Error; err != nil {
+		return nil, err
+	}
+	if offer.BuyerID != userID && offer.AgentID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	response := toOfferResponse(&offer)
+	for parentID := offer.ParentID; parentID != nil; {
+		var parent models.Offer
+		if err := s.db.First(&parent, *parentID).Error; err != nil {
+			return nil, err
+		}
+		parent.Property = offer.Property
+		parent.Buyer = offer.Buyer
+		response.History = append([]models.OfferResponse{*toOfferResponse(&parent)}, response.History...)
+		parentID = parent.ParentID
+	}
+
+	if offer.Status == models.OfferStatusAccepted {
+		var transaction models.Transaction
+		if err := s.db.Where("offer_id = ?", offer.ID).First(&transaction).Error; err == nil {
+			response.TransactionID = &transaction.ID
+		}
+	}
+
+	return response, nil
+}
+
+// CounterOffer answers an open offer with new terms. The answered offer is
+// closed as countered and the counter-offer awaits the other party.
+func (s *OfferService) CounterOffer(id, userID uint, req *models.OfferRequest) (*models.OfferResponse, error) {
+	closingDate, fieldErrors := validateOfferRequest(req)
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	var counter models.Offer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		offer, err := lockRespondableOffer(tx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		var property models.Property
+		if err := tx.First(&property, offer.PropertyID).Error; err != nil {
+			return err
+		}
+		if property.Status != models.PropertyStatusAvailable {
+			return errors.New("listing is not accepting offers")
+		}
+
+		if err := tx.Model(offer).Update("status", models.OfferStatusCountered).Error; err != nil {
+			return err
+		}
+
+		counter = models.Offer{
+			PropertyID:    offer.PropertyID,
+			BuyerID:       offer.BuyerID,
+			AgentID:       offer.AgentID,
+			ParentID:      &offer.ID,
+			ProposedBy:    userID,
+			Amount:        req.Amount,
+			EarnestMoney:  req.EarnestMoney,
+			Contingencies: normalizeContingencies(req.Contingencies),
+			ClosingDate:   closingDate,
+			ExpiresAt:     req.ExpiresAt,
+			Message:       req.Message,
+			Status:        models.OfferStatusSubmitted,
+		}
+		return tx.Create(&counter).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOffer(offerCounterparty(&counter, userID), "Counter-offer received",
+		fmt.Sprintf("A counter-offer of %.2f was made on offer #%d.", counter.Amount, id))
+
+	return s.GetOffer(counter.ID, userID)
+}
+
+// AcceptOffer accepts an open offer. The listing moves to pending, every
+// other open offer on it is rejected, and a transaction is opened.
+func (s *OfferService) AcceptOffer(id, userID uint) (*models.OfferResponse, error) {
+	var offer *models.Offer
+	var rejected []models.Offer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = lockRespondableOffer(tx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		var property models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&property, offer.PropertyID).Error; err != nil {
+			return err
+		}
+		if property.Status != models.PropertyStatusAvailable {
+			return errors.New("listing is no longer available")
+		}
+		if err := changePropertyStatus(tx, property.ID, models.PropertyStatusPending); err != nil {
+			return err
+		}
+
+		if err := tx.Model(offer).Update("status", models.OfferStatusAccepted).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Scopes(openOffers).Where("property_id = ? AND id <> ?", offer.PropertyID, offer.ID).
+			Find(&rejected).Error; err != nil {
+			return err
+		}
+		if len(rejected) > 0 {
+			ids := make([]uint, len(rejected))
+			for i := range rejected {
+				ids[i] = rejected[i].ID
+			}
+			if err := tx.Model(&models.Offer{}).Where("id IN ?", ids).
+				Update("status", models.OfferStatusRejected).Error; err != nil {
+				return err
+			}
+		}
+
+		return openTransaction(tx, offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOffer(offerCounterparty(offer, userID), "Offer accepted",
+		fmt.Sprintf("Offer #%d of %.2f was accepted.", offer.ID, offer.Amount))
+	for _, other := range rejected {
+		s.notifyOffer(other.BuyerID, "Offer declined",
+			fmt.Sprintf("Offer #%d was declined because another offer was accepted.", other.ID))
+	}
+
+	return s.GetOffer(id, userID)
+}
+
+// RejectOffer rejects an open offer
+func (s *OfferService) RejectOffer(id, userID uint) (*models.OfferResponse, error) {
+	var offer *models.Offer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = lockRespondableOffer(tx, id, userID)
+		if err != nil {
+			return err
+		}
+		return tx.Model(offer).Update("status", models.OfferStatusRejected).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOffer(offerCounterparty(offer, userID), "Offer rejected",
+		fmt.Sprintf("Offer #%d of %.2f was rejected.", offer.ID, offer.Amount))
+
+	return s.GetOffer(id, userID)
+}
+
+// WithdrawOffer withdraws an open offer its proposer no longer stands by
+func (s *OfferService) WithdrawOffer(id, userID uint) (*models.OfferResponse, error) {
+	var offer models.Offer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, id).Error; err != nil {
+			return err
+		}
+		if offer.ProposedBy != userID {
+			return errors.New("unauthorized")
+		}
+		if status := offerStatus(&offer); status != models.OfferStatusSubmitted {
+			return fmt.Errorf("offer is %s and can no longer be withdrawn", status)
+		}
+		return tx.Model(&offer).Update("status", models.OfferStatusWithdrawn).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOffer(offerCounterparty(&offer, userID), "Offer withdrawn",
+		fmt.Sprintf("Offer #%d was withdrawn.", offer.ID))
+
+	return s.GetOffer(id, userID)
+}
+
+// listOffers pages through offers, newest first
+func (s *OfferService) listOffers(query *gorm.DB, req *models.OfferListRequest) (*models.PaginationResponse, error) {
+	if req.Status != nil {
+		switch *req.Status {
+		case models.OfferStatusSubmitted:
+			query = query.Scopes(openOffers)
+		case models.OfferStatusExpired:
+			query = query.Where("(status = ? OR (status = ? AND expires_at <= ?))",
+				models.OfferStatusExpired, models.OfferStatusSubmitted, time.Now())
+		default:
+			query = query.Where("status = ?", *req.Status)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var offers []models.Offer
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").
+		Order("created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&offers).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OfferResponse, len(offers))
+	for i := range offers {
+		responses[i] = *toOfferResponse(&offers[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// notifyOffer notifies a user about an offer. Delivery failures are logged
+// and do not fail the request.
+func (s *OfferService) notifyOffer(userID uint, subject, body string) {
+	notifyUser(s.db, s.notifier, userID, subject, body)
+}
+
+// lockRespondableOffer locks an open offer that userID may answer: the
+// party who did not propose it
+func lockRespondableOffer(tx *gorm.DB, id, userID uint) (*models.Offer, error) {
+	var offer models.Offer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&offer, id).Error; err != nil {
+		return nil, err
+	}
+	if offer.BuyerID != userID && offer.AgentID != userID {
+		return nil, errors.New("unauthorized")
+	}
+	if status := offerStatus(&offer); status != models.OfferStatusSubmitted {
+		return nil, fmt.Errorf("offer is %s and can no longer be answered", status)
+	}
+	if offer.ProposedBy == userID {
+		return nil, errors.New("you cannot answer your own offer")
+	}
+
+	return &offer, nil
+}
+
+// ExpireOffers marks open offers past their expiry as expired and returns
+// how many it marked
+func (s *OfferService) ExpireOffers() (int64, error) {
+	result := s.db.Model(&models.Offer{}).
+		Where("status = ? AND expires_at <= ?", models.OfferStatusSubmitted, time.Now()).
+		Update("status", models.OfferStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// RunExpiry marks expired offers every interval until ctx is cancelled
+func (s *OfferService) RunExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireOffers()
+			if err != nil {
+				log.Printf("offer expiry: failed: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("offer expiry: expired offers=%d", expired)
+			}
+		}
+	}
+}
+
+// openOffers limits a query to offers still awaiting an answer. Offers past
+// their expiry count as expired before the expiry job marks them.
+func openOffers(db *gorm.DB) *gorm.DB {
+	return db.Where("offers.status = ? AND offers.expires_at > ?", models.OfferStatusSubmitted, time.Now())
+}
+
+// offerStatus is an offer's status, reporting open offers past their
+// expiry as expired before the expiry job marks them
+func offerStatus(offer *models.Offer) models.OfferStatus {
+	if offer.Status == models.OfferStatusSubmitted && !offer.ExpiresAt.After(time.Now()) {
+		return models.OfferStatusExpired
+	}
+	return offer.Status
+}
+
+// validateOfferRequest checks an offer's terms and parses its closing date
+func validateOfferRequest(req *models.OfferRequest) (*time.Time, []models.FieldError) {
+	var fieldErrors []models.FieldError
+	for _, contingency := range req.Contingencies {
+		if !contingency.IsValid() {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   "contingencies",
+				Message: fmt.Sprintf("unknown contingency %q", contingency),
+			})
+		}
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "expires_at", Message: "must be in the future"})
+	}
+
+	closingDate, err := parseOptionalDate(req.ClosingDate)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "closing_date", Message: "must be a date in YYYY-MM-DD format"})
+	} else if closingDate != nil && closingDate.Before(req.ExpiresAt.Truncate(24*time.Hour)) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "closing_date", Message: "must not be before the offer expires"})
+	}
+
+	return closingDate, fieldErrors
+}
+
+// normalizeContingencies removes duplicate contingencies, keeping their order
+func normalizeContingencies(contingencies []models.Contingency) []models.Contingency {
+	seen := make(map[models.Contingency]bool, len(contingencies))
+	normalized := []models.Contingency{}
+	for _, contingency := range contingencies {
+		if !seen[contingency] {
+			seen[contingency] = true
+			normalized = append(normalized, contingency)
+		}
+	}
+	return normalized
+}
+
+// hasContingency reports whether an offer depends on a contingency
+func hasContingency(offer *models.Offer, contingency models.Contingency) bool {
+	for _, c := range offer.Contingencies {
+		if c == contingency {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, returning nil for ""
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(offerDateFormat, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// offerCounterparty returns the party of an offer other than userID
+func offerCounterparty(offer *models.Offer, userID uint) uint {
+	if userID == offer.AgentID {
+		return offer.BuyerID
+	}
+	return offer.AgentID
+}
+
+// notifyUser notifies a user by ID. Delivery failures are logged and do not
+// fail the request.
+func notifyUser(db *gorm.DB, notifier Notifier, userID uint, subject, body string) {
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		log.Printf("failed to load user %d for notification: %v", userID, err)
+		return
+	}
+
+	if err := notifier.Notify(Notification{UserID: user.ID, Email: user.Email, Subject: subject, Body: body}); err != nil {
+		log.Printf("failed to notify user %d: %v", userID, err)
+	}
+}
+
+// toOfferResponse converts Offer to OfferResponse
+func toOfferResponse(offer *models.Offer) *models.OfferResponse {
+	return &models.OfferResponse{
+		ID:            offer.ID,
+		PropertyID:    offer.PropertyID,
+		PropertyTitle: offer.Property.Title,
+		BuyerID:       offer.BuyerID,
+		BuyerName:     strings.TrimSpace(offer.Buyer.FirstName + " " + offer.Buyer.LastName),
+		AgentID:       offer.AgentID,
+		ParentID:      offer.ParentID,
+		ProposedBy:    offer.ProposedBy,
+		Amount:        offer.Amount,
+		EarnestMoney:  offer.EarnestMoney,
+		Contingencies: offer.Contingencies,
+		ClosingDate:   offer.ClosingDate,
+		ExpiresAt:     offer.ExpiresAt,
+		Message:       offer.Message,
+		Status:        offerStatus(offer),
+		CreatedAt:     offer.CreatedAt,
+		UpdatedAt:     offer.UpdatedAt,
+	}
+}
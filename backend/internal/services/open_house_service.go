@@ -0,0 +1,613 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/ical"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// agentCalendarHistory is how far back the agent calendar feed includes past events
+const agentCalendarHistory = 30 * 24 * time.Hour
+
+// OpenHouseService handles open house scheduling and RSVPs
+type OpenHouseService struct {
+	db            *gorm.DB
+	notifier      Notifier
+	publicBaseURL string
+}
+
+// NewOpenHouseService creates a new open house service
+func NewOpenHouseService(db *gorm.DB, notifier Notifier, publicBaseURL string) *OpenHouseService {
+	return &OpenHouseService{db: db, notifier: notifier, publicBaseURL: strings.TrimRight(publicBaseURL, "/")}
+}
+
+// CreateOpenHouse schedules an open house for a property owned by agentID
+func (s *OpenHouseService) CreateOpenHouse(propertyID, agentID uint, req *models.OpenHouseCreateRequest) (*models.OpenHouseResponse, error) {
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+	if listingHidden(&property) {
+		return nil, errors.New("listing is hidden from the public and cannot host open houses")
+	}
+
+	startsAt, endsAt, fieldErrors := parseOpenHouseTimes(req.StartTime, req.EndTime, req.TimeZone)
+	if len(fieldErrors) == 0 && !startsAt.After(time.Now()) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "start_time", Message: "must be in the future"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	openHouse := models.OpenHouse{
+		PropertyID: propertyID,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		TimeZone:   req.TimeZone,
+		Capacity:   req.Capacity,
+		Notes:      req.Notes,
+	}
+	if err := s.db.Create(&openHouse).Error; err != nil {
+		return nil, err
+	}
+
+	return toOpenHouseResponse(&openHouse), nil
+}
+
+// UpdateOpenHouse reschedules or edits an open house. Raising the capacity
+// promotes waitlisted RSVPs that now fit.
+func (s *OpenHouseService) UpdateOpenHouse(id, agentID uint, req *models.OpenHouseUpdateRequest) (*models.OpenHouseResponse, error) {
+	var openHouse models.OpenHouse
+	var promoted []models.OpenHouseRSVP
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockOwnedOpenHouse(tx, id, agentID, &openHouse); err != nil {
+			return err
+		}
+
+		loc, err := loadEventLocation(openHouse.TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		startTime := openHouse.StartsAt.In(loc).Format(models.OpenHouseLocalTimeFormat)
+		endTime := openHouse.EndsAt.In(loc).Format(models.OpenHouseLocalTimeFormat)
+		timeZone := openHouse.TimeZone
+		if req.StartTime != nil {
+			startTime = *req.StartTime
+		}
+		if req.EndTime != nil {
+			endTime = *req.EndTime
+		}
+		if req.TimeZone != nil {
+			timeZone = *req.TimeZone
+		}
+
+		startsAt, endsAt, fieldErrors := parseOpenHouseTimes(startTime, endTime, timeZone)
+		if len(fieldErrors) > 0 {
+			return &ValidationError{Errors: fieldErrors}
+		}
+
+		// Calendar clients only apply updates with a higher sequence number
+		if !startsAt.Equal(openHouse.StartsAt) || !endsAt.Equal(openHouse.EndsAt) {
+			openHouse.Sequence++
+		}
+		openHouse.StartsAt = startsAt
+		openHouse.EndsAt = endsAt
+		openHouse.TimeZone = timeZone
+		if req.Capacity != nil {
+			openHouse.Capacity = *req.Capacity
+		}
+		if req.Notes != nil {
+			openHouse.Notes = *req.Notes
+		}
+
+		if err := tx.Omit(clause.Associations).Save(&openHouse).Error; err != nil {
+			return err
+		}
+
+		promoted, err = s.promoteWaitlist(tx, &openHouse)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyPromoted(&openHouse, promoted)
+	return toOpenHouseResponse(&openHouse), nil
+}
+
+// CancelOpenHouse cancels an open house. RSVPs are kept so attendees can see
+// the cancellation.
+func (s *OpenHouseService) CancelOpenHouse(id, agentID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var openHouse models.OpenHouse
+		if err := s.lockOwnedOpenHouse(tx, id, agentID, &openHouse); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&openHouse).Update("sequence", openHouse.Sequence+1).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&openHouse).Error
+	})
+}
+
+// GetOpenHouse gets an open house of a live property viewer may see
+func (s *OpenHouseService) GetOpenHouse(id uint, viewer ListingViewer) (*models.OpenHouseResponse, error) {
+	var openHouse models.OpenHouse
+	if err := s.db.InnerJoins("Property").Scopes(visibleListings(viewer, `"Property"`)).First(&openHouse, id).Error; err != nil {
+		return nil, err
+	}
+
+	return toOpenHouseResponse(&openHouse), nil
+}
+
+// GetPropertyOpenHouses lists the upcoming open houses of a property viewer
+// may see
+func (s *OpenHouseService) GetPropertyOpenHouses(propertyID uint, viewer ListingViewer) ([]models.OpenHouseResponse, error) {
+	var property models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
+	var openHouses []models.OpenHouse
+	if err := upcomingOpenHouses(s.db).Where("property_id = ?", propertyID).Find(&openHouses).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OpenHouseResponse, len(openHouses))
+	for i := range openHouses {
+		responses[i] = *toOpenHouseResponse(&openHouses[i])
+	}
+	return responses, nil
+}
+
+// RSVP reserves a place at an open house for userID. The RSVP is confirmed
+// if the party fits in the remaining capacity, otherwise it is waitlisted.
+func (s *OpenHouseService) RSVP(openHouseID, userID uint, req *models.OpenHouseRSVPRequest) (*models.OpenHouseRSVPResponse, error) {
+	partySize := req.PartySize
+	if partySize == 0 {
+		partySize = 1
+	}
+
+	var rsvp models.OpenHouseRSVP
+	var openHouse models.OpenHouse
+	var promoted []models.OpenHouseRSVP
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockOpenHouse(tx, openHouseID, &openHouse); err != nil {
+			return err
+		}
+		// Listings in the trash keep their open houses for restore but take
+		// no new RSVPs, and neither do listings hidden from the attendee
+		if err := tx.Scopes(visibleListings(ListingViewer{UserID: userID}, "properties")).
+			First(&models.Property{}, openHouse.PropertyID).Error; err != nil {
+			return err
+		}
+		if !openHouse.EndsAt.After(time.Now()) {
+			return errors.New("open house has already ended")
+		}
+		if openHouse.Capacity > 0 && partySize > openHouse.Capacity {
+			return fmt.Errorf("party size exceeds open house capacity of %d", openHouse.Capacity)
+		}
+
+		err := tx.Where("open_house_id = ? AND user_id = ?", openHouseID, userID).First(&rsvp).Error
+		switch {
+		case err == nil && rsvp.Status != models.RSVPStatusCancelled:
+			return errors.New("you have already RSVPed to this open house")
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		// Join the back of the waitlist; promotion confirms it right away
+		// when it fits
+		rsvp.OpenHouseID = openHouseID
+		rsvp.UserID = userID
+		rsvp.PartySize = partySize
+		rsvp.Status = models.RSVPStatusWaitlisted
+		rsvp.CreatedAt = time.Now()
+		if err := tx.Save(&rsvp).Error; err != nil {
+			return err
+		}
+
+		if promoted, err = s.promoteWaitlist(tx, &openHouse); err != nil {
+			return err
+		}
+		return tx.First(&rsvp, rsvp.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The caller learns about their own RSVP from the response
+	others := promoted[:0]
+	for _, promotedRSVP := range promoted {
+		if promotedRSVP.ID != rsvp.ID {
+			others = append(others, promotedRSVP)
+		}
+	}
+	s.notifyPromoted(&openHouse, others)
+
+	return s.toRSVPResponse(&rsvp, false)
+}
+
+// CancelRSVP cancels userID's RSVP and promotes waitlisted RSVPs into any
+// capacity it frees
+func (s *OpenHouseService) CancelRSVP(openHouseID, userID uint) error {
+	var openHouse models.OpenHouse
+	var promoted []models.OpenHouseRSVP
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockOpenHouse(tx, openHouseID, &openHouse); err != nil {
+			return err
+		}
+
+		var rsvp models.OpenHouseRSVP
+		if err := tx.Where("open_house_id = ? AND user_id = ? AND status <> ?", openHouseID, userID, models.RSVPStatusCancelled).
+			First(&rsvp).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&rsvp).Update("status", models.RSVPStatusCancelled).Error; err != nil {
+			return err
+		}
+
+		var err error
+		promoted, err = s.promoteWaitlist(tx, &openHouse)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyPromoted(&openHouse, promoted)
+	return nil
+}
+
+// GetMyRSVP gets userID's active RSVP for an open house
+func (s *OpenHouseService) GetMyRSVP(openHouseID, userID uint) (*models.OpenHouseRSVPResponse, error) {
+	var rsvp models.OpenHouseRSVP
+	if err := s.db.Where("open_house_id = ? AND user_id = ? AND status <> ?", openHouseID, userID, models.RSVPStatusCancelled).
+		First(&rsvp).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toRSVPResponse(&rsvp, false)
+}
+
+// GetRSVPs lists the confirmed and waitlisted RSVPs of an open house for its agent
+func (s *OpenHouseService) GetRSVPs(openHouseID, agentID uint) ([]models.OpenHouseRSVPResponse, error) {
+	var openHouse models.OpenHouse
+	if err := s.db.InnerJoins("Property").First(&openHouse, openHouseID).Error; err != nil {
+		return nil, err
+	}
+	if openHouse.Property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	var rsvps []models.OpenHouseRSVP
+	if err := s.db.Preload("User").
+		Where("open_house_id = ? AND status <> ?", openHouseID, models.RSVPStatusCancelled).
+		Order("created_at, id").
+		Find(&rsvps).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.OpenHouseRSVPResponse, 0, len(rsvps))
+	position := 0
+	for i := range rsvps {
+		response := s.rsvpResponse(&rsvps[i], true)
+		if rsvps[i].Status == models.RSVPStatusWaitlisted {
+			position++
+			response.WaitlistPosition = position
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// EventCalendar builds an iCalendar document for a single open house of a
+// property viewer may see
+func (s *OpenHouseService) EventCalendar(id uint, viewer ListingViewer) (*ical.Calendar, error) {
+	var openHouse models.OpenHouse
+	if err := s.db.InnerJoins("Property").Scopes(visibleListings(viewer, `"Property"`)).First(&openHouse, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &ical.Calendar{
+		ProdID:   "-//Galactavista//Open Houses//EN",
+		TimeZone: openHouse.TimeZone,
+		Events:   []ical.Event{s.calendarEvent(&openHouse)},
+	}, nil
+}
+
+// AgentCalendar builds an iCalendar feed of an agent's open houses at the
+// listings viewer may see, including recently cancelled ones so subscribed
+// calendars drop them
+func (s *OpenHouseService) AgentCalendar(agentID uint, viewer ListingViewer) (*ical.Calendar, error) {
+	var agent models.User
+	if err := s.db.Where("role = ?", models.RoleAgent).First(&agent, agentID).Error; err != nil {
+		return nil, err
+	}
+
+	var openHouses []models.OpenHouse
+	if err := s.db.Unscoped().
+		InnerJoins("Property").
+		Where(`"Property".agent_id = ? AND "Property".deleted_at IS NULL`, agentID).
+		Scopes(visibleListings(viewer, `"Property"`)).
+		Where("open_houses.ends_at > ?", time.Now().Add(-agentCalendarHistory)).
+		Order("open_houses.starts_at").
+		Find(&openHouses).Error; err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID: "-//Galactavista//Open Houses//EN",
+		Name:   fmt.Sprintf("%s %s open houses", agent.FirstName, agent.LastName),
+	}
+	for i := range openHouses {
+		calendar.Events = append(calendar.Events, s.calendarEvent(&openHouses[i]))
+	}
+	return calendar, nil
+}
+
+// lockOpenHouse loads a live open house and locks it for the rest of the
+// transaction so capacity checks are not raced
+func (s *OpenHouseService) lockOpenHouse(tx *gorm.DB, id uint, openHouse *models.OpenHouse) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(openHouse, id).Error
+}
+
+// lockOwnedOpenHouse locks an open house and checks that agentID lists its property
+func (s *OpenHouseService) lockOwnedOpenHouse(tx *gorm.DB, id, agentID uint, openHouse *models.OpenHouse) error {
+	if err := s.lockOpenHouse(tx, id, openHouse); err != nil {
+		return err
+	}
+
+	var property models.Property
+	if err := tx.First(&property, openHouse.PropertyID).Error; err != nil {
+		return err
+	}
+	if property.AgentID != agentID {
+		return errors.New("unauthorized")
+	}
+	return nil
+}
+
+// promoteWaitlist confirms waitlisted RSVPs, oldest first, while they fit in
+// the remaining capacity, then refreshes the open house's attendee counts.
+// A party too large for the remaining space does not block smaller ones behind it.
+// It returns the RSVPs it confirmed.
+func (s *OpenHouseService) promoteWaitlist(tx *gorm.DB, openHouse *models.OpenHouse) ([]models.OpenHouseRSVP, error) {
+	var confirmed int
+	if err := tx.Model(&models.OpenHouseRSVP{}).
+		Where("open_house_id = ? AND status = ?", openHouse.ID, models.RSVPStatusConfirmed).
+		Select("COALESCE(SUM(party_size), 0)").
+		Scan(&confirmed).Error; err != nil {
+		return nil, err
+	}
+
+	var waitlisted []models.OpenHouseRSVP
+	if err := tx.Where("open_house_id = ? AND status = ?", openHouse.ID, models.RSVPStatusWaitlisted).
+		Order("created_at, id").
+		Find(&waitlisted).Error; err != nil {
+		return nil, err
+	}
+
+	var promoted []models.OpenHouseRSVP
+	waiting := 0
+	for _, rsvp := range waitlisted {
+		if openHouse.Capacity > 0 && confirmed+rsvp.PartySize > openHouse.Capacity {
+			waiting += rsvp.PartySize
+			continue
+		}
+
+		if err := tx.Model(&rsvp).Update("status", models.RSVPStatusConfirmed).Error; err != nil {
+			return nil, err
+		}
+		confirmed += rsvp.PartySize
+		promoted = append(promoted, rsvp)
+	}
+
+	openHouse.ConfirmedCount = confirmed
+	openHouse.WaitlistCount = waiting
+	if err := tx.Model(openHouse).Updates(map[string]interface{}{
+		"confirmed_count": confirmed,
+		"waitlist_count":  waiting,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// notifyPromoted tells attendees their waitlisted RSVP has been confirmed
+func (s *OpenHouseService) notifyPromoted(openHouse *models.OpenHouse, promoted []models.OpenHouseRSVP) {
+	if len(promoted) == 0 {
+		return
+	}
+
+	var property models.Property
+	if err := s.db.Unscoped().First(&property, openHouse.PropertyID).Error; err != nil {
+		log.Printf("open house %d: failed to load property for notification: %v", openHouse.ID, err)
+		return
+	}
+
+	response := toOpenHouseResponse(openHouse)
+	body := fmt.Sprintf("A place opened up at the open house for %s at %s on %s. Your RSVP is now confirmed.",
+		property.Title, property.Address, response.LocalStart)
+	for _, rsvp := range promoted {
+		notifyUser(s.db, s.notifier, rsvp.UserID, "Your open house RSVP is confirmed", body)
+	}
+}
+
+// calendarEvent converts an open house to an iCalendar event
+func (s *OpenHouseService) calendarEvent(openHouse *models.OpenHouse) ical.Event {
+	property := openHouse.Property
+
+	status := ical.StatusConfirmed
+	if openHouse.DeletedAt.Valid {
+		status = ical.StatusCancelled
+	}
+
+	host := "galactavista"
+	if base, err := url.Parse(s.publicBaseURL); err == nil && base.Hostname() != "" {
+		host = base.Hostname()
+	}
+
+	return ical.Event{
+		UID:          fmt.Sprintf("open-house-%d@%s", openHouse.ID, host),
+		Sequence:     openHouse.Sequence,
+		Start:        openHouse.StartsAt,
+		End:          openHouse.EndsAt,
+		Summary:      "Open House: " + property.Title,
+		Description:  openHouse.Notes,
+		Location:     fmt.Sprintf("%s, %s, %s %s", property.Address, property.City, property.State, property.ZipCode),
+		URL:          fmt.Sprintf("%s/properties/%d", s.publicBaseURL, property.ID),
+		Status:       status,
+		Created:      openHouse.CreatedAt,
+		LastModified: openHouse.UpdatedAt,
+	}
+}
+
+// toRSVPResponse converts an OpenHouseRSVP to OpenHouseRSVPResponse,
+// computing its waitlist position
+func (s *OpenHouseService) toRSVPResponse(rsvp *models.OpenHouseRSVP, includeUser bool) (*models.OpenHouseRSVPResponse, error) {
+	response := s.rsvpResponse(rsvp, includeUser)
+
+	if rsvp.Status == models.RSVPStatusWaitlisted {
+		var ahead int64
+		if err := s.db.Model(&models.OpenHouseRSVP{}).
+			Where("open_house_id = ? AND status = ?", rsvp.OpenHouseID, models.RSVPStatusWaitlisted).
+			Where("created_at < ? OR (created_at = ? AND id < ?)", rsvp.CreatedAt, rsvp.CreatedAt, rsvp.ID).
+			Count(&ahead).Error; err != nil {
+			return nil, err
+		}
+		response.WaitlistPosition = int(ahead) + 1
+	}
+
+	return response, nil
+}
+
+// rsvpResponse converts an OpenHouseRSVP without a waitlist position
+func (s *OpenHouseService) rsvpResponse(rsvp *models.OpenHouseRSVP, includeUser bool) *models.OpenHouseRSVPResponse {
+	response := &models.OpenHouseRSVPResponse{
+		ID:          rsvp.ID,
+		OpenHouseID: rsvp.OpenHouseID,
+		PartySize:   rsvp.PartySize,
+		Status:      rsvp.Status,
+		CreatedAt:   rsvp.CreatedAt,
+		UpdatedAt:   rsvp.UpdatedAt,
+	}
+
+	if includeUser {
+		response.User = &models.UserResponse{
+			ID:        rsvp.User.ID,
+			Email:     rsvp.User.Email,
+			FirstName: rsvp.User.FirstName,
+			LastName:  rsvp.User.LastName,
+			Role:      rsvp.User.Role,
+			Phone:     rsvp.User.Phone,
+			Avatar:    rsvp.User.Avatar,
+			IsActive:  rsvp.User.IsActive,
+			Version:   rsvp.User.Version,
+			CreatedAt: rsvp.User.CreatedAt,
+			UpdatedAt: rsvp.User.UpdatedAt,
+		}
+	}
+
+	return response
+}
+
+// upcomingOpenHouses scopes an open house query to events that have not
+// ended, soonest first
+func upcomingOpenHouses(db *gorm.DB) *gorm.DB {
+	return db.Where("ends_at > ?", time.Now()).Order("starts_at")
+}
+
+// toOpenHouseResponse converts OpenHouse to OpenHouseResponse
+func toOpenHouseResponse(openHouse *models.OpenHouse) *models.OpenHouseResponse {
+	loc, err := loadEventLocation(openHouse.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	response := &models.OpenHouseResponse{
+		ID:             openHouse.ID,
+		PropertyID:     openHouse.PropertyID,
+		StartsAt:       openHouse.StartsAt.UTC(),
+		EndsAt:         openHouse.EndsAt.UTC(),
+		TimeZone:       openHouse.TimeZone,
+		LocalStart:     openHouse.StartsAt.In(loc).Format(time.RFC3339),
+		LocalEnd:       openHouse.EndsAt.In(loc).Format(time.RFC3339),
+		Capacity:       openHouse.Capacity,
+		Notes:          openHouse.Notes,
+		ConfirmedCount: openHouse.ConfirmedCount,
+		WaitlistCount:  openHouse.WaitlistCount,
+		CreatedAt:      openHouse.CreatedAt,
+		UpdatedAt:      openHouse.UpdatedAt,
+	}
+
+	if openHouse.Capacity > 0 {
+		remaining := openHouse.Capacity - openHouse.ConfirmedCount
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.SpotsRemaining = &remaining
+	}
+
+	return response
+}
+
+// parseOpenHouseTimes parses local wall-clock start and end times in the
+// named IANA time zone and checks that the event ends after it starts
+func parseOpenHouseTimes(startTime, endTime, timeZone string) (time.Time, time.Time, []models.FieldError) {
+	var fieldErrors []models.FieldError
+
+	loc, err := loadEventLocation(timeZone)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "time_zone", Message: "must be an IANA time zone such as America/New_York"})
+		return time.Time{}, time.Time{}, fieldErrors
+	}
+
+	startsAt, err := time.ParseInLocation(models.OpenHouseLocalTimeFormat, startTime, loc)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "start_time", Message: "must be a local time in YYYY-MM-DDTHH:MM format"})
+	}
+	endsAt, err := time.ParseInLocation(models.OpenHouseLocalTimeFormat, endTime, loc)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "end_time", Message: "must be a local time in YYYY-MM-DDTHH:MM format"})
+	}
+	if len(fieldErrors) == 0 && !endsAt.After(startsAt) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "end_time", Message: "must be after start_time"})
+	}
+
+	return startsAt.UTC(), endsAt.UTC(), fieldErrors
+}
+
+// loadEventLocation loads an IANA time zone, rejecting the server-dependent "Local"
+func loadEventLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("invalid time zone")
+	}
+	return time.LoadLocation(name)
+}
+
+// upcomingWeekend returns the local dates of the weekend containing now,
+// or the next one on a weekday. On a Sunday only that Sunday remains.
+func upcomingWeekend(now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sunday := today.AddDate(0, 0, (7-int(today.Weekday()))%7)
+
+	saturday := sunday.AddDate(0, 0, -1)
+	if saturday.Before(today) {
+		saturday = today
+	}
+	return saturday, sunday
+}
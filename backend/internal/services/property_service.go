@@ -2,73 +2,179 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"galactavista/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PropertyService handles property operations
 type PropertyService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	listingRules []ListingRule
 }
 
-// NewPropertyService creates a new property service
-func NewPropertyService(db *gorm.DB) *PropertyService {
-	return &PropertyService{db: db}
+// NewPropertyService creates a new property service. New and edited
+// listings are checked against the listing rules.
+func NewPropertyService(db *gorm.DB, listingRules ...ListingRule) *PropertyService {
+	return &PropertyService{db: db, listingRules: listingRules}
 }
 
 // CreateProperty creates a new property
 func (s *PropertyService) CreateProperty(req *models.PropertyCreateRequest, agentID uint) (*models.PropertyResponse, error) {
+	return s.createProperty(req, agentID, nil)
+}
+
+// createProperty creates a new property, as a unit of development when one
+// is given
+func (s *PropertyService) createProperty(req *models.PropertyCreateRequest, agentID uint, development *models.Development) (*models.PropertyResponse, error) {
+	if fieldErrors := ValidatePropertyCreateRequest(req); len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	listingType := req.ListingType
+	if listingType == "" {
+		listingType = models.ListingTypeSale
+	}
+	availableFrom, err := parseOptionalDate(req.AvailableFrom)
+	if err != nil {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "available_from", Message: "must be a date in YYYY-MM-DD format"}}}
+	}
+	attributes, err := resolveAttributeValues(s.db, req.PropertyType, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	var quality *models.ListingQuality
+
 	property := models.Property{
-		Title:        req.Title,
-		Description:  req.Description,
-		Price:        req.Price,
-		Address:      req.Address,
-		City:         req.City,
-		State:        req.State,
-		ZipCode:      req.ZipCode,
-		Country:      req.Country,
-		PropertyType: req.PropertyType,
-		Status:       models.PropertyStatusAvailable,
-		Bedrooms:     req.Bedrooms,
-		Bathrooms:    req.Bathrooms,
-		SquareFeet:   req.SquareFeet,
-		YearBuilt:    req.YearBuilt,
-		LotSize:      req.LotSize,
-		Features:     req.Features,
-		Images:       req.Images,
-		AgentID:      agentID,
-	}
-
-	if err := s.db.Create(&property).Error; err != nil {
+		Title:           req.Title,
+		Description:     req.Description,
+		Price:           req.Price,
+		MonthlyRent:     req.MonthlyRent,
+		SecurityDeposit: req.SecurityDeposit,
+		LeaseTermMonths: req.LeaseTermMonths,
+		PetPolicy:       req.PetPolicy,
+		AvailableFrom:   availableFrom,
+		Address:         req.Address,
+		City:            req.City,
+		State:           req.State,
+		ZipCode:         req.ZipCode,
+		Country:         req.Country,
+		PropertyType:    req.PropertyType,
+		ListingType:     listingType,
+		Status:          models.PropertyStatusAvailable,
+		Bedrooms:        req.Bedrooms,
+		Bathrooms:       req.Bathrooms,
+		SquareFeet:      req.SquareFeet,
+		YearBuilt:       req.YearBuilt,
+		LotSize:         req.LotSize,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		GeocodeAccuracy: req.GeocodeAccuracy,
+		Features:        req.Features,
+		Images:          req.Images,
+		UnitNumber:      req.UnitNumber,
+		AgentID:         agentID,
+
+		ModerationStatus: models.ModerationStatusApproved,
+	}
+	if req.Commercial != nil {
+		property.Commercial = *req.Commercial
+	}
+	if req.Land != nil {
+		property.Land = *req.Land
+	}
+	if development != nil {
+		property.DevelopmentID = &development.ID
+	}
+	normalizePropertyAddress(&property)
+	lintListingDescription(&property, true)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&property).Error; err != nil {
+			return err
+		}
+		if _, err := flagDuplicates(tx, &property); err != nil {
+			return err
+		}
+		if err := moderateListing(tx, s.listingRules, &property, nil); err != nil {
+			return err
+		}
+		if err := saveAttributeValues(tx, property.ID, attributes); err != nil {
+			return err
+		}
+		var err error
+		if quality, err = refreshQualityScore(tx, property.ID); err != nil {
+			return err
+		}
+		return recordRevision(tx, &property, models.RevisionActionCreated, &agentID, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
+	property.Development = development
+	property.Attributes = attributes
+	property.QualityScore = quality.Score
 
-	return s.getPropertyResponse(&property), nil
+	response := s.getPropertyResponse(&property)
+	response.Quality = quality
+	return response, nil
 }
 
 // GetProperty gets a property by ID
 func (s *PropertyService) GetProperty(id uint) (*models.PropertyResponse, error) {
 	var property models.Property
-	if err := s.db.Preload("Agent").First(&property, id).Error; err != nil {
+	if err := s.db.Preload("Agent").Preload("Development").Preload("Attributes.Attribute").Preload("OpenHouses", upcomingOpenHouses).First(&property, id).Error; err != nil {
 		return nil, err
 	}
 
 	return s.getPropertyResponse(&property), nil
 }
 
-// UpdateProperty updates a property
-func (s *PropertyService) UpdateProperty(id uint, req *models.PropertyUpdateRequest, agentID uint) (*models.PropertyResponse, error) {
+// GetVisibleProperty gets a property by ID if viewer may see it. Hidden
+// listings are reported as not found.
+func (s *PropertyService) GetVisibleProperty(id uint, viewer ListingViewer) (*models.PropertyResponse, error) {
+	var property models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).
+		Preload("Agent").Preload("Development").Preload("Attributes.Attribute").Preload("OpenHouses", upcomingOpenHouses).
+		First(&property, id).Error; err != nil {
+		return nil, err
+	}
+
+	return s.getPropertyResponse(&property), nil
+}
+
+// UpdateProperty updates a property if it is still at expectedVersion
+func (s *PropertyService) UpdateProperty(id uint, req *models.PropertyUpdateRequest, agentID uint, expectedVersion int) (*models.PropertyResponse, error) {
 	var property models.Property
 	if err := s.db.First(&property, id).Error; err != nil {
 		return nil, err
 	}
 
-	// Check if user is the agent or admin
+	// Only the listing's agent may change it
 	if property.AgentID != agentID {
 		return nil, errors.New("unauthorized")
 	}
 
+	if property.Version != expectedVersion {
+		return nil, newVersionConflict(expectedVersion, property.Version, req, &property)
+	}
+	if property.DevelopmentID != nil {
+		if fieldErrors := inheritedFieldErrors(req); len(fieldErrors) > 0 {
+			return nil, &ValidationError{Errors: fieldErrors}
+		}
+	}
+	if req.Status != nil && !property.Status.CanTransitionTo(*req.Status) {
+		return nil, &ValidationError{Errors: []models.FieldError{{
+			Field:   "status",
+			Message: fmt.Sprintf("cannot change status from %s to %s", property.Status, *req.Status),
+		}}}
+	}
+	original := property
+
 	// Update fields if provided
 	if req.Title != nil {
 		property.Title = *req.Title
@@ -97,6 +203,28 @@ func (s *PropertyService) UpdateProperty(id uint, req *models.PropertyUpdateRequ
 	if req.PropertyType != nil {
 		property.PropertyType = *req.PropertyType
 	}
+	if req.ListingType != nil {
+		property.ListingType = *req.ListingType
+	}
+	if req.MonthlyRent != nil {
+		property.MonthlyRent = req.MonthlyRent
+	}
+	if req.SecurityDeposit != nil {
+		property.SecurityDeposit = req.SecurityDeposit
+	}
+	if req.LeaseTermMonths != nil {
+		property.LeaseTermMonths = req.LeaseTermMonths
+	}
+	if req.PetPolicy != nil {
+		property.PetPolicy = *req.PetPolicy
+	}
+	if req.AvailableFrom != nil {
+		availableFrom, err := parseOptionalDate(*req.AvailableFrom)
+		if err != nil {
+			return nil, &ValidationError{Errors: []models.FieldError{{Field: "available_from", Message: "must be a date in YYYY-MM-DD format"}}}
+		}
+		property.AvailableFrom = availableFrom
+	}
 	if req.Status != nil {
 		property.Status = *req.Status
 	}
@@ -115,6 +243,21 @@ func (s *PropertyService) UpdateProperty(id uint, req *models.PropertyUpdateRequ
 	if req.LotSize != nil {
 		property.LotSize = *req.LotSize
 	}
+	if req.Latitude != nil {
+		property.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		property.Longitude = req.Longitude
+	}
+	if req.GeocodeAccuracy != nil {
+		property.GeocodeAccuracy = *req.GeocodeAccuracy
+	} else if req.Latitude != nil || req.Longitude != nil {
+		// Moved coordinates no longer have a known accuracy
+		property.GeocodeAccuracy = ""
+	}
+	if req.SoldPrice != nil {
+		property.SoldPrice = req.SoldPrice
+	}
 	if req.Features != nil {
 		property.Features = req.Features
 	}
@@ -124,27 +267,206 @@ func (s *PropertyService) UpdateProperty(id uint, req *models.PropertyUpdateRequ
 	if req.VRModelURL != nil {
 		property.VRModelURL = *req.VRModelURL
 	}
-
-	if err := s.db.Save(&property).Error; err != nil {
+	if req.UnitNumber != nil {
+		property.UnitNumber = *req.UnitNumber
+	}
+	if req.Commercial != nil {
+		property.Commercial = *req.Commercial
+	}
+	if req.Land != nil {
+		property.Land = *req.Land
+	}
+	// A type change drops the previous type's field set unless replaced
+	if property.PropertyType != models.PropertyTypeCommercial && req.Commercial == nil {
+		property.Commercial = models.CommercialDetails{}
+	}
+	if property.PropertyType != models.PropertyTypeLand && req.Land == nil {
+		property.Land = models.LandDetails{}
+	}
+	normalizePropertyAddress(&property)
+	if req.Description != nil {
+		lintListingDescription(&property, property.Description != original.Description)
+	}
+	fieldErrors := validateListingTerms(&property)
+	fieldErrors = append(fieldErrors, validateGeocodeAccuracy(&property)...)
+	if req.PropertyType != nil || req.Commercial != nil || req.Land != nil ||
+		req.Bedrooms != nil || req.Bathrooms != nil || req.SquareFeet != nil {
+		fieldErrors = append(fieldErrors, validatePropertyTypeFields(&property)...)
+	}
+	if !property.ListingType.AllowsStatus(property.Status) {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   "status",
+			Message: fmt.Sprintf("%s listings cannot be %s", property.ListingType, property.Status),
+		})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+	recordSaleStatus(&property, original.Status, time.Now())
+
+	// Attribute values are replaced when given, and otherwise rechecked
+	// against a changed property type
+	attributeValues := req.Attributes
+	if attributeValues == nil {
+		if err := s.db.Preload("Attribute").Where("property_id = ?", id).Find(&property.Attributes).Error; err != nil {
+			return nil, err
+		}
+		if property.PropertyType != original.PropertyType {
+			attributeValues = make(map[string]interface{}, len(property.Attributes))
+			for _, value := range property.Attributes {
+				attributeValues[value.Attribute.Key] = value.Value()
+			}
+		}
+	}
+	var attributes []models.PropertyAttribute
+	if attributeValues != nil {
+		var err error
+		if attributes, err = resolveAttributeValues(s.db, property.PropertyType, attributeValues); err != nil {
+			return nil, err
+		}
+	}
+
+	var quality *models.ListingQuality
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, &original); err != nil {
+			return err
+		}
+
+		// Only write if nobody else updated the property since it was loaded
+		property.Version = expectedVersion + 1
+		result := tx.Model(&property).
+			Where("version = ?", expectedVersion).
+			Select("*").
+			Omit("created_at", "moderation_status", clause.Associations).
+			Updates(&property)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return s.currentVersionConflict(id, expectedVersion, req)
+		}
+		if req.Attributes != nil {
+			if err := tx.Where("property_id = ?", id).Delete(&models.PropertyAttribute{}).Error; err != nil {
+				return err
+			}
+			if err := saveAttributeValues(tx, id, attributes); err != nil {
+				return err
+			}
+		}
+		if _, err := flagDuplicates(tx, &property); err != nil {
+			return err
+		}
+		if err := moderateListing(tx, s.listingRules, &property, &original); err != nil {
+			return err
+		}
+		var err error
+		if quality, err = refreshQualityScore(tx, id); err != nil {
+			return err
+		}
+
+		return recordRevision(tx, &property, models.RevisionActionUpdated, &agentID, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if req.Attributes != nil {
+		property.Attributes = attributes
+	}
+	property.QualityScore = quality.Score
 
-	return s.getPropertyResponse(&property), nil
+	response := s.getPropertyResponse(&property)
+	response.Quality = quality
+	return response, nil
 }
 
-// DeleteProperty deletes a property
-func (s *PropertyService) DeleteProperty(id uint, agentID uint) error {
+// DeleteProperty moves a property and its media and tours to the trash if it
+// is still at expectedVersion
+func (s *PropertyService) DeleteProperty(id uint, agentID uint, expectedVersion int) error {
 	var property models.Property
 	if err := s.db.First(&property, id).Error; err != nil {
 		return err
 	}
 
-	// Check if user is the agent or admin
+	// Only the listing's agent may change it
 	if property.AgentID != agentID {
 		return errors.New("unauthorized")
 	}
 
-	return s.db.Delete(&property).Error
+	if property.Version != expectedVersion {
+		return newVersionConflict(expectedVersion, property.Version, &models.PropertyUpdateRequest{}, &property)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&property).
+			Where("version = ?", expectedVersion).
+			Update("version", expectedVersion+1)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return s.currentVersionConflict(id, expectedVersion, &models.PropertyUpdateRequest{})
+		}
+
+		return softDeleteProperty(tx, &property)
+	})
+}
+
+// changePropertyStatus moves a live property to another status through the
+// status workflow, locking it and bumping its version so cached copies and
+// pending edits see the change
+func changePropertyStatus(tx *gorm.DB, propertyID uint, to models.PropertyStatus) error {
+	var property models.Property
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&property, propertyID).Error; err != nil {
+		return err
+	}
+	if property.Status == to {
+		return nil
+	}
+	if !property.Status.CanTransitionTo(to) {
+		return fmt.Errorf("listing cannot change from %s to %s", property.Status, to)
+	}
+	if !property.ListingType.AllowsStatus(to) {
+		return fmt.Errorf("%s listings cannot be %s", property.ListingType, to)
+	}
+
+	from := property.Status
+	property.Status = to
+	recordSaleStatus(&property, from, time.Now())
+
+	return tx.Model(&property).Updates(map[string]interface{}{
+		"status":     to,
+		"sold_at":    property.SoldAt,
+		"sold_price": property.SoldPrice,
+		"version":    property.Version + 1,
+	}).Error
+}
+
+// recordSaleStatus keeps a property's sale fields in step with a status
+// change from the given status: selling stamps the sale date and defaults
+// the sale price to the list price, relisting clears both
+func recordSaleStatus(property *models.Property, from models.PropertyStatus, now time.Time) {
+	switch {
+	case property.Status == models.PropertyStatusSold && from != models.PropertyStatusSold:
+		property.SoldAt = &now
+		if property.SoldPrice == nil {
+			price := property.Price
+			property.SoldPrice = &price
+		}
+	case property.Status != models.PropertyStatusSold && from == models.PropertyStatusSold:
+		property.SoldAt = nil
+		property.SoldPrice = nil
+	}
+}
+
+// currentVersionConflict reloads a property that lost a concurrent write and
+// describes the conflict against its current state
+func (s *PropertyService) currentVersionConflict(id uint, expectedVersion int, req *models.PropertyUpdateRequest) error {
+	var current models.Property
+	if err := s.db.First(&current, id).Error; err != nil {
+		return err
+	}
+
+	return newVersionConflict(expectedVersion, current.Version, req, &current)
 }
 
 // SearchProperties searches properties with filters
@@ -152,7 +474,114 @@ func (s *PropertyService) SearchProperties(req *models.PropertySearchRequest) (*
 	var properties []models.Property
 	var total int64
 
-	query := s.db.Preload("Agent")
+	query := s.applySearchFilters(s.db.Preload("Agent").Preload("Development").Preload("Attributes.Attribute").Preload("OpenHouses", upcomingOpenHouses), req)
+	if req.RollupDevelopments {
+		query = query.Where("development_id IS NULL OR id IN (?)", s.developmentRepresentatives(req))
+	}
+
+	// Count total
+	if err := query.Model(&models.Property{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order(searchOrder(req.Sort)).Offset(offset).Limit(req.PageSize).Find(&properties).Error; err != nil {
+		return nil, err
+	}
+
+	// Convert to responses
+	var responses []models.PropertyResponse
+	for _, property := range properties {
+		responses = append(responses, *s.getPropertyResponse(&property))
+	}
+	if req.RollupDevelopments {
+		if err := s.attachRollups(responses, req); err != nil {
+			return nil, err
+		}
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// searchOrder returns the ORDER BY clause for a search sort option. Rentals
+// sort by monthly rent.
+func searchOrder(sort string) string {
+	switch sort {
+	case "newest":
+		return "created_at DESC, id DESC"
+	case "price_asc":
+		return "COALESCE(monthly_rent, price), id"
+	case "price_desc":
+		return "COALESCE(monthly_rent, price) DESC, id DESC"
+	}
+	return "quality_score DESC, created_at DESC, id DESC"
+}
+
+// developmentRepresentatives selects, for each development with matching
+// units, the ID of its lowest priced match
+func (s *PropertyService) developmentRepresentatives(req *models.PropertySearchRequest) *gorm.DB {
+	return s.applySearchFilters(s.db.Model(&models.Property{}), req).
+		Select("DISTINCT ON (development_id) id").
+		Where("development_id IS NOT NULL").
+		Order("development_id, COALESCE(monthly_rent, price), id")
+}
+
+// attachRollups summarises the matching units behind each rolled up
+// development result
+func (s *PropertyService) attachRollups(responses []models.PropertyResponse, req *models.PropertySearchRequest) error {
+	var developmentIDs []uint
+	for _, response := range responses {
+		if response.DevelopmentID != nil {
+			developmentIDs = append(developmentIDs, *response.DevelopmentID)
+		}
+	}
+	if len(developmentIDs) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		DevelopmentID uint
+		models.DevelopmentRollup
+	}
+	if err := s.applySearchFilters(s.db.Model(&models.Property{}), req).
+		Select("development_id, COUNT(*) AS matching_units, "+
+			"MIN(NULLIF(price, 0)) AS min_price, MAX(NULLIF(price, 0)) AS max_price, "+
+			"MIN(monthly_rent) AS min_rent, MAX(monthly_rent) AS max_rent, "+
+			"MIN(bedrooms) AS min_bedrooms, MAX(bedrooms) AS max_bedrooms").
+		Where("development_id IN ?", developmentIDs).
+		Group("development_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	rollups := make(map[uint]models.DevelopmentRollup, len(rows))
+	for _, row := range rows {
+		rollups[row.DevelopmentID] = row.DevelopmentRollup
+	}
+	for i := range responses {
+		if responses[i].DevelopmentID == nil {
+			continue
+		}
+		if rollup, ok := rollups[*responses[i].DevelopmentID]; ok {
+			responses[i].Rollup = &rollup
+		}
+	}
+
+	return nil
+}
+
+// applySearchFilters applies the search request filters to a property query
+func (s *PropertyService) applySearchFilters(query *gorm.DB, req *models.PropertySearchRequest) *gorm.DB {
+	query = publicListings(query)
 
 	// Apply filters
 	if req.Query != "" {
@@ -183,33 +612,115 @@ func (s *PropertyService) SearchProperties(req *models.PropertySearchRequest) (*
 	if req.Status != nil {
 		query = query.Where("status = ?", *req.Status)
 	}
-
-	// Count total
-	if err := query.Model(&models.Property{}).Count(&total).Error; err != nil {
-		return nil, err
+	if req.ListingType != nil {
+		query = query.Where("listing_type = ?", *req.ListingType)
 	}
-
-	// Apply pagination
-	offset := (req.Page - 1) * req.PageSize
-	if err := query.Offset(offset).Limit(req.PageSize).Find(&properties).Error; err != nil {
-		return nil, err
+	if req.DevelopmentID != nil {
+		query = query.Where("development_id = ?", *req.DevelopmentID)
+	}
+	if req.Zoning != "" {
+		query = query.Where("commercial_zoning ILIKE ? OR land_zoning ILIKE ?", req.Zoning, req.Zoning)
+	}
+	if req.MinUsableSquareFeet != nil {
+		query = query.Where("commercial_usable_square_feet >= ?", *req.MinUsableSquareFeet)
+	}
+	if req.MinCapRate != nil {
+		query = query.Where("commercial_cap_rate >= ?", *req.MinCapRate)
+	}
+	if req.MaxCapRate != nil {
+		query = query.Where("commercial_cap_rate <= ?", *req.MaxCapRate)
+	}
+	if req.MinNOI != nil {
+		query = query.Where("commercial_net_operating_income >= ?", *req.MinNOI)
+	}
+	if req.CommercialLeaseType != nil {
+		query = query.Where("commercial_lease_type = ?", *req.CommercialLeaseType)
+	}
+	if req.MinAcreage != nil {
+		query = query.Where("land_acreage >= ?", *req.MinAcreage)
+	}
+	if req.MaxAcreage != nil {
+		query = query.Where("land_acreage <= ?", *req.MaxAcreage)
+	}
+	if req.Utilities != "" {
+		for _, utility := range strings.Split(req.Utilities, ",") {
+			query = query.Where("land_utilities::jsonb @> jsonb_build_array(?::text)", strings.TrimSpace(utility))
+		}
+	}
+	if req.Topography != nil {
+		query = query.Where("land_topography = ?", *req.Topography)
+	}
+	if len(req.Attributes) > 0 {
+		filters, err := resolveAttributeFilters(s.db, req.Attributes)
+		if err != nil {
+			// Fail the query rather than silently dropping the filters
+			query = query.Where("FALSE")
+			query.AddError(err)
+			return query
+		}
+		for _, filter := range filters {
+			query = query.Where("EXISTS (SELECT 1 FROM property_attributes WHERE property_attributes.property_id = properties.id "+
+				"AND property_attributes.attribute_id = ? AND property_attributes."+filter.condition+")", filter.attributeID, filter.value)
+		}
+	}
+	if req.MinRent != nil {
+		query = query.Where("monthly_rent >= ?", *req.MinRent)
+	}
+	if req.MaxRent != nil {
+		query = query.Where("monthly_rent <= ?", *req.MaxRent)
+	}
+	switch req.Pets {
+	case "cats":
+		query = query.Where("pet_policy IN ?", []models.PetPolicy{models.PetPolicyCats, models.PetPolicyCatsAndDogs, models.PetPolicyNegotiable})
+	case "dogs":
+		query = query.Where("pet_policy IN ?", []models.PetPolicy{models.PetPolicyDogs, models.PetPolicyCatsAndDogs, models.PetPolicyNegotiable})
+	}
+	if req.AvailableBy != nil {
+		query = query.Where("listing_type = ? AND (available_from IS NULL OR available_from <= ?)",
+			models.ListingTypeRent, req.AvailableBy.Format("2006-01-02"))
+	}
+	if req.MaxLeaseMonths != nil {
+		query = query.Where("lease_term_months IS NULL OR lease_term_months <= ?", *req.MaxLeaseMonths)
+	}
+	if req.HasOpenHouse || req.OpenHouseFrom != nil || req.OpenHouseTo != nil || req.OpenHouseWeekend {
+		query = s.applyOpenHouseFilter(query, req)
 	}
 
-	// Convert to responses
-	var responses []models.PropertyResponse
-	for _, property := range properties {
-		responses = append(responses, *s.getPropertyResponse(&property))
+	return query
+}
+
+// applyOpenHouseFilter limits a property query to listings with an upcoming
+// open house, optionally on given local dates
+func (s *PropertyService) applyOpenHouseFilter(query *gorm.DB, req *models.PropertySearchRequest) *gorm.DB {
+	conditions := []string{
+		"open_houses.property_id = properties.id",
+		"open_houses.deleted_at IS NULL",
+		"open_houses.ends_at > ?",
 	}
+	args := []interface{}{time.Now()}
 
-	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+	// Local date of the event in its own time zone
+	localDate := "(open_houses.starts_at AT TIME ZONE open_houses.time_zone)::date"
 
-	return &models.PaginationResponse{
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		Total:      total,
-		TotalPages: totalPages,
-		Data:       responses,
-	}, nil
+	from, to := req.OpenHouseFrom, req.OpenHouseTo
+	if req.OpenHouseWeekend {
+		loc, err := loadEventLocation(req.OpenHouseTimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		saturday, sunday := upcomingWeekend(time.Now().In(loc))
+		from, to = &saturday, &sunday
+	}
+	if from != nil {
+		conditions = append(conditions, localDate+" >= ?")
+		args = append(args, from.Format("2006-01-02"))
+	}
+	if to != nil {
+		conditions = append(conditions, localDate+" <= ?")
+		args = append(args, to.Format("2006-01-02"))
+	}
+
+	return query.Where("EXISTS (SELECT 1 FROM open_houses WHERE "+strings.Join(conditions, " AND ")+")", args...)
 }
 
 // GetPropertiesByAgent gets properties by agent ID
@@ -217,7 +728,7 @@ func (s *PropertyService) GetPropertiesByAgent(agentID uint, req *models.Paginat
 	var properties []models.Property
 	var total int64
 
-	query := s.db.Preload("Agent").Where("agent_id = ?", agentID)
+	query := s.db.Preload("Agent").Preload("Development").Preload("Attributes.Attribute").Preload("OpenHouses", upcomingOpenHouses).Where("agent_id = ?", agentID)
 
 	// Count total
 	if err := query.Model(&models.Property{}).Count(&total).Error; err != nil {
@@ -230,10 +741,18 @@ func (s *PropertyService) GetPropertiesByAgent(agentID uint, req *models.Paginat
 		return nil, err
 	}
 
+	// Agents see what would improve each listing
+	qualities, err := assessListingQuality(s.db, properties)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to responses
 	var responses []models.PropertyResponse
 	for _, property := range properties {
-		responses = append(responses, *s.getPropertyResponse(&property))
+		response := s.getPropertyResponse(&property)
+		response.Quality = qualities[property.ID]
+		responses = append(responses, *response)
 	}
 
 	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
@@ -247,6 +766,52 @@ func (s *PropertyService) GetPropertiesByAgent(agentID uint, req *models.Paginat
 	}, nil
 }
 
+// GetListingQuality scores an agent's listing and lists what would improve
+// it. The stored score used to rank search results is brought up to date.
+func (s *PropertyService) GetListingQuality(id, agentID uint) (*models.ListingQuality, error) {
+	var property models.Property
+	if err := s.db.First(&property, id).Error; err != nil {
+		return nil, err
+	}
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return refreshQualityScore(s.db, id)
+}
+
+// RefreshQualityScores recomputes the stored quality score of every live
+// listing, for example after attribute catalog changes
+func (s *PropertyService) RefreshQualityScores() (*models.QualityRefreshResult, error) {
+	result := &models.QualityRefreshResult{}
+
+	var batch []models.Property
+	err := s.db.Preload("Development").Preload("Attributes").Order("id").
+		FindInBatches(&batch, qualityRefreshBatchSize, func(tx *gorm.DB, _ int) error {
+			qualities, err := assessListingQuality(s.db, batch)
+			if err != nil {
+				return err
+			}
+			for i := range batch {
+				result.Scanned++
+				score := qualities[batch[i].ID].Score
+				if score == batch[i].QualityScore {
+					continue
+				}
+				if err := s.db.Model(&batch[i]).UpdateColumn("quality_score", score).Error; err != nil {
+					return err
+				}
+				result.Updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // getPropertyResponse converts Property to PropertyResponse
 func (s *PropertyService) getPropertyResponse(property *models.Property) *models.PropertyResponse {
 	agentResponse := models.UserResponse{
@@ -258,32 +823,87 @@ func (s *PropertyService) getPropertyResponse(property *models.Property) *models
 		Phone:     property.Agent.Phone,
 		Avatar:    property.Agent.Avatar,
 		IsActive:  property.Agent.IsActive,
+		Version:   property.Agent.Version,
 		CreatedAt: property.Agent.CreatedAt,
 		UpdatedAt: property.Agent.UpdatedAt,
 	}
 
-	return &models.PropertyResponse{
-		ID:           property.ID,
-		Title:        property.Title,
-		Description:  property.Description,
-		Price:        property.Price,
-		Address:      property.Address,
-		City:         property.City,
-		State:        property.State,
-		ZipCode:      property.ZipCode,
-		Country:      property.Country,
-		PropertyType: property.PropertyType,
-		Status:       property.Status,
-		Bedrooms:     property.Bedrooms,
-		Bathrooms:    property.Bathrooms,
-		SquareFeet:   property.SquareFeet,
-		YearBuilt:    property.YearBuilt,
-		LotSize:      property.LotSize,
-		Features:     property.Features,
-		Images:       property.Images,
-		VRModelURL:   property.VRModelURL,
-		Agent:        agentResponse,
-		CreatedAt:    property.CreatedAt,
-		UpdatedAt:    property.UpdatedAt,
-	}
+	openHouseResponses := make([]models.OpenHouseResponse, len(property.OpenHouses))
+	for i := range property.OpenHouses {
+		openHouseResponses[i] = *toOpenHouseResponse(&property.OpenHouses[i])
+	}
+
+	response := &models.PropertyResponse{
+		ID:               property.ID,
+		Title:            property.Title,
+		Description:      property.Description,
+		Price:            property.Price,
+		MonthlyRent:      property.MonthlyRent,
+		SecurityDeposit:  property.SecurityDeposit,
+		LeaseTermMonths:  property.LeaseTermMonths,
+		PetPolicy:        property.PetPolicy,
+		AvailableFrom:    property.AvailableFrom,
+		Address:          property.Address,
+		City:             property.City,
+		State:            property.State,
+		ZipCode:          property.ZipCode,
+		Country:          property.Country,
+		PropertyType:     property.PropertyType,
+		ListingType:      property.ListingType,
+		Status:           property.Status,
+		Bedrooms:         property.Bedrooms,
+		Bathrooms:        property.Bathrooms,
+		SquareFeet:       property.SquareFeet,
+		YearBuilt:        property.YearBuilt,
+		LotSize:          property.LotSize,
+		Latitude:         property.Latitude,
+		Longitude:        property.Longitude,
+		GeocodeAccuracy:  property.GeocodeAccuracy,
+		Features:         property.Features,
+		Attributes:       toAttributeValues(property.Attributes),
+		Images:           property.Images,
+		VRModelURL:       property.VRModelURL,
+		DevelopmentID:    property.DevelopmentID,
+		UnitNumber:       property.UnitNumber,
+		Agent:            agentResponse,
+		SourceFeedID:     property.SourceFeedID,
+		SourceListingKey: property.SourceListingKey,
+		SoldPrice:        property.SoldPrice,
+		SoldAt:           property.SoldAt,
+		QualityScore:     property.QualityScore,
+		ModerationStatus: property.ModerationStatus,
+		Compliance:       property.Compliance,
+		Version:          property.Version,
+		OpenHouses:       openHouseResponses,
+		CreatedAt:        property.CreatedAt,
+		UpdatedAt:        property.UpdatedAt,
+	}
+
+	switch property.PropertyType {
+	case models.PropertyTypeCommercial:
+		commercial := property.Commercial
+		response.Commercial = &commercial
+	case models.PropertyTypeLand:
+		land := property.Land
+		response.Land = &land
+	}
+
+	// Units fall back to their development's media and VR tour
+	if development := property.Development; development != nil {
+		response.Development = &models.DevelopmentSummary{
+			ID:         development.ID,
+			Name:       development.Name,
+			Amenities:  development.Amenities,
+			Images:     development.Images,
+			VRModelURL: development.VRModelURL,
+		}
+		if len(response.Images) == 0 {
+			response.Images = development.Images
+		}
+		if response.VRModelURL == "" {
+			response.VRModelURL = development.VRModelURL
+		}
+	}
+
+	return response
 }
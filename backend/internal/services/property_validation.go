@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"galactavista/internal/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError is returned when a request fails field validation
+type ValidationError struct {
+	Errors []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// maxLeaseTermMonths is the longest lease term a rental may require
+const maxLeaseTermMonths = 60
+
+// ValidatePropertyCreateRequest checks a create request against the binding
+// rules and the property domain rules, returning every failing field
+func ValidatePropertyCreateRequest(req *models.PropertyCreateRequest) []models.FieldError {
+	var fieldErrors []models.FieldError
+
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, fe := range validationErrors {
+				fieldErrors = append(fieldErrors, models.FieldError{
+					Field:   jsonFieldName(req, fe.StructField()),
+					Message: validationMessage(fe),
+				})
+			}
+		} else {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "", Message: err.Error()})
+		}
+	}
+
+	if req.PropertyType != "" && !req.PropertyType.IsValid() {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   "property_type",
+			Message: fmt.Sprintf("unknown property type %q", req.PropertyType),
+		})
+	}
+	if req.Price < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "price", Message: "must not be negative"})
+	}
+	if req.Bedrooms < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "bedrooms", Message: "must not be negative"})
+	}
+	if req.Bathrooms < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "bathrooms", Message: "must not be negative"})
+	}
+	if req.SquareFeet < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "square_feet", Message: "must not be negative"})
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "latitude", Message: "latitude and longitude must be given together"})
+	}
+	fieldErrors = append(fieldErrors, validateGeocodeAccuracy(&models.Property{
+		Latitude:        req.Latitude,
+		GeocodeAccuracy: req.GeocodeAccuracy,
+	})...)
+
+	listing := models.Property{
+		ListingType:     req.ListingType,
+		Price:           req.Price,
+		MonthlyRent:     req.MonthlyRent,
+		SecurityDeposit: req.SecurityDeposit,
+		LeaseTermMonths: req.LeaseTermMonths,
+		PetPolicy:       req.PetPolicy,
+	}
+	if listing.ListingType == "" {
+		listing.ListingType = models.ListingTypeSale
+	}
+	if req.AvailableFrom != "" {
+		listing.AvailableFrom = &time.Time{}
+	}
+	fieldErrors = append(fieldErrors, validateListingTerms(&listing)...)
+
+	typed := models.Property{
+		PropertyType: req.PropertyType,
+		Bedrooms:     req.Bedrooms,
+		Bathrooms:    req.Bathrooms,
+		SquareFeet:   req.SquareFeet,
+	}
+	if req.Commercial != nil {
+		typed.Commercial = *req.Commercial
+	}
+	if req.Land != nil {
+		typed.Land = *req.Land
+	}
+	fieldErrors = append(fieldErrors, validatePropertyTypeFields(&typed)...)
+
+	return fieldErrors
+}
+
+// validateListingTerms checks that a listing's price and rental terms match
+// its listing type: sale listings have a price and no rental terms, rentals
+// have a monthly rent instead of a price
+func validateListingTerms(property *models.Property) []models.FieldError {
+	var fieldErrors []models.FieldError
+	if !property.ListingType.IsValid() {
+		return append(fieldErrors, models.FieldError{
+			Field:   "listing_type",
+			Message: fmt.Sprintf("unknown listing type %q", property.ListingType),
+		})
+	}
+
+	if property.ListingType == models.ListingTypeSale {
+		if property.Price == 0 {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "price", Message: "is required"})
+		}
+		rentalTerms := []struct {
+			field string
+			set   bool
+		}{
+			{"monthly_rent", property.MonthlyRent != nil},
+			{"security_deposit", property.SecurityDeposit != nil},
+			{"lease_term_months", property.LeaseTermMonths != nil},
+			{"pet_policy", property.PetPolicy != ""},
+			{"available_from", property.AvailableFrom != nil},
+		}
+		for _, term := range rentalTerms {
+			if term.set {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: term.field, Message: "only applies to rental listings"})
+			}
+		}
+		return fieldErrors
+	}
+
+	if property.MonthlyRent == nil || *property.MonthlyRent <= 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "monthly_rent", Message: "is required and must be positive"})
+	}
+	if property.Price != 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "price", Message: "rental listings use monthly_rent instead of price"})
+	}
+	if property.SecurityDeposit != nil && *property.SecurityDeposit < 0 {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "security_deposit", Message: "must not be negative"})
+	}
+	if property.LeaseTermMonths != nil && (*property.LeaseTermMonths < 1 || *property.LeaseTermMonths > maxLeaseTermMonths) {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   "lease_term_months",
+			Message: fmt.Sprintf("must be between 1 and %d", maxLeaseTermMonths),
+		})
+	}
+	if property.PetPolicy != "" && !property.PetPolicy.IsValid() {
+		fieldErrors = append(fieldErrors, models.FieldError{
+			Field:   "pet_policy",
+			Message: fmt.Sprintf("unknown pet policy %q", property.PetPolicy),
+		})
+	}
+	return fieldErrors
+}
+
+// validatePropertyTypeFields checks the fields that depend on the property
+// type: commercial and land listings have no bedrooms or bathrooms and need
+// their zoning, land its acreage, and other types cannot use either set
+func validatePropertyTypeFields(property *models.Property) []models.FieldError {
+	var fieldErrors []models.FieldError
+	isCommercial := property.PropertyType == models.PropertyTypeCommercial
+	isLand := property.PropertyType == models.PropertyTypeLand
+
+	if isCommercial || isLand {
+		if property.Bedrooms != 0 {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "bedrooms", Message: fmt.Sprintf("does not apply to %s listings", property.PropertyType)})
+		}
+		if property.Bathrooms != 0 {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "bathrooms", Message: fmt.Sprintf("does not apply to %s listings", property.PropertyType)})
+		}
+	}
+	if !isCommercial && !property.Commercial.IsZero() {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "commercial", Message: "only applies to commercial listings"})
+	}
+	if !isLand && !property.Land.IsZero() {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "land", Message: "only applies to land listings"})
+	}
+
+	if isCommercial {
+		commercial := &property.Commercial
+		if strings.TrimSpace(commercial.Zoning) == "" {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "commercial.zoning", Message: "is required"})
+		}
+		if usable := commercial.UsableSquareFeet; usable != nil {
+			if *usable <= 0 {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: "commercial.usable_square_feet", Message: "must be positive"})
+			} else if property.SquareFeet > 0 && *usable > property.SquareFeet {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: "commercial.usable_square_feet", Message: "must not exceed square_feet"})
+			}
+		}
+		if commercial.CapRate != nil && (*commercial.CapRate <= 0 || *commercial.CapRate > 100) {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "commercial.cap_rate", Message: "must be a percentage between 0 and 100"})
+		}
+		if commercial.LeaseType != "" && !commercial.LeaseType.IsValid() {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   "commercial.lease_type",
+				Message: fmt.Sprintf("unknown lease type %q", commercial.LeaseType),
+			})
+		}
+	}
+
+	if isLand {
+		land := &property.Land
+		if land.Acreage == nil || *land.Acreage <= 0 {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "land.acreage", Message: "is required and must be positive"})
+		}
+		if strings.TrimSpace(land.Zoning) == "" {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: "land.zoning", Message: "is required"})
+		}
+		seen := make(map[models.Utility]bool, len(land.Utilities))
+		for _, utility := range land.Utilities {
+			if !utility.IsValid() {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: "land.utilities", Message: fmt.Sprintf("unknown utility %q", utility)})
+			} else if seen[utility] {
+				fieldErrors = append(fieldErrors, models.FieldError{Field: "land.utilities", Message: fmt.Sprintf("%q is listed twice", utility)})
+			}
+			seen[utility] = true
+		}
+		if land.Topography != "" && !land.Topography.IsValid() {
+			fieldErrors = append(fieldErrors, models.FieldError{
+				Field:   "land.topography",
+				Message: fmt.Sprintf("unknown topography %q", land.Topography),
+			})
+		}
+	}
+
+	return fieldErrors
+}
+
+// validateGeocodeAccuracy checks that a geocode accuracy is known and only
+// describes coordinates that are set
+func validateGeocodeAccuracy(property *models.Property) []models.FieldError {
+	switch {
+	case property.GeocodeAccuracy == "":
+		return nil
+	case !property.GeocodeAccuracy.IsValid():
+		return []models.FieldError{{
+			Field:   "geocode_accuracy",
+			Message: fmt.Sprintf("unknown geocode accuracy %q", property.GeocodeAccuracy),
+		}}
+	case property.Latitude == nil:
+		return []models.FieldError{{Field: "geocode_accuracy", Message: "requires latitude and longitude"}}
+	}
+	return nil
+}
+
+// inheritedFieldErrors rejects changes to the fields a development unit
+// takes from its development
+func inheritedFieldErrors(req *models.PropertyUpdateRequest) []models.FieldError {
+	inherited := []struct {
+		field string
+		set   bool
+	}{
+		{"address", req.Address != nil},
+		{"city", req.City != nil},
+		{"state", req.State != nil},
+		{"zip_code", req.ZipCode != nil},
+		{"country", req.Country != nil},
+		{"latitude", req.Latitude != nil},
+		{"longitude", req.Longitude != nil},
+		{"year_built", req.YearBuilt != nil},
+	}
+
+	var fieldErrors []models.FieldError
+	for _, field := range inherited {
+		if field.set {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: field.field, Message: "is set on the development"})
+		}
+	}
+	return fieldErrors
+}
+
+// jsonFieldName returns the JSON name of a struct field
+func jsonFieldName(v interface{}, structField string) string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if field, ok := t.FieldByName(structField); ok {
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return structField
+}
+
+// validationMessage converts a validator error into a readable message
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "email":
+		return "must be a valid email address"
+	default:
+		return "failed " + fe.Tag() + " validation"
+	}
+}
@@ -0,0 +1,610 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/tabular"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reportMaxDays is the longest range a report may span
+const reportMaxDays = 5 * 366
+
+// Joins and grouping expressions shared by the metrics of listings. The
+// joins bring in each listing's agent as u and brokerage as b.
+const (
+	reportAgentJoins   = "LEFT JOIN users u ON u.id = p.agent_id LEFT JOIN brokerages b ON b.id = u.brokerage_id"
+	reportAgentDim     = "CONCAT(u.first_name, ' ', u.last_name, ' (', u.id, ')')"
+	reportBrokerageDim = "b.name"
+	reportPropertyType = "p.property_type"
+	reportListingType  = "p.listing_type"
+	reportListingState = "p.state"
+)
+
+// reportDimension is a way a metric can be grouped
+type reportDimension struct {
+	name string
+	expr string
+}
+
+// reportMetric defines how a metric is aggregated: the tables it reads,
+// the timestamp that places a row in a period, the count and optional
+// summed value, and the dimensions it can be grouped by. Tables are read
+// directly, so soft-deleted rows count towards what happened.
+type reportMetric struct {
+	metric      models.ReportMetric
+	label       string
+	description string
+	from        string
+	where       string
+	timeColumn  string
+	count       string
+	value       string
+	valueLabel  string
+	dimensions  []reportDimension
+}
+
+// reportMetrics are the metrics admins can report on
+var reportMetrics = []reportMetric{
+	{
+		metric:      models.ReportRegistrations,
+		label:       "New registrations",
+		description: "Users who signed up",
+		from:        "users u LEFT JOIN brokerages b ON b.id = u.brokerage_id",
+		timeColumn:  "u.created_at",
+		count:       "COUNT(*)",
+		dimensions: []reportDimension{
+			{"role", "u.role"},
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+	{
+		metric:      models.ReportListingsCreated,
+		label:       "Listings created",
+		description: "Listings created by agents, imports and MLS feeds",
+		from:        "properties p " + reportAgentJoins,
+		timeColumn:  "p.created_at",
+		count:       "COUNT(*)",
+		dimensions: []reportDimension{
+			{"property_type", reportPropertyType},
+			{"listing_type", reportListingType},
+			{"state", reportListingState},
+			{"agent", reportAgentDim},
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+	{
+		metric:      models.ReportListingsSold,
+		label:       "Listings sold",
+		description: "Listings marked sold, with their total sale price",
+		from:        "properties p " + reportAgentJoins,
+		where:       "p.status = 'sold'",
+		timeColumn:  "p.sold_at",
+		count:       "COUNT(*)",
+		value:       "SUM(COALESCE(p.sold_price, p.price))",
+		valueLabel:  "sales_volume",
+		dimensions: []reportDimension{
+			{"property_type", reportPropertyType},
+			{"state", reportListingState},
+			{"agent", reportAgentDim},
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+	{
+		metric:      models.ReportMediaStorage,
+		label:       "Media storage",
+		description: "Media files uploaded and the bytes they take up; files in the trash count until they are purged",
+		from:        "media_files m JOIN properties p ON p.id = m.property_id " + reportAgentJoins,
+		timeColumn:  "m.created_at",
+		count:       "COUNT(*)",
+		value:       "SUM(m.file_size)",
+		valueLabel:  "bytes",
+		dimensions: []reportDimension{
+			{"file_type", "m.file_type"},
+			{"agent", reportAgentDim},
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+	{
+		metric:      models.ReportVRToursPublished,
+		label:       "VR tours published",
+		description: "VR tours added to listings",
+		from:        "vr_tours t JOIN properties p ON p.id = t.property_id " + reportAgentJoins,
+		timeColumn:  "t.created_at",
+		count:       "COUNT(*)",
+		dimensions: []reportDimension{
+			{"property_type", reportPropertyType},
+			{"agent", reportAgentDim},
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+	{
+		metric:      models.ReportActiveAgents,
+		label:       "Active agents",
+		description: "Agents who created, edited or restored a listing",
+		from:        "property_revisions r JOIN users u ON u.id = r.editor_id LEFT JOIN brokerages b ON b.id = u.brokerage_id",
+		where:       "u.role = 'agent'",
+		timeColumn:  "r.created_at",
+		count:       "COUNT(DISTINCT u.id)",
+		dimensions: []reportDimension{
+			{"brokerage", reportBrokerageDim},
+		},
+	},
+}
+
+// ReportService handles admin business reports and their email schedules
+type ReportService struct {
+	db       *gorm.DB
+	mailer   Mailer
+	notifier Notifier
+}
+
+// NewReportService creates a new report service
+func NewReportService(db *gorm.DB, mailer Mailer, notifier Notifier) *ReportService {
+	return &ReportService{db: db, mailer: mailer, notifier: notifier}
+}
+
+// reportRow is an aggregate row of a report query
+type reportRow struct {
+	Period time.Time
+	Grp    string
+	Count  int64
+	Value  *float64
+}
+
+// GetMetrics lists the metrics reports can be run on
+func (s *ReportService) GetMetrics() []models.ReportMetricInfo {
+	infos := make([]models.ReportMetricInfo, len(reportMetrics))
+	for i, metric := range reportMetrics {
+		infos[i] = models.ReportMetricInfo{
+			Metric:      metric.metric,
+			Label:       metric.label,
+			Description: metric.description,
+			ValueLabel:  metric.valueLabel,
+			Dimensions:  make([]string, len(metric.dimensions)),
+		}
+		for j, dimension := range metric.dimensions {
+			infos[i].Dimensions[j] = dimension.name
+		}
+	}
+	return infos
+}
+
+// GetReport aggregates a metric over a date range
+func (s *ReportService) GetReport(metric models.ReportMetric, req *models.ReportRequest) (*models.Report, error) {
+	definition, dimension, err := findReportMetric(metric, req.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	r, err := resolveAnalyticsRange(&req.AnalyticsRequest, time.Now(), reportMaxDays)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildReport(definition, dimension, r)
+}
+
+// ExportReport writes a report as a table: one row per period and group,
+// followed by the totals with "total" in place of the period
+func (s *ReportService) ExportReport(w io.Writer, report *models.Report, format tabular.Format) error {
+	header := []string{"period"}
+	if report.GroupBy != "" {
+		header = append(header, report.GroupBy)
+	}
+	header = append(header, "count")
+	if report.ValueLabel != "" {
+		header = append(header, report.ValueLabel)
+	}
+
+	rows := [][]string{header}
+	record := func(period, group string, count int64, value *float64) {
+		row := []string{period}
+		if report.GroupBy != "" {
+			row = append(row, group)
+		}
+		row = append(row, strconv.FormatInt(count, 10))
+		if report.ValueLabel != "" {
+			row = append(row, formatOptionalFloat(value))
+		}
+		rows = append(rows, row)
+	}
+	for _, row := range report.Rows {
+		record(row.Period.Format("2006-01-02"), row.Group, row.Count, row.Value)
+	}
+	for _, total := range report.Totals {
+		record("total", total.Group, total.Count, total.Value)
+	}
+
+	return tabular.Write(w, rows, format)
+}
+
+// buildReport runs a metric's aggregation over a range
+func (s *ReportService) buildReport(definition *reportMetric, dimension *reportDimension, r *analyticsRange) (*models.Report, error) {
+	report := &models.Report{
+		Metric:     definition.metric,
+		Label:      definition.label,
+		ValueLabel: definition.valueLabel,
+		From:       r.from,
+		To:         r.to,
+		Interval:   r.interval,
+		Rows:       []models.ReportRow{},
+		Totals:     []models.ReportTotal{},
+	}
+	if dimension != nil {
+		report.GroupBy = dimension.name
+	}
+
+	var rows []reportRow
+	if err := s.reportQuery(definition, dimension, r, true).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		// The first period may start before the range when it does not
+		// begin on a period boundary
+		period := time.Date(row.Period.Year(), row.Period.Month(), row.Period.Day(), 0, 0, 0, 0, time.UTC)
+		if period.Before(r.from) {
+			period = r.from
+		}
+		report.Rows = append(report.Rows, models.ReportRow{Period: period, Group: row.Grp, Count: row.Count, Value: row.Value})
+	}
+
+	var totals []reportRow
+	if err := s.reportQuery(definition, dimension, r, false).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	for _, total := range totals {
+		report.Totals = append(report.Totals, models.ReportTotal{Group: total.Grp, Count: total.Count, Value: total.Value})
+	}
+
+	return report, nil
+}
+
+// reportQuery builds a metric's aggregate query, split by period when
+// byPeriod is set and by the dimension when one is given
+func (s *ReportService) reportQuery(definition *reportMetric, dimension *reportDimension, r *analyticsRange, byPeriod bool) *gorm.DB {
+	query := s.db.Table(definition.from).
+		Where(definition.timeColumn+" >= ? AND "+definition.timeColumn+" < ?", r.from, r.end)
+	if definition.where != "" {
+		query = query.Where(definition.where)
+	}
+
+	var columns, groups []string
+	var args []interface{}
+	if byPeriod {
+		columns = append(columns, "date_trunc(?, "+definition.timeColumn+" AT TIME ZONE 'UTC') AS period")
+		args = append(args, string(r.interval))
+		groups = append(groups, strconv.Itoa(len(groups)+1))
+	}
+	if dimension != nil {
+		columns = append(columns, "COALESCE(CAST("+dimension.expr+" AS TEXT), '') AS grp")
+		groups = append(groups, strconv.Itoa(len(groups)+1))
+	}
+	columns = append(columns, definition.count+" AS count")
+	if definition.value != "" {
+		columns = append(columns, definition.value+" AS value")
+	}
+
+	query = query.Select(strings.Join(columns, ", "), args...)
+	if len(groups) > 0 {
+		query = query.Group(strings.Join(groups, ", ")).Order(strings.Join(groups, ", "))
+	}
+	return query
+}
+
+// GetSchedules lists the report schedules
+func (s *ReportService) GetSchedules() ([]models.ReportSchedule, error) {
+	var schedules []models.ReportSchedule
+	if err := s.db.Order("id").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// CreateSchedule schedules a report. It is first sent when the current
+// period ends.
+func (s *ReportService) CreateSchedule(adminID uint, req *models.ReportScheduleRequest) (*models.ReportSchedule, error) {
+	if err := validateReportSchedule(req); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.ReportSchedule{
+		Name:        req.Name,
+		Metric:      req.Metric,
+		GroupBy:     req.GroupBy,
+		Frequency:   req.Frequency,
+		Recipients:  req.Recipients,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+		NextRunAt:   nextReportRun(req.Frequency, time.Now()),
+		CreatedByID: adminID,
+	}
+	if err := s.db.Create(schedule).Error; err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule changes a report schedule. Changing the frequency moves
+// the next run to the end of the current period.
+func (s *ReportService) UpdateSchedule(id uint, req *models.ReportScheduleRequest) (*models.ReportSchedule, error) {
+	if err := validateReportSchedule(req); err != nil {
+		return nil, err
+	}
+
+	var schedule models.ReportSchedule
+	if err := s.db.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+
+	if req.Frequency != schedule.Frequency {
+		schedule.NextRunAt = nextReportRun(req.Frequency, time.Now())
+	}
+	schedule.Name = req.Name
+	schedule.Metric = req.Metric
+	schedule.GroupBy = req.GroupBy
+	schedule.Frequency = req.Frequency
+	schedule.Recipients = req.Recipients
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+	if err := s.db.Save(&schedule).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// DeleteSchedule removes a report schedule
+func (s *ReportService) DeleteSchedule(id uint) error {
+	result := s.db.Delete(&models.ReportSchedule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SendSchedule sends a scheduled report for the last full period now,
+// without moving its next run
+func (s *ReportService) SendSchedule(id uint) (*models.ReportSchedule, error) {
+	var schedule models.ReportSchedule
+	if err := s.db.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.sendReport(&schedule, time.Now()); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SendDueReports sends every active scheduled report whose next run has
+// come. Each schedule is claimed by moving its next run first, so servers
+// sharing the database do not send a report twice. A report missed while
+// no server was running is sent once, for the last full period.
+func (s *ReportService) SendDueReports(now time.Time) {
+	var schedules []models.ReportSchedule
+	if err := s.db.Where("is_active = ? AND next_run_at <= ?", true, now).Order("next_run_at, id").Find(&schedules).Error; err != nil {
+		log.Printf("report scheduler: failed to load due schedules: %v", err)
+		return
+	}
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		next := nextReportRun(schedule.Frequency, now)
+		result := s.db.Model(&models.ReportSchedule{}).
+			Where("id = ? AND next_run_at = ?", schedule.ID, schedule.NextRunAt).
+			Update("next_run_at", next)
+		if result.Error != nil {
+			log.Printf("report scheduler: failed to claim schedule %d: %v", schedule.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		schedule.NextRunAt = next
+
+		if err := s.sendReport(schedule, now); err != nil {
+			log.Printf("report scheduler: schedule %d failed: %v", schedule.ID, err)
+			continue
+		}
+		log.Printf("report scheduler: sent schedule %d to %d recipients", schedule.ID, len(schedule.Recipients))
+	}
+}
+
+// RunScheduler sends due reports every interval until ctx is cancelled
+func (s *ReportService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.SendDueReports(now)
+		}
+	}
+}
+
+// sendReport emails a schedule's report for the last full period before
+// now and records the outcome on the schedule. The admin who created the
+// schedule is told when it fails.
+func (s *ReportService) sendReport(schedule *models.ReportSchedule, now time.Time) error {
+	err := s.mailReport(schedule, now)
+
+	schedule.LastRunAt = &now
+	schedule.LastRunStatus = "sent"
+	schedule.LastRunError = ""
+	if err != nil {
+		schedule.LastRunStatus = "failed"
+		schedule.LastRunError = err.Error()
+	}
+	if updateErr := s.db.Model(schedule).Select("last_run_at", "last_run_status", "last_run_error").Updates(schedule).Error; updateErr != nil {
+		log.Printf("failed to record run of report schedule %d: %v", schedule.ID, updateErr)
+	}
+
+	if err != nil {
+		var admin models.User
+		if lookupErr := s.db.First(&admin, schedule.CreatedByID).Error; lookupErr == nil {
+			if notifyErr := s.notifier.Notify(Notification{
+				UserID:  admin.ID,
+				Email:   admin.Email,
+				Subject: fmt.Sprintf("Scheduled report %q could not be sent", schedule.Name),
+				Body:    err.Error(),
+			}); notifyErr != nil {
+				log.Printf("failed to notify admin %d about report schedule %d: %v", admin.ID, schedule.ID, notifyErr)
+			}
+		}
+	}
+	return err
+}
+
+// mailReport builds a schedule's report for the last full period before
+// now, broken down by day, and mails it with the report attached as CSV
+func (s *ReportService) mailReport(schedule *models.ReportSchedule, now time.Time) error {
+	definition, dimension, err := findReportMetric(schedule.Metric, schedule.GroupBy)
+	if err != nil {
+		return err
+	}
+
+	interval := schedule.Frequency.Interval()
+	end := startOfPeriod(now, interval)
+	from := previousPeriod(end, interval)
+	report, err := s.buildReport(definition, dimension, &analyticsRange{
+		from:     from,
+		to:       end.AddDate(0, 0, -1),
+		end:      end,
+		interval: models.AnalyticsIntervalDay,
+	})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := s.ExportReport(&buf, report, tabular.FormatCSV); err != nil {
+		return err
+	}
+
+	period := report.From.Format("2006-01-02")
+	if !report.To.Equal(report.From) {
+		period += " to " + report.To.Format("2006-01-02")
+	}
+	return s.mailer.Send(Mail{
+		To:      schedule.Recipients,
+		Subject: fmt.Sprintf("%s: %s, %s", schedule.Name, report.Label, period),
+		Body:    reportSummary(report, period),
+		Attachments: []MailAttachment{{
+			FileName:    fmt.Sprintf("%s-%s.csv", report.Metric, report.From.Format("20060102")),
+			ContentType: tabular.FormatCSV.ContentType(),
+			Data:        buf.Bytes(),
+		}},
+	})
+}
+
+// reportSummary is the plain-text body of a report email: the totals, with
+// the full breakdown left to the attachment
+func reportSummary(report *models.Report, period string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s for %s\n\n", report.Label, period)
+	if len(report.Totals) == 0 {
+		b.WriteString("No activity in this period.\n")
+	}
+	for _, total := range report.Totals {
+		label := "Total"
+		if report.GroupBy != "" {
+			label = total.Group
+			if label == "" {
+				label = "(none)"
+			}
+		}
+		fmt.Fprintf(&b, "%s: %d", label, total.Count)
+		if report.ValueLabel != "" {
+			fmt.Fprintf(&b, " (%s %s)", report.ValueLabel, formatOptionalFloat(total.Value))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nThe attached CSV breaks the figures down by day.\n")
+	return b.String()
+}
+
+// findReportMetric looks up a metric and the dimension to group it by
+func findReportMetric(metric models.ReportMetric, groupBy string) (*reportMetric, *reportDimension, error) {
+	for i := range reportMetrics {
+		definition := &reportMetrics[i]
+		if definition.metric != metric {
+			continue
+		}
+		if groupBy == "" {
+			return definition, nil, nil
+		}
+
+		names := make([]string, len(definition.dimensions))
+		for j := range definition.dimensions {
+			if definition.dimensions[j].name == groupBy {
+				return definition, &definition.dimensions[j], nil
+			}
+			names[j] = definition.dimensions[j].name
+		}
+		return nil, nil, &ValidationError{Errors: []models.FieldError{{
+			Field:   "group_by",
+			Message: fmt.Sprintf("%s can be grouped by %s", metric, strings.Join(names, ", ")),
+		}}}
+	}
+
+	return nil, nil, &ValidationError{Errors: []models.FieldError{{Field: "metric", Message: fmt.Sprintf("unknown metric %q", metric)}}}
+}
+
+// validateReportSchedule checks a schedule's metric, grouping and
+// frequency
+func validateReportSchedule(req *models.ReportScheduleRequest) error {
+	if _, _, err := findReportMetric(req.Metric, req.GroupBy); err != nil {
+		return err
+	}
+	if !req.Frequency.IsValid() {
+		return &ValidationError{Errors: []models.FieldError{{Field: "frequency", Message: "must be daily, weekly or monthly"}}}
+	}
+	return nil
+}
+
+// nextReportRun returns when a report of the frequency is next due: the
+// end of the period now falls in
+func nextReportRun(frequency models.ReportFrequency, now time.Time) time.Time {
+	interval := frequency.Interval()
+	return nextPeriod(startOfPeriod(now, interval), interval)
+}
+
+// startOfPeriod returns the start of the UTC day, week or month t falls in
+func startOfPeriod(t time.Time, interval models.AnalyticsInterval) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case models.AnalyticsIntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.AnalyticsIntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// previousPeriod returns the start of the period before the one starting
+// at start
+func previousPeriod(start time.Time, interval models.AnalyticsInterval) time.Time {
+	switch interval {
+	case models.AnalyticsIntervalWeek:
+		return start.AddDate(0, 0, -7)
+	case models.AnalyticsIntervalMonth:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
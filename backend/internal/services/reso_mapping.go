@@ -0,0 +1,237 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/reso"
+)
+
+// mapRESOPropertyType maps RESO PropertyType/PropertySubType onto PropertyType
+func mapRESOPropertyType(propertyType, subType string) (models.PropertyType, error) {
+	switch strings.ToLower(subType) {
+	case "single family residence", "single family detached", "manufactured home", "farm":
+		return models.PropertyTypeHouse, nil
+	case "condominium", "stock cooperative":
+		return models.PropertyTypeCondo, nil
+	case "townhouse", "duplex", "triplex", "quadruplex":
+		return models.PropertyTypeTownhouse, nil
+	case "apartment":
+		return models.PropertyTypeApartment, nil
+	}
+
+	switch strings.ToLower(propertyType) {
+	case "residential", "residential lease", "farm", "manufactured in park":
+		return models.PropertyTypeHouse, nil
+	case "residential income":
+		return models.PropertyTypeApartment, nil
+	case "land":
+		return models.PropertyTypeLand, nil
+	case "commercial sale", "commercial lease", "business opportunity":
+		return models.PropertyTypeCommercial, nil
+	}
+
+	return "", fmt.Errorf("unsupported property type %q/%q", propertyType, subType)
+}
+
+// isRESOLease reports whether a RESO PropertyType is a lease (rental) type
+func isRESOLease(propertyType string) bool {
+	return strings.Contains(strings.ToLower(propertyType), "lease")
+}
+
+// mapRESOStatus maps RESO StandardStatus onto PropertyStatus
+func mapRESOStatus(status, propertyType string) models.PropertyStatus {
+	switch status {
+	case reso.StatusPending, reso.StatusActiveUnderContract:
+		return models.PropertyStatusPending
+	case reso.StatusClosed:
+		if isRESOLease(propertyType) {
+			return models.PropertyStatusRented
+		}
+		return models.PropertyStatusSold
+	default:
+		return models.PropertyStatusAvailable
+	}
+}
+
+// isOffMarket reports whether a RESO status means the listing should be hidden
+func isOffMarket(status string) bool {
+	switch status {
+	case reso.StatusCanceled, reso.StatusExpired, reso.StatusWithdrawn, reso.StatusHold, reso.StatusDelete:
+		return true
+	}
+	return false
+}
+
+// mediaFileType maps a RESO media item onto the media file types used locally
+func mediaFileType(media reso.Media) string {
+	category := strings.ToLower(media.MediaCategory)
+	mimeType := strings.ToLower(media.MimeType)
+	switch {
+	case category == "photo" || strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case category == "video" || strings.HasPrefix(mimeType, "video/"):
+		return "video"
+	}
+
+	switch strings.ToLower(path.Ext(media.MediaURL)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return "image"
+	case ".mp4", ".avi", ".mov":
+		return "video"
+	}
+	return "other"
+}
+
+// sortedMedia returns media ordered by their RESO Order field
+func sortedMedia(media []reso.Media) []reso.Media {
+	sorted := make([]reso.Media, len(media))
+	copy(sorted, media)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Order < sorted[j].Order
+	})
+	return sorted
+}
+
+// toRESOProperty maps a Property and its media onto a RESO Data Dictionary record
+func toRESOProperty(property *models.Property, media []models.MediaFile) reso.Property {
+	propertyType, subType := toRESOPropertyType(property.PropertyType)
+
+	record := reso.Property{
+		ListingKey:              strconv.FormatUint(uint64(property.ID), 10),
+		StandardStatus:          toRESOStatus(property),
+		PropertyType:            propertyType,
+		PropertySubType:         subType,
+		ListPrice:               property.Price,
+		UnparsedAddress:         property.Address,
+		City:                    property.City,
+		StateOrProvince:         property.State,
+		PostalCode:              property.ZipCode,
+		Country:                 property.Country,
+		BedroomsTotal:           property.Bedrooms,
+		BathroomsTotalInteger:   int(math.Round(property.Bathrooms)),
+		LivingArea:              float64(property.SquareFeet),
+		YearBuilt:               property.YearBuilt,
+		LotSizeAcres:            property.LotSize,
+		PublicRemarks:           property.Description,
+		InteriorFeatures:        property.Features,
+		VirtualTourURLUnbranded: property.VRModelURL,
+		ListAgentEmail:          property.Agent.Email,
+		ListAgentFullName:       strings.TrimSpace(property.Agent.FirstName + " " + property.Agent.LastName),
+		ModificationTimestamp:   property.UpdatedAt.UTC(),
+	}
+	if property.SourceListingKey != nil {
+		record.ListingId = *property.SourceListingKey
+	}
+	if property.ListingType == models.ListingTypeRent {
+		record.PropertyType = toRESOLeaseType(propertyType)
+		if property.MonthlyRent != nil {
+			record.ListPrice = *property.MonthlyRent
+		}
+	}
+	if property.Latitude != nil && property.Longitude != nil {
+		record.Latitude, record.Longitude = *property.Latitude, *property.Longitude
+	}
+	if property.SoldPrice != nil {
+		record.ClosePrice = *property.SoldPrice
+	}
+	if property.SoldAt != nil {
+		record.CloseDate = property.SoldAt.UTC().Format(reso.DateFormat)
+	}
+	if property.AgentID != 0 {
+		record.ListAgentKey = strconv.FormatUint(uint64(property.AgentID), 10)
+	}
+	if property.DeletedAt.Valid && property.DeletedAt.Time.After(record.ModificationTimestamp) {
+		record.ModificationTimestamp = property.DeletedAt.Time.UTC()
+	}
+	createdAt := property.CreatedAt.UTC()
+	record.OriginalEntryTimestamp = &createdAt
+
+	order := 0
+	for _, mediaFile := range media {
+		if !mediaFile.IsActive {
+			continue
+		}
+		order++
+		category := "Photo"
+		if mediaFile.FileType == "video" {
+			category = "Video"
+		}
+		item := reso.Media{
+			MediaKey:      strconv.FormatUint(uint64(mediaFile.ID), 10),
+			MediaURL:      mediaFile.FileURL,
+			MediaCategory: category,
+			Order:         order,
+		}
+		if mediaFile.Width != nil && mediaFile.Height != nil {
+			item.ImageWidth = *mediaFile.Width
+			item.ImageHeight = *mediaFile.Height
+		}
+		record.Media = append(record.Media, item)
+	}
+	for _, image := range property.Images {
+		order++
+		record.Media = append(record.Media, reso.Media{
+			MediaKey:      fmt.Sprintf("%d-image-%d", property.ID, order),
+			MediaURL:      image,
+			MediaCategory: "Photo",
+			Order:         order,
+		})
+	}
+
+	return record
+}
+
+// toRESOPropertyType maps a PropertyType onto RESO PropertyType/PropertySubType
+func toRESOPropertyType(propertyType models.PropertyType) (string, string) {
+	switch propertyType {
+	case models.PropertyTypeHouse:
+		return "Residential", "Single Family Residence"
+	case models.PropertyTypeCondo:
+		return "Residential", "Condominium"
+	case models.PropertyTypeTownhouse:
+		return "Residential", "Townhouse"
+	case models.PropertyTypeApartment:
+		return "Residential", "Apartment"
+	case models.PropertyTypeLand:
+		return "Land", ""
+	case models.PropertyTypeCommercial:
+		return "Commercial Sale", ""
+	}
+	return "", ""
+}
+
+// toRESOLeaseType maps a RESO sale PropertyType onto its lease counterpart
+func toRESOLeaseType(propertyType string) string {
+	switch propertyType {
+	case "Residential":
+		return "Residential Lease"
+	case "Commercial Sale":
+		return "Commercial Lease"
+	}
+	return propertyType
+}
+
+// toRESOStatus maps a property's status onto RESO StandardStatus
+func toRESOStatus(property *models.Property) string {
+	if property.DeletedAt.Valid {
+		return reso.StatusDelete
+	}
+	if listingHidden(property) {
+		return reso.StatusWithdrawn
+	}
+
+	switch property.Status {
+	case models.PropertyStatusPending:
+		return reso.StatusPending
+	case models.PropertyStatusSold, models.PropertyStatusRented:
+		return reso.StatusClosed
+	default:
+		return reso.StatusActive
+	}
+}
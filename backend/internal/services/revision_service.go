@@ -0,0 +1,326 @@
+package services
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevisionService handles property revision history
+type RevisionService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+}
+
+// NewRevisionService creates a new revision service
+func NewRevisionService(db *gorm.DB, propertyService *PropertyService) *RevisionService {
+	return &RevisionService{db: db, propertyService: propertyService}
+}
+
+// GetRevisions lists a property's revisions, newest first, each with the
+// changes it made to the revision before it
+func (s *RevisionService) GetRevisions(propertyID, agentID uint, req *models.PaginationRequest) (*models.PaginationResponse, error) {
+	if _, err := s.getOwnedProperty(propertyID, agentID); err != nil {
+		return nil, err
+	}
+
+	var total int64
+	query := s.db.Model(&models.PropertyRevision{}).Where("property_id = ?", propertyID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// Load one extra revision so the oldest one on the page can be diffed
+	var revisions []models.PropertyRevision
+	offset := (req.Page - 1) * req.PageSize
+	if err := s.db.Preload("Editor").
+		Where("property_id = ?", propertyID).
+		Order("revision DESC").
+		Offset(offset).Limit(req.PageSize + 1).
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	responses := []models.PropertyRevisionResponse{}
+	for i := 0; i < len(revisions) && i < req.PageSize; i++ {
+		var previous *models.PropertyRevision
+		if i+1 < len(revisions) {
+			previous = &revisions[i+1]
+		}
+		responses = append(responses, *s.toResponse(&revisions[i], previous))
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// GetRevision gets a single revision of a property
+func (s *RevisionService) GetRevision(propertyID uint, revision int, agentID uint) (*models.PropertyRevisionResponse, error) {
+	if _, err := s.getOwnedProperty(propertyID, agentID); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(s.db.Preload("Editor"), propertyID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous models.PropertyRevision
+	err = s.db.Where("property_id = ? AND revision < ?", propertyID, revision).
+		Order("revision DESC").
+		First(&previous).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.toResponse(rev, nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(rev, &previous), nil
+}
+
+// DiffRevisions compares the content of two revisions of a property
+func (s *RevisionService) DiffRevisions(propertyID, agentID uint, req *models.PropertyRevisionDiffRequest) (*models.PropertyRevisionDiff, error) {
+	if _, err := s.getOwnedProperty(propertyID, agentID); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevision(s.db, propertyID, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(s.db, propertyID, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PropertyRevisionDiff{
+		PropertyID:   propertyID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      diffRevisions(from, to),
+	}, nil
+}
+
+// RestoreRevision copies a revision's content back onto the property if it is
+// still at expectedVersion. The restore itself is recorded as a new revision.
+func (s *RevisionService) RestoreRevision(propertyID uint, revision int, agentID uint, expectedVersion int) (*models.PropertyResponse, error) {
+	property, err := s.getOwnedProperty(propertyID, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(s.db, propertyID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &models.PropertyUpdateRequest{
+		Title:       &rev.Title,
+		Description: &rev.Description,
+		Features:    rev.Features,
+		Images:      rev.Images,
+	}
+	if property.Version != expectedVersion {
+		return nil, newVersionConflict(expectedVersion, property.Version, req, property)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, property); err != nil {
+			return err
+		}
+
+		original := *property
+		property.Title = rev.Title
+		property.Description = rev.Description
+		property.Features = rev.Features
+		property.Images = rev.Images
+		property.Version = expectedVersion + 1
+		if property.Description != original.Description {
+			lintListingDescription(property, true)
+		}
+
+		result := tx.Model(property).
+			Where("version = ?", expectedVersion).
+			Select("title", "description", "features", "images", "version", "compliance_status",
+				"compliance_override_by_id", "compliance_override_reason", "compliance_overridden_at").
+			Omit(clause.Associations).
+			Updates(property)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return s.propertyService.currentVersionConflict(propertyID, expectedVersion, req)
+		}
+		if err := moderateListing(tx, s.propertyService.listingRules, property, &original); err != nil {
+			return err
+		}
+		if _, err := refreshQualityScore(tx, propertyID); err != nil {
+			return err
+		}
+
+		return recordRevision(tx, property, models.RevisionActionRestored, &agentID, &rev.Revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.propertyService.GetProperty(propertyID)
+}
+
+// getOwnedProperty loads a property and checks that agentID is its agent
+func (s *RevisionService) getOwnedProperty(propertyID, agentID uint) (*models.Property, error) {
+	var property models.Property
+	if err := s.db.First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return &property, nil
+}
+
+// findRevision loads a revision by its number
+func (s *RevisionService) findRevision(query *gorm.DB, propertyID uint, revision int) (*models.PropertyRevision, error) {
+	var rev models.PropertyRevision
+	if err := query.Where("property_id = ? AND revision = ?", propertyID, revision).First(&rev).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+// toResponse converts a PropertyRevision to PropertyRevisionResponse
+func (s *RevisionService) toResponse(rev *models.PropertyRevision, previous *models.PropertyRevision) *models.PropertyRevisionResponse {
+	response := &models.PropertyRevisionResponse{
+		ID:           rev.ID,
+		PropertyID:   rev.PropertyID,
+		Revision:     rev.Revision,
+		Action:       rev.Action,
+		Title:        rev.Title,
+		Description:  rev.Description,
+		Features:     rev.Features,
+		Images:       rev.Images,
+		RestoredFrom: rev.RestoredFrom,
+		Changes:      []models.RevisionFieldChange{},
+		CreatedAt:    rev.CreatedAt,
+	}
+
+	if previous != nil {
+		response.Changes = diffRevisions(previous, rev)
+	}
+
+	if rev.Editor != nil {
+		response.Editor = &models.UserResponse{
+			ID:        rev.Editor.ID,
+			Email:     rev.Editor.Email,
+			FirstName: rev.Editor.FirstName,
+			LastName:  rev.Editor.LastName,
+			Role:      rev.Editor.Role,
+			Phone:     rev.Editor.Phone,
+			Avatar:    rev.Editor.Avatar,
+			IsActive:  rev.Editor.IsActive,
+			Version:   rev.Editor.Version,
+			CreatedAt: rev.Editor.CreatedAt,
+			UpdatedAt: rev.Editor.UpdatedAt,
+		}
+	}
+
+	return response
+}
+
+// recordRevision snapshots the content of property at its current version
+func recordRevision(tx *gorm.DB, property *models.Property, action models.RevisionAction, editorID *uint, restoredFrom *int) error {
+	return tx.Create(&models.PropertyRevision{
+		PropertyID:   property.ID,
+		Revision:     property.Version,
+		Action:       action,
+		Title:        property.Title,
+		Description:  property.Description,
+		Features:     property.Features,
+		Images:       property.Images,
+		EditorID:     editorID,
+		RestoredFrom: restoredFrom,
+	}).Error
+}
+
+// ensureBaselineRevision snapshots a property that predates revision history
+// before its first tracked change, so the original content can be restored
+func ensureBaselineRevision(tx *gorm.DB, property *models.Property) error {
+	var count int64
+	if err := tx.Model(&models.PropertyRevision{}).Where("property_id = ?", property.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	return recordRevision(tx, property, models.RevisionActionBaseline, nil, nil)
+}
+
+// diffRevisions lists the content fields that differ between two revisions
+func diffRevisions(from, to *models.PropertyRevision) []models.RevisionFieldChange {
+	changes := []models.RevisionFieldChange{}
+
+	if from.Title != to.Title {
+		changes = append(changes, models.RevisionFieldChange{Field: "title", From: from.Title, To: to.Title})
+	}
+	if from.Description != to.Description {
+		changes = append(changes, models.RevisionFieldChange{Field: "description", From: from.Description, To: to.Description})
+	}
+	if change, ok := diffList("features", from.Features, to.Features); ok {
+		changes = append(changes, change)
+	}
+	if change, ok := diffList("images", from.Images, to.Images); ok {
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// diffList compares two list fields, reporting added and removed entries.
+// A pure reordering is reported as a change with no additions or removals.
+func diffList(field string, from, to []string) (models.RevisionFieldChange, bool) {
+	if (len(from) == 0 && len(to) == 0) || reflect.DeepEqual(from, to) {
+		return models.RevisionFieldChange{}, false
+	}
+
+	return models.RevisionFieldChange{
+		Field:   field,
+		From:    from,
+		To:      to,
+		Added:   missingFrom(to, from),
+		Removed: missingFrom(from, to),
+	}, true
+}
+
+// missingFrom returns the values of list that do not appear in other
+func missingFrom(list, other []string) []string {
+	seen := make(map[string]bool, len(other))
+	for _, value := range other {
+		seen[value] = true
+	}
+
+	var missing []string
+	for _, value := range list {
+		if !seen[value] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,803 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"galactavista/pkg/ical"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxSlotRangeDays is the longest date range a slot search may cover
+	maxSlotRangeDays = 14
+	// showingCalendarHistory is how far back the agent showing feed includes past showings
+	showingCalendarHistory = 30 * 24 * time.Hour
+	// localClockFormat is the format of weekly availability times
+	localClockFormat = "15:04"
+	// localDateFormat is the format of agent-local dates
+	localDateFormat = "2006-01-02"
+)
+
+// showingTransitions lists the statuses a showing may move to from each
+// status. A reschedule by either party waits for the agent to confirm again.
+var showingTransitions = map[models.ShowingStatus][]models.ShowingStatus{
+	models.ShowingStatusRequested:   {models.ShowingStatusConfirmed, models.ShowingStatusRescheduled, models.ShowingStatusCancelled},
+	models.ShowingStatusConfirmed:   {models.ShowingStatusRescheduled, models.ShowingStatusCancelled},
+	models.ShowingStatusRescheduled: {models.ShowingStatusConfirmed, models.ShowingStatusRescheduled, models.ShowingStatusCancelled},
+}
+
+// ShowingService handles agent availability and private showing bookings
+type ShowingService struct {
+	db            *gorm.DB
+	notifier      Notifier
+	publicBaseURL string
+}
+
+// NewShowingService creates a new showing service
+func NewShowingService(db *gorm.DB, notifier Notifier, publicBaseURL string) *ShowingService {
+	return &ShowingService{db: db, notifier: notifier, publicBaseURL: strings.TrimRight(publicBaseURL, "/")}
+}
+
+// GetAvailability returns an agent's availability rules and upcoming
+// blackouts, creating default rules on first use
+func (s *ShowingService) GetAvailability(agentID uint) (*models.AgentAvailabilityResponse, error) {
+	availability, err := s.getOrCreateAvailability(s.db, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toAvailabilityResponse(availability)
+}
+
+// UpdateAvailability replaces an agent's availability rules
+func (s *ShowingService) UpdateAvailability(agentID uint, req *models.AgentAvailabilityRequest) (*models.AgentAvailabilityResponse, error) {
+	var fieldErrors []models.FieldError
+	if _, err := loadEventLocation(req.TimeZone); err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "time_zone", Message: "must be an IANA time zone such as America/New_York"})
+	}
+	for i, hours := range req.WeeklyHours {
+		if _, _, err := parseWeeklyHours(hours); err != nil {
+			fieldErrors = append(fieldErrors, models.FieldError{Field: fmt.Sprintf("weekly_hours[%d]", i), Message: err.Error()})
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	availability, err := s.getOrCreateAvailability(s.db, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability.TimeZone = req.TimeZone
+	availability.SlotMinutes = req.SlotMinutes
+	if availability.SlotMinutes == 0 {
+		availability.SlotMinutes = 30
+	}
+	availability.BufferMinutes = req.BufferMinutes
+	availability.NoticeMinutes = req.NoticeMinutes
+	availability.WeeklyHours = req.WeeklyHours
+	if availability.WeeklyHours == nil {
+		availability.WeeklyHours = []models.WeeklyHours{}
+	}
+
+	if err := s.db.Save(availability).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toAvailabilityResponse(availability)
+}
+
+// RegenerateCalendarToken replaces an agent's calendar feed token, revoking
+// the old feed URL
+func (s *ShowingService) RegenerateCalendarToken(agentID uint) (*models.AgentAvailabilityResponse, error) {
+	availability, err := s.getOrCreateAvailability(s.db, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability.CalendarToken = newCalendarToken()
+	if err := s.db.Model(availability).Update("calendar_token", availability.CalendarToken).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toAvailabilityResponse(availability)
+}
+
+// AddBlackout blocks showings on a range of the agent's local dates
+func (s *ShowingService) AddBlackout(agentID uint, req *models.AvailabilityBlackoutRequest) (*models.AvailabilityBlackout, error) {
+	availability, err := s.getOrCreateAvailability(s.db, agentID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := loadEventLocation(availability.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var fieldErrors []models.FieldError
+	startDate, err := time.ParseInLocation(localDateFormat, req.StartDate, loc)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "start_date", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	endDate, err := time.ParseInLocation(localDateFormat, req.EndDate, loc)
+	if err != nil {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "end_date", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	if len(fieldErrors) == 0 && endDate.Before(startDate) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "end_date", Message: "must not be before start_date"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	blackout := models.AvailabilityBlackout{
+		AgentID:   agentID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		StartsAt:  startDate.UTC(),
+		EndsAt:    endDate.AddDate(0, 0, 1).UTC(),
+		Reason:    req.Reason,
+	}
+	if err := s.db.Create(&blackout).Error; err != nil {
+		return nil, err
+	}
+
+	return &blackout, nil
+}
+
+// DeleteBlackout removes one of an agent's blackouts
+func (s *ShowingService) DeleteBlackout(agentID, blackoutID uint) error {
+	result := s.db.Where("id = ? AND agent_id = ?", blackoutID, agentID).Delete(&models.AvailabilityBlackout{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetSlots lists the bookable showing slots for a property viewer may see
+// between two of the agent's local dates, inclusive
+func (s *ShowingService) GetSlots(propertyID uint, viewer ListingViewer, req *models.ShowingSlotsRequest) ([]models.ShowingSlot, error) {
+	var property models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+
+	availability, err := s.findAvailability(s.db, property.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := loadEventLocation(availability.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	today := time.Now().In(loc)
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+	if req.From != nil {
+		from = time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, loc)
+	}
+	to := from.AddDate(0, 0, 6)
+	if req.To != nil {
+		to = time.Date(req.To.Year(), req.To.Month(), req.To.Day(), 0, 0, 0, 0, loc)
+	}
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if to.Sub(from) >= maxSlotRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("date range may span at most %d days", maxSlotRangeDays)
+	}
+
+	return s.availableSlots(s.db, availability, from, to.AddDate(0, 0, 1), 0)
+}
+
+// RequestShowing books a private showing for the viewer in one of the
+// available slots of a listing they may see
+func (s *ShowingService) RequestShowing(propertyID uint, viewer ListingViewer, req *models.ShowingCreateRequest) (*models.ShowingResponse, error) {
+	buyerID := viewer.UserID
+
+	var property models.Property
+	if err := s.db.Scopes(visibleListings(viewer, "properties")).First(&property, propertyID).Error; err != nil {
+		return nil, err
+	}
+	if property.Status != models.PropertyStatusAvailable {
+		return nil, errors.New("listing is not available for showings")
+	}
+
+	var showing models.Showing
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		availability, err := s.lockAvailability(tx, property.AgentID)
+		if err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.Showing{}).
+			Where("property_id = ? AND buyer_id = ? AND status IN ? AND ends_at > ?", propertyID, buyerID, activeShowingStatuses(), time.Now()).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errors.New("you already have an upcoming showing for this property")
+		}
+
+		slot, err := s.findSlot(tx, availability, req.StartsAt, 0)
+		if err != nil {
+			return err
+		}
+
+		showing = models.Showing{
+			PropertyID: propertyID,
+			AgentID:    property.AgentID,
+			BuyerID:    buyerID,
+			StartsAt:   slot.StartsAt,
+			EndsAt:     slot.EndsAt,
+			TimeZone:   availability.TimeZone,
+			Status:     models.ShowingStatusRequested,
+			Message:    req.Message,
+		}
+		return tx.Create(&showing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetShowing(showing.ID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyShowing(property.AgentID, "Showing requested", response)
+	return response, nil
+}
+
+// GetShowings lists the showings of an agent's listings, or a buyer's own
+// showing requests
+func (s *ShowingService) GetShowings(userID uint, asAgent bool, req *models.ShowingListRequest) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Showing{})
+	if asAgent {
+		query = query.Where("agent_id = ?", userID)
+	} else {
+		query = query.Where("buyer_id = ?", userID)
+	}
+	if req.Status != nil {
+		query = query.Where("status = ?", *req.Status)
+	}
+	if req.Upcoming {
+		query = query.Where("ends_at > ?", time.Now())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var showings []models.Showing
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Preload("Property").Preload("Buyer").
+		Order("starts_at").
+		Offset(offset).Limit(req.PageSize).
+		Find(&showings).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.ShowingResponse, len(showings))
+	for i := range showings {
+		responses[i] = *s.toShowingResponse(&showings[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// GetShowing gets a showing visible to its buyer or agent
+func (s *ShowingService) GetShowing(id, userID uint) (*models.ShowingResponse, error) {
+	var showing models.Showing
+	if err := s.db.Preload("Property").Preload("Buyer").First(&showing, id).Error; err != nil {
+		return nil, err
+	}
+	if showing.BuyerID != userID && showing.AgentID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return s.toShowingResponse(&showing), nil
+}
+
+// ConfirmShowing confirms a requested or rescheduled showing (agent only)
+func (s *ShowingService) ConfirmShowing(id, agentID uint) (*models.ShowingResponse, error) {
+	var showing models.Showing
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockShowing(tx, id, &showing); err != nil {
+			return err
+		}
+		if showing.AgentID != agentID {
+			return errors.New("unauthorized")
+		}
+		if err := checkShowingTransition(showing.Status, models.ShowingStatusConfirmed); err != nil {
+			return err
+		}
+		if !showing.StartsAt.After(time.Now()) {
+			return errors.New("showing has already started")
+		}
+
+		return tx.Model(&showing).Update("status", models.ShowingStatusConfirmed).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetShowing(id, agentID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyShowing(showing.BuyerID, "Showing confirmed", response)
+	return response, nil
+}
+
+// RescheduleShowing moves a showing to another available slot. Either the
+// buyer or the agent may reschedule; the showing then awaits confirmation.
+func (s *ShowingService) RescheduleShowing(id, userID uint, req *models.ShowingRescheduleRequest) (*models.ShowingResponse, error) {
+	var showing models.Showing
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var probe models.Showing
+		if err := tx.First(&probe, id).Error; err != nil {
+			return err
+		}
+
+		// Lock the agent's availability before the showing, in the same order
+		// as RequestShowing, so concurrent bookings cannot deadlock
+		availability, err := s.lockAvailability(tx, probe.AgentID)
+		if err != nil {
+			return err
+		}
+		if err := s.lockShowing(tx, id, &showing); err != nil {
+			return err
+		}
+		if showing.BuyerID != userID && showing.AgentID != userID {
+			return errors.New("unauthorized")
+		}
+		if err := checkShowingTransition(showing.Status, models.ShowingStatusRescheduled); err != nil {
+			return err
+		}
+		if !showing.StartsAt.After(time.Now()) {
+			return errors.New("showing has already started")
+		}
+
+		slot, err := s.findSlot(tx, availability, req.StartsAt, showing.ID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"starts_at": slot.StartsAt,
+			"ends_at":   slot.EndsAt,
+			"time_zone": availability.TimeZone,
+			"status":    models.ShowingStatusRescheduled,
+			"sequence":  showing.Sequence + 1,
+		}
+		if req.Message != "" {
+			updates["message"] = req.Message
+		}
+		return tx.Model(&showing).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetShowing(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyShowing(counterparty(&showing, userID), "Showing rescheduled", response)
+	return response, nil
+}
+
+// CancelShowing cancels a showing on behalf of its buyer or agent
+func (s *ShowingService) CancelShowing(id, userID uint, req *models.ShowingCancelRequest) (*models.ShowingResponse, error) {
+	var showing models.Showing
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.lockShowing(tx, id, &showing); err != nil {
+			return err
+		}
+		if showing.BuyerID != userID && showing.AgentID != userID {
+			return errors.New("unauthorized")
+		}
+		if err := checkShowingTransition(showing.Status, models.ShowingStatusCancelled); err != nil {
+			return err
+		}
+		if !showing.EndsAt.After(time.Now()) {
+			return errors.New("showing has already ended")
+		}
+
+		return tx.Model(&showing).Updates(map[string]interface{}{
+			"status":        models.ShowingStatusCancelled,
+			"cancel_reason": req.Reason,
+			"cancelled_by":  userID,
+			"sequence":      showing.Sequence + 1,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.GetShowing(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifyShowing(counterparty(&showing, userID), "Showing cancelled", response)
+	return response, nil
+}
+
+// AgentCalendar builds the iCalendar feed of an agent's showings for the
+// agent owning a calendar token
+func (s *ShowingService) AgentCalendar(token string) (*ical.Calendar, error) {
+	var availability models.AgentAvailability
+	if err := s.db.Where("calendar_token = ?", token).First(&availability).Error; err != nil {
+		return nil, err
+	}
+
+	var showings []models.Showing
+	if err := s.db.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").
+		Where("agent_id = ? AND ends_at > ?", availability.AgentID, time.Now().Add(-showingCalendarHistory)).
+		Order("starts_at").
+		Find(&showings).Error; err != nil {
+		return nil, err
+	}
+
+	host := "galactavista"
+	if base, err := url.Parse(s.publicBaseURL); err == nil && base.Hostname() != "" {
+		host = base.Hostname()
+	}
+
+	calendar := &ical.Calendar{
+		ProdID:   "-//Galactavista//Showings//EN",
+		Name:     "Galactavista showings",
+		TimeZone: availability.TimeZone,
+	}
+	for _, showing := range showings {
+		status := ical.StatusConfirmed
+		switch showing.Status {
+		case models.ShowingStatusRequested, models.ShowingStatusRescheduled:
+			status = ical.StatusTentative
+		case models.ShowingStatusCancelled:
+			status = ical.StatusCancelled
+		}
+
+		description := fmt.Sprintf("Buyer: %s %s <%s>", showing.Buyer.FirstName, showing.Buyer.LastName, showing.Buyer.Email)
+		if showing.Buyer.Phone != "" {
+			description += "\nPhone: " + showing.Buyer.Phone
+		}
+		if showing.Message != "" {
+			description += "\n\n" + showing.Message
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:          fmt.Sprintf("showing-%d@%s", showing.ID, host),
+			Sequence:     showing.Sequence,
+			Start:        showing.StartsAt,
+			End:          showing.EndsAt,
+			Summary:      "Showing: " + showing.Property.Title,
+			Description:  description,
+			Location:     fmt.Sprintf("%s, %s, %s %s", showing.Property.Address, showing.Property.City, showing.Property.State, showing.Property.ZipCode),
+			URL:          fmt.Sprintf("%s/properties/%d", s.publicBaseURL, showing.PropertyID),
+			Status:       status,
+			Created:      showing.CreatedAt,
+			LastModified: showing.UpdatedAt,
+		})
+	}
+
+	return calendar, nil
+}
+
+// availableSlots computes the free slots starting in [from, to). Slots must
+// fall inside the weekly hours, outside blackouts, after the notice period,
+// and at least the buffer away from the agent's other showings and open
+// houses on any of their listings. excludeShowingID ignores one showing,
+// so a showing can be rescheduled next to its current time.
+func (s *ShowingService) availableSlots(tx *gorm.DB, availability *models.AgentAvailability, from, to time.Time, excludeShowingID uint) ([]models.ShowingSlot, error) {
+	loc, err := loadEventLocation(availability.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	slotLength := time.Duration(availability.SlotMinutes) * time.Minute
+	buffer := time.Duration(availability.BufferMinutes) * time.Minute
+	earliest := time.Now().Add(time.Duration(availability.NoticeMinutes) * time.Minute)
+
+	busy, err := s.busyIntervals(tx, availability.AgentID, from.Add(-buffer), to.Add(slotLength+buffer), excludeShowingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var blackouts []models.AvailabilityBlackout
+	if err := tx.Where("agent_id = ? AND starts_at < ? AND ends_at > ?", availability.AgentID, to.Add(slotLength), from).
+		Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+	for _, blackout := range blackouts {
+		busy = append(busy, timeInterval{start: blackout.StartsAt, end: blackout.EndsAt})
+	}
+
+	slots := []models.ShowingSlot{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, hours := range availability.WeeklyHours {
+			if time.Weekday(hours.Weekday) != day.Weekday() {
+				continue
+			}
+			open, close, err := parseWeeklyHours(hours)
+			if err != nil {
+				continue
+			}
+
+			windowStart := time.Date(day.Year(), day.Month(), day.Day(), open.Hour(), open.Minute(), 0, 0, loc)
+			windowEnd := time.Date(day.Year(), day.Month(), day.Day(), close.Hour(), close.Minute(), 0, 0, loc)
+			for start := windowStart; !start.Add(slotLength).After(windowEnd); start = start.Add(slotLength) {
+				end := start.Add(slotLength)
+				if start.Before(earliest) || start.Before(from) || !start.Before(to) {
+					continue
+				}
+				if overlapsAny(busy, start.Add(-buffer), end.Add(buffer)) {
+					continue
+				}
+				slots = append(slots, models.ShowingSlot{
+					StartsAt:   start.UTC(),
+					EndsAt:     end.UTC(),
+					LocalStart: start.Format(time.RFC3339),
+				})
+			}
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].StartsAt.Before(slots[j].StartsAt)
+	})
+	return slots, nil
+}
+
+// busyIntervals returns the agent's active showings and open houses that
+// overlap [from, to)
+func (s *ShowingService) busyIntervals(tx *gorm.DB, agentID uint, from, to time.Time, excludeShowingID uint) ([]timeInterval, error) {
+	var showings []models.Showing
+	if err := tx.Where("agent_id = ? AND id <> ? AND status IN ? AND starts_at < ? AND ends_at > ?",
+		agentID, excludeShowingID, activeShowingStatuses(), to, from).
+		Find(&showings).Error; err != nil {
+		return nil, err
+	}
+
+	var openHouses []models.OpenHouse
+	if err := tx.InnerJoins("Property").
+		Where(`"Property".agent_id = ? AND open_houses.starts_at < ? AND open_houses.ends_at > ?`, agentID, to, from).
+		Find(&openHouses).Error; err != nil {
+		return nil, err
+	}
+
+	busy := make([]timeInterval, 0, len(showings)+len(openHouses))
+	for _, showing := range showings {
+		busy = append(busy, timeInterval{start: showing.StartsAt, end: showing.EndsAt})
+	}
+	for _, openHouse := range openHouses {
+		busy = append(busy, timeInterval{start: openHouse.StartsAt, end: openHouse.EndsAt})
+	}
+	return busy, nil
+}
+
+// findSlot checks that startsAt is currently an available slot
+func (s *ShowingService) findSlot(tx *gorm.DB, availability *models.AgentAvailability, startsAt time.Time, excludeShowingID uint) (*models.ShowingSlot, error) {
+	loc, err := loadEventLocation(availability.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := startsAt.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	slots, err := s.availableSlots(tx, availability, day, day.AddDate(0, 0, 1), excludeShowingID)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.StartsAt.Equal(startsAt) {
+			return &slot, nil
+		}
+	}
+
+	return nil, errors.New("requested time is not available")
+}
+
+// findAvailability loads an agent's availability rules, failing if the
+// agent does not accept showings
+func (s *ShowingService) findAvailability(tx *gorm.DB, agentID uint) (*models.AgentAvailability, error) {
+	var availability models.AgentAvailability
+	err := tx.Where("agent_id = ?", agentID).First(&availability).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && len(availability.WeeklyHours) == 0) {
+		return nil, errors.New("agent is not accepting showings")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &availability, nil
+}
+
+// lockAvailability loads and locks an agent's availability row. Every
+// booking for the agent takes this lock, which serializes slot checks and
+// prevents double-booking.
+func (s *ShowingService) lockAvailability(tx *gorm.DB, agentID uint) (*models.AgentAvailability, error) {
+	return s.findAvailability(tx.Clauses(clause.Locking{Strength: "UPDATE"}), agentID)
+}
+
+// lockShowing loads and locks a showing for the rest of the transaction
+func (s *ShowingService) lockShowing(tx *gorm.DB, id uint, showing *models.Showing) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(showing, id).Error
+}
+
+// getOrCreateAvailability loads an agent's availability, creating default
+// rules with no weekly hours if the agent has none yet
+func (s *ShowingService) getOrCreateAvailability(tx *gorm.DB, agentID uint) (*models.AgentAvailability, error) {
+	availability := models.AgentAvailability{
+		AgentID:       agentID,
+		TimeZone:      "UTC",
+		SlotMinutes:   30,
+		NoticeMinutes: 60,
+		WeeklyHours:   []models.WeeklyHours{},
+		CalendarToken: newCalendarToken(),
+	}
+	if err := tx.Where(models.AgentAvailability{AgentID: agentID}).FirstOrCreate(&availability).Error; err != nil {
+		return nil, err
+	}
+
+	return &availability, nil
+}
+
+// notifyShowing notifies a user about a showing change. Delivery failures
+// are logged and do not fail the booking.
+func (s *ShowingService) notifyShowing(userID uint, subject string, showing *models.ShowingResponse) {
+	var user models.User
+	if err := s.db.First(&user, userID).Error; err != nil {
+		log.Printf("showing %d: failed to load user %d for notification: %v", showing.ID, userID, err)
+		return
+	}
+
+	body := fmt.Sprintf("%s at %s on %s (%s)", showing.PropertyTitle, showing.Address, showing.LocalStart, showing.Status)
+	if err := s.notifier.Notify(Notification{UserID: user.ID, Email: user.Email, Subject: subject, Body: body}); err != nil {
+		log.Printf("showing %d: failed to notify user %d: %v", showing.ID, userID, err)
+	}
+}
+
+// toAvailabilityResponse converts AgentAvailability to AgentAvailabilityResponse
+func (s *ShowingService) toAvailabilityResponse(availability *models.AgentAvailability) (*models.AgentAvailabilityResponse, error) {
+	blackouts := []models.AvailabilityBlackout{}
+	if err := s.db.Where("agent_id = ? AND ends_at > ?", availability.AgentID, time.Now()).
+		Order("starts_at").
+		Find(&blackouts).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.AgentAvailabilityResponse{
+		AgentID:       availability.AgentID,
+		TimeZone:      availability.TimeZone,
+		SlotMinutes:   availability.SlotMinutes,
+		BufferMinutes: availability.BufferMinutes,
+		NoticeMinutes: availability.NoticeMinutes,
+		WeeklyHours:   availability.WeeklyHours,
+		Blackouts:     blackouts,
+		CalendarURL:   "/api/v1/showings/calendar/" + availability.CalendarToken + ".ics",
+		UpdatedAt:     availability.UpdatedAt,
+	}, nil
+}
+
+// toShowingResponse converts Showing to ShowingResponse
+func (s *ShowingService) toShowingResponse(showing *models.Showing) *models.ShowingResponse {
+	loc, err := loadEventLocation(showing.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return &models.ShowingResponse{
+		ID:            showing.ID,
+		PropertyID:    showing.PropertyID,
+		PropertyTitle: showing.Property.Title,
+		Address:       showing.Property.Address,
+		AgentID:       showing.AgentID,
+		Buyer: models.UserResponse{
+			ID:        showing.Buyer.ID,
+			Email:     showing.Buyer.Email,
+			FirstName: showing.Buyer.FirstName,
+			LastName:  showing.Buyer.LastName,
+			Role:      showing.Buyer.Role,
+			Phone:     showing.Buyer.Phone,
+			Avatar:    showing.Buyer.Avatar,
+			IsActive:  showing.Buyer.IsActive,
+			Version:   showing.Buyer.Version,
+			CreatedAt: showing.Buyer.CreatedAt,
+			UpdatedAt: showing.Buyer.UpdatedAt,
+		},
+		StartsAt:     showing.StartsAt.UTC(),
+		EndsAt:       showing.EndsAt.UTC(),
+		LocalStart:   showing.StartsAt.In(loc).Format(time.RFC3339),
+		TimeZone:     showing.TimeZone,
+		Status:       showing.Status,
+		Message:      showing.Message,
+		CancelReason: showing.CancelReason,
+		CancelledBy:  showing.CancelledBy,
+		CreatedAt:    showing.CreatedAt,
+		UpdatedAt:    showing.UpdatedAt,
+	}
+}
+
+// timeInterval is a half-open [start, end) span of time
+type timeInterval struct {
+	start time.Time
+	end   time.Time
+}
+
+// overlapsAny reports whether [start, end) overlaps any of the intervals
+func overlapsAny(intervals []timeInterval, start, end time.Time) bool {
+	for _, interval := range intervals {
+		if start.Before(interval.end) && interval.start.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeeklyHours parses and checks a weekly availability window
+func parseWeeklyHours(hours models.WeeklyHours) (time.Time, time.Time, error) {
+	open, err := time.Parse(localClockFormat, hours.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("start must be a time in HH:MM format")
+	}
+	close, err := time.Parse(localClockFormat, hours.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("end must be a time in HH:MM format")
+	}
+	if !close.After(open) {
+		return time.Time{}, time.Time{}, errors.New("end must be after start")
+	}
+	return open, close, nil
+}
+
+// checkShowingTransition checks that a showing may move between two statuses
+func checkShowingTransition(from, to models.ShowingStatus) error {
+	for _, allowed := range showingTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot change a %s showing to %s", from, to)
+}
+
+// activeShowingStatuses lists the statuses that hold a time slot
+func activeShowingStatuses() []models.ShowingStatus {
+	return []models.ShowingStatus{models.ShowingStatusRequested, models.ShowingStatusConfirmed, models.ShowingStatusRescheduled}
+}
+
+// counterparty returns the other participant of a showing
+func counterparty(showing *models.Showing, userID uint) uint {
+	if userID == showing.AgentID {
+		return showing.BuyerID
+	}
+	return showing.AgentID
+}
+
+// newCalendarToken generates an unguessable calendar feed token
+func newCalendarToken() string {
+	return strings.ReplaceAll(uuid.New().String(), "-", "")
+}
@@ -0,0 +1,532 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"galactavista/internal/models"
+	"galactavista/pkg/reso"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultListingFeedLimit is the page size of the RESO and XML feeds
+	defaultListingFeedLimit = 500
+	// defaultNewsFeedLimit is the number of entries in the RSS and Atom feeds
+	defaultNewsFeedLimit = 50
+)
+
+// alwaysSyndicatedFields are sent regardless of partner field filters so
+// partners can identify records and apply incremental updates
+var alwaysSyndicatedFields = map[string]bool{
+	"ListingKey":            true,
+	"StandardStatus":        true,
+	"ModificationTimestamp": true,
+}
+
+// FeedState describes the current version of a feed for conditional requests
+type FeedState struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// SyndicationService generates outbound listing feeds for partners
+type SyndicationService struct {
+	db            *gorm.DB
+	publicBaseURL string
+}
+
+// NewSyndicationService creates a new syndication service. publicBaseURL is
+// the web app origin used to build listing links.
+func NewSyndicationService(db *gorm.DB, publicBaseURL string) *SyndicationService {
+	return &SyndicationService{db: db, publicBaseURL: strings.TrimRight(publicBaseURL, "/")}
+}
+
+// CreatePartner creates a syndication partner with a new feed key
+func (s *SyndicationService) CreatePartner(req *models.SyndicationPartnerCreateRequest) (*models.SyndicationPartnerResponse, error) {
+	if err := validateSyndicationFields(req.Fields); err != nil {
+		return nil, err
+	}
+
+	partner := models.SyndicationPartner{
+		Name:     req.Name,
+		FeedKey:  strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Fields:   req.Fields,
+		IsActive: true,
+	}
+
+	if err := s.db.Create(&partner).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toPartnerResponse(&partner), nil
+}
+
+// UpdatePartner updates a syndication partner
+func (s *SyndicationService) UpdatePartner(id uint, req *models.SyndicationPartnerUpdateRequest) (*models.SyndicationPartnerResponse, error) {
+	var partner models.SyndicationPartner
+	if err := s.db.First(&partner, id).Error; err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		partner.Name = *req.Name
+	}
+	if req.Fields != nil {
+		if err := validateSyndicationFields(req.Fields); err != nil {
+			return nil, err
+		}
+		partner.Fields = req.Fields
+	}
+	if req.IsActive != nil {
+		partner.IsActive = *req.IsActive
+	}
+
+	if err := s.db.Save(&partner).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toPartnerResponse(&partner), nil
+}
+
+// GetPartners returns all syndication partners
+func (s *SyndicationService) GetPartners() ([]models.SyndicationPartnerResponse, error) {
+	var partners []models.SyndicationPartner
+	if err := s.db.Order("id").Find(&partners).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.SyndicationPartnerResponse, len(partners))
+	for i, partner := range partners {
+		responses[i] = *s.toPartnerResponse(&partner)
+	}
+
+	return responses, nil
+}
+
+// GetPartnerByFeedKey returns the active partner owning a feed key
+func (s *SyndicationService) GetPartnerByFeedKey(feedKey string) (*models.SyndicationPartner, error) {
+	var partner models.SyndicationPartner
+	if err := s.db.Where("feed_key = ? AND is_active = ?", feedKey, true).First(&partner).Error; err != nil {
+		return nil, err
+	}
+
+	return &partner, nil
+}
+
+// GetFeedState returns the ETag and Last-Modified time of a feed without rendering it
+func (s *SyndicationService) GetFeedState(partner *models.SyndicationPartner, format models.FeedFormat, req *models.FeedRequest) (*FeedState, error) {
+	var aggregate struct {
+		Count         int64
+		LastUpdatedAt *time.Time
+		LastDeletedAt *time.Time
+	}
+	err := s.feedQuery(format, req).
+		Select("COUNT(*) AS count, MAX(updated_at) AS last_updated_at, MAX(deleted_at) AS last_deleted_at").
+		Scan(&aggregate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lastModified := partner.UpdatedAt
+	if aggregate.LastUpdatedAt != nil && aggregate.LastUpdatedAt.After(lastModified) {
+		lastModified = *aggregate.LastUpdatedAt
+	}
+	if aggregate.LastDeletedAt != nil && aggregate.LastDeletedAt.After(lastModified) {
+		lastModified = *aggregate.LastDeletedAt
+	}
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	since := ""
+	if req.Since != nil {
+		since = req.Since.UTC().Format(time.RFC3339Nano)
+	}
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%s|%s|%d|%d|%d",
+		partner.ID, partner.UpdatedAt.UnixNano(), format, since, req.Limit, aggregate.Count, lastModified.UnixNano())))
+
+	return &FeedState{
+		ETag:         `"` + hex.EncodeToString(hash[:16]) + `"`,
+		LastModified: lastModified,
+	}, nil
+}
+
+// RenderFeed renders a feed for a partner and returns its body and content type
+func (s *SyndicationService) RenderFeed(partner *models.SyndicationPartner, format models.FeedFormat, req *models.FeedRequest) ([]byte, string, error) {
+	var properties []models.Property
+	query := s.feedQuery(format, req).Preload("Agent").Limit(feedLimit(format, req))
+	if format == models.FeedFormatRSS || format == models.FeedFormatAtom {
+		query = query.Order("created_at DESC")
+	} else {
+		query = query.Order("updated_at ASC, id ASC")
+	}
+	if err := query.Find(&properties).Error; err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case models.FeedFormatRESO:
+		records, err := s.resoRecords(partner, properties)
+		if err != nil {
+			return nil, "", err
+		}
+		body, err := json.Marshal(reso.Page{Context: "$metadata#Property", Value: records})
+		return body, "application/json; charset=utf-8", err
+	case models.FeedFormatXML:
+		records, err := s.resoRecords(partner, properties)
+		if err != nil {
+			return nil, "", err
+		}
+		body, err := marshalXMLDocument(xmlListingFeed{
+			Generated: time.Now().UTC().Format(time.RFC3339),
+			Count:     len(records),
+			Listings:  records,
+		})
+		return body, "application/xml; charset=utf-8", err
+	case models.FeedFormatRSS:
+		body, err := marshalXMLDocument(s.rssFeed(partner, properties))
+		return body, "application/rss+xml; charset=utf-8", err
+	case models.FeedFormatAtom:
+		body, err := marshalXMLDocument(s.atomFeed(partner, properties))
+		return body, "application/atom+xml; charset=utf-8", err
+	default:
+		return nil, "", fmt.Errorf("unsupported feed format %q", format)
+	}
+}
+
+// feedQuery selects the listings of a feed. Without since, the RESO and XML
+// feeds contain every published listing; with since they contain every
+// listing changed or removed after that time so partners can apply deltas.
+// The RSS and Atom feeds only ever contain newly published listings.
+func (s *SyndicationService) feedQuery(format models.FeedFormat, req *models.FeedRequest) *gorm.DB {
+	query := s.db.Model(&models.Property{})
+
+	if format == models.FeedFormatRSS || format == models.FeedFormatAtom {
+		query = publishedListings(query)
+		if req.Since != nil {
+			query = query.Where("created_at > ?", *req.Since)
+		}
+		return query
+	}
+
+	if req.Since == nil {
+		return publishedListings(query)
+	}
+
+	return query.Unscoped().Where("updated_at > ? OR deleted_at > ?", *req.Since, *req.Since)
+}
+
+// resoRecords converts properties to RESO records filtered by the partner's
+// fields. Deleted and hidden listings only appear in delta feeds, as bare
+// records telling partners to take them down.
+func (s *SyndicationService) resoRecords(partner *models.SyndicationPartner, properties []models.Property) ([]reso.Property, error) {
+	var shown []models.Property
+	for i := range properties {
+		if !properties[i].DeletedAt.Valid && !listingHidden(&properties[i]) {
+			shown = append(shown, properties[i])
+		}
+	}
+	mediaByProperty, err := s.loadMedia(shown)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]reso.Property, len(properties))
+	for i := range properties {
+		property := &properties[i]
+		if property.DeletedAt.Valid || listingHidden(property) {
+			records[i] = reso.Property{
+				ListingKey:            strconv.FormatUint(uint64(property.ID), 10),
+				StandardStatus:        toRESOStatus(property),
+				ModificationTimestamp: property.UpdatedAt.UTC(),
+			}
+			if property.DeletedAt.Valid && property.DeletedAt.Time.After(property.UpdatedAt) {
+				records[i].ModificationTimestamp = property.DeletedAt.Time.UTC()
+			}
+			continue
+		}
+		records[i] = toRESOProperty(property, mediaByProperty[property.ID])
+		filterRESOFields(&records[i], partner.Fields)
+	}
+
+	return records, nil
+}
+
+// loadMedia loads the active media of the given properties
+func (s *SyndicationService) loadMedia(properties []models.Property) (map[uint][]models.MediaFile, error) {
+	ids := make([]uint, len(properties))
+	for i, property := range properties {
+		ids[i] = property.ID
+	}
+
+	var mediaFiles []models.MediaFile
+	if len(ids) > 0 {
+		if err := visibleMedia(s.db).Where("property_id IN ? AND is_active = ?", ids, true).
+			Order("sort_order, id").Find(&mediaFiles).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	byProperty := make(map[uint][]models.MediaFile)
+	for _, mediaFile := range mediaFiles {
+		byProperty[mediaFile.PropertyID] = append(byProperty[mediaFile.PropertyID], mediaFile)
+	}
+
+	return byProperty, nil
+}
+
+// rssFeed builds an RSS 2.0 feed of new listings
+func (s *SyndicationService) rssFeed(partner *models.SyndicationPartner, properties []models.Property) rssDocument {
+	channel := rssChannel{
+		Title:         "Galactavista new listings",
+		Link:          s.publicBaseURL + "/properties",
+		Description:   "Newly published listings for " + partner.Name,
+		LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+	}
+
+	for i := range properties {
+		property := &properties[i]
+		link := s.listingURL(property)
+		channel.Items = append(channel.Items, rssItem{
+			Title:       listingHeadline(property),
+			Link:        link,
+			Description: listingSummary(property, partner.Fields),
+			PubDate:     property.CreatedAt.UTC().Format(time.RFC1123Z),
+			GUID:        rssGUID{Value: link, IsPermaLink: "true"},
+		})
+	}
+
+	return rssDocument{Version: "2.0", Channel: channel}
+}
+
+// atomFeed builds an Atom feed of new listings
+func (s *SyndicationService) atomFeed(partner *models.SyndicationPartner, properties []models.Property) atomDocument {
+	updated := partner.UpdatedAt
+	for _, property := range properties {
+		if property.UpdatedAt.After(updated) {
+			updated = property.UpdatedAt
+		}
+	}
+
+	feed := atomDocument{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		ID:      s.publicBaseURL + "/feeds/" + strconv.FormatUint(uint64(partner.ID), 10),
+		Title:   "Galactavista new listings",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: s.publicBaseURL + "/properties", Rel: "alternate"},
+	}
+
+	for i := range properties {
+		property := &properties[i]
+		link := s.listingURL(property)
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        link,
+			Title:     listingHeadline(property),
+			Link:      atomLink{Href: link, Rel: "alternate"},
+			Published: property.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   property.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary:   listingSummary(property, partner.Fields),
+		})
+	}
+
+	return feed
+}
+
+func (s *SyndicationService) listingURL(property *models.Property) string {
+	return s.publicBaseURL + "/properties/" + strconv.FormatUint(uint64(property.ID), 10)
+}
+
+// toPartnerResponse converts SyndicationPartner to SyndicationPartnerResponse
+func (s *SyndicationService) toPartnerResponse(partner *models.SyndicationPartner) *models.SyndicationPartnerResponse {
+	return &models.SyndicationPartnerResponse{
+		ID:        partner.ID,
+		Name:      partner.Name,
+		FeedKey:   partner.FeedKey,
+		Fields:    partner.Fields,
+		IsActive:  partner.IsActive,
+		CreatedAt: partner.CreatedAt,
+		UpdatedAt: partner.UpdatedAt,
+	}
+}
+
+// publishedListings restricts a property query to publicly visible listings
+func publishedListings(query *gorm.DB) *gorm.DB {
+	return publicListings(query).
+		Where("status IN ?", []models.PropertyStatus{models.PropertyStatusAvailable, models.PropertyStatusPending})
+}
+
+// filterRESOFields clears every field of a RESO record that is not allowed
+func filterRESOFields(record *reso.Property, allowed []string) {
+	if len(allowed) == 0 {
+		return
+	}
+
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, field := range allowed {
+		allowedSet[field] = true
+	}
+
+	value := reflect.ValueOf(record).Elem()
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := resoFieldName(t.Field(i))
+		if !allowedSet[name] && !alwaysSyndicatedFields[name] {
+			value.Field(i).Set(reflect.Zero(t.Field(i).Type))
+		}
+	}
+}
+
+// validateSyndicationFields rejects field names that are not RESO fields we publish
+func validateSyndicationFields(fields []string) error {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(reso.Property{})
+	for i := 0; i < t.NumField(); i++ {
+		known[resoFieldName(t.Field(i))] = true
+	}
+
+	var unknown []string
+	for _, field := range fields {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		return errors.New("unknown RESO fields: " + strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+func resoFieldName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+func fieldAllowed(allowed []string, field string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, name := range allowed {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+func feedLimit(format models.FeedFormat, req *models.FeedRequest) int {
+	if req.Limit > 0 {
+		return req.Limit
+	}
+	if format == models.FeedFormatRSS || format == models.FeedFormatAtom {
+		return defaultNewsFeedLimit
+	}
+	return defaultListingFeedLimit
+}
+
+// listingHeadline builds a one-line title for news feed entries
+func listingHeadline(property *models.Property) string {
+	return fmt.Sprintf("%s - %s, %s", property.Title, property.City, property.State)
+}
+
+// listingSummary builds a short text summary honouring the partner's field filter
+func listingSummary(property *models.Property, allowed []string) string {
+	var parts []string
+	if fieldAllowed(allowed, "ListPrice") && property.MonthlyRent != nil {
+		parts = append(parts, fmt.Sprintf("$%.0f/mo", *property.MonthlyRent))
+	} else if fieldAllowed(allowed, "ListPrice") && property.Price > 0 {
+		parts = append(parts, fmt.Sprintf("$%.0f", property.Price))
+	}
+	if fieldAllowed(allowed, "BedroomsTotal") && property.Bedrooms > 0 {
+		parts = append(parts, fmt.Sprintf("%d bd", property.Bedrooms))
+	}
+	if fieldAllowed(allowed, "BathroomsTotalInteger") && property.Bathrooms > 0 {
+		parts = append(parts, fmt.Sprintf("%g ba", property.Bathrooms))
+	}
+	if fieldAllowed(allowed, "LivingArea") && property.SquareFeet > 0 {
+		parts = append(parts, fmt.Sprintf("%d sqft", property.SquareFeet))
+	}
+
+	summary := strings.Join(parts, " | ")
+	if fieldAllowed(allowed, "PublicRemarks") && property.Description != "" {
+		if summary != "" {
+			summary += ". "
+		}
+		summary += property.Description
+	}
+	return summary
+}
+
+func marshalXMLDocument(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type xmlListingFeed struct {
+	XMLName   xml.Name        `xml:"Listings"`
+	Generated string          `xml:"generated,attr"`
+	Count     int             `xml:"count,attr"`
+	Listings  []reso.Property `xml:"Listing"`
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+type atomDocument struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Link      atomLink `xml:"link"`
+	Published string   `xml:"published"`
+	Updated   string   `xml:"updated"`
+	Summary   string   `xml:"summary"`
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"galactavista/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultClosingPeriod is how long after acceptance closing is scheduled
+	// when the offer names no closing date
+	defaultClosingPeriod = 45 * 24 * time.Hour
+	// inspectionPeriod and appraisalPeriod are the default milestone due
+	// dates after acceptance
+	inspectionPeriod = 10 * 24 * time.Hour
+	appraisalPeriod  = 21 * 24 * time.Hour
+	financingPeriod  = 30 * 24 * time.Hour
+)
+
+// TransactionService handles transactions and their milestone checklists
+type TransactionService struct {
+	db       *gorm.DB
+	notifier Notifier
+}
+
+// NewTransactionService creates a new transaction service
+func NewTransactionService(db *gorm.DB, notifier Notifier) *TransactionService {
+	return &TransactionService{db: db, notifier: notifier}
+}
+
+// GetTransactions lists the transactions a user is a party to
+func (s *TransactionService) GetTransactions(userID uint, req *models.TransactionListRequest) (*models.PaginationResponse, error) {
+	query := s.db.Model(&models.Transaction{}).Where("buyer_id = ? OR agent_id = ?", userID, userID)
+	if req.Status != nil {
+		query = query.Where("status = ?", *req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var transactions []models.Transaction
+	offset := (req.Page - 1) * req.PageSize
+	if err := s.preloadTransaction(query).
+		Order("created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.TransactionResponse, len(transactions))
+	for i := range transactions {
+		responses[i] = *toTransactionResponse(&transactions[i])
+	}
+
+	totalPages := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		Data:       responses,
+	}, nil
+}
+
+// GetTransaction gets a transaction with its milestones
+func (s *TransactionService) GetTransaction(id, userID uint) (*models.TransactionResponse, error) {
+	var transaction models.Transaction
+	if err := s.preloadTransaction(s.db).First(&transaction, id).Error; err != nil {
+		return nil, err
+	}
+	if transaction.BuyerID != userID && transaction.AgentID != userID {
+		return nil, errors.New("unauthorized")
+	}
+
+	return toTransactionResponse(&transaction), nil
+}
+
+// AddMilestone adds a custom milestone to an open transaction
+func (s *TransactionService) AddMilestone(id, userID uint, req *models.MilestoneCreateRequest) (*models.TransactionResponse, error) {
+	dueDate, err := parseOptionalDate(req.DueDate)
+	if err != nil {
+		return nil, &ValidationError{Errors: []models.FieldError{{Field: "due_date", Message: "must be a date in YYYY-MM-DD format"}}}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		transaction, err := lockOpenTransaction(tx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		var sortOrder int
+		if err := tx.Model(&models.TransactionMilestone{}).
+			Where("transaction_id = ?", transaction.ID).
+			Select("COALESCE(MAX(sort_order), 0)").
+			Scan(&sortOrder).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.TransactionMilestone{
+			TransactionID: transaction.ID,
+			Type:          models.MilestoneCustom,
+			Title:         req.Title,
+			DueDate:       dueDate,
+			SortOrder:     sortOrder + 1,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTransaction(id, userID)
+}
+
+// UpdateMilestone changes a milestone's due date or marks it complete or
+// incomplete
+func (s *TransactionService) UpdateMilestone(id, milestoneID, userID uint, req *models.MilestoneUpdateRequest) (*models.TransactionResponse, error) {
+	updates := map[string]interface{}{}
+	if req.DueDate != nil {
+		dueDate, err := parseOptionalDate(*req.DueDate)
+		if err != nil {
+			return nil, &ValidationError{Errors: []models.FieldError{{Field: "due_date", Message: "must be a date in YYYY-MM-DD format"}}}
+		}
+		updates["due_date"] = dueDate
+	}
+	if req.Completed != nil {
+		if *req.Completed {
+			updates["completed_at"] = time.Now()
+			updates["completed_by"] = userID
+		} else {
+			updates["completed_at"] = nil
+			updates["completed_by"] = nil
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockOpenTransaction(tx, id, userID); err != nil {
+			return err
+		}
+
+		var milestone models.TransactionMilestone
+		if err := tx.Where("id = ? AND transaction_id = ?", milestoneID, id).First(&milestone).Error; err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&milestone).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTransaction(id, userID)
+}
+
+// CloseTransaction closes a transaction once every milestone is complete
+// and marks the listing sold (agent only)
+func (s *TransactionService) CloseTransaction(id, agentID uint) (*models.TransactionResponse, error) {
+	var transaction *models.Transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transaction, err = lockOpenTransaction(tx, id, agentID)
+		if err != nil {
+			return err
+		}
+		if transaction.AgentID != agentID {
+			return errors.New("unauthorized")
+		}
+
+		var pending int64
+		if err := tx.Model(&models.TransactionMilestone{}).
+			Where("transaction_id = ? AND completed_at IS NULL", transaction.ID).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d milestone(s) are still incomplete", pending)
+		}
+
+		if err := changePropertyStatus(tx, transaction.PropertyID, models.PropertyStatusSold); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Property{}).Where("id = ?", transaction.PropertyID).
+			Update("sold_price", transaction.Price).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(transaction).Updates(map[string]interface{}{
+			"status":    models.TransactionStatusClosed,
+			"closed_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyUser(s.db, s.notifier, transaction.BuyerID, "Transaction closed",
+		fmt.Sprintf("Transaction #%d has closed. Congratulations!", transaction.ID))
+
+	return s.GetTransaction(id, agentID)
+}
+
+// CancelTransaction cancels a transaction that fell through and returns the
+// listing to available (agent only)
+func (s *TransactionService) CancelTransaction(id, agentID uint) (*models.TransactionResponse, error) {
+	var transaction *models.Transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transaction, err = lockOpenTransaction(tx, id, agentID)
+		if err != nil {
+			return err
+		}
+		if transaction.AgentID != agentID {
+			return errors.New("unauthorized")
+		}
+
+		if err := changePropertyStatus(tx, transaction.PropertyID, models.PropertyStatusAvailable); err != nil {
+			return err
+		}
+
+		return tx.Model(transaction).Update("status", models.TransactionStatusCancelled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyUser(s.db, s.notifier, transaction.BuyerID, "Transaction cancelled",
+		fmt.Sprintf("Transaction #%d was cancelled.", transaction.ID))
+
+	return s.GetTransaction(id, agentID)
+}
+
+// preloadTransaction preloads the associations shown in transaction responses
+func (s *TransactionService) preloadTransaction(query *gorm.DB) *gorm.DB {
+	return query.Preload("Property", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Buyer").
+		Preload("Milestones", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") })
+}
+
+// openTransaction opens the transaction of an accepted offer with its
+// default milestones
+func openTransaction(tx *gorm.DB, offer *models.Offer) error {
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	closingDate := now.Add(defaultClosingPeriod)
+	if offer.ClosingDate != nil {
+		closingDate = *offer.ClosingDate
+	}
+
+	dueBy := func(period time.Duration) *time.Time {
+		due := now.Add(period)
+		if due.After(closingDate) {
+			due = closingDate
+		}
+		return &due
+	}
+
+	milestones := []models.TransactionMilestone{
+		{Type: models.MilestoneInspection, Title: "Home inspection", DueDate: dueBy(inspectionPeriod)},
+		{Type: models.MilestoneAppraisal, Title: "Appraisal", DueDate: dueBy(appraisalPeriod)},
+	}
+	if hasContingency(offer, models.ContingencyFinancing) {
+		milestones = append(milestones, models.TransactionMilestone{
+			Type: models.MilestoneFinancing, Title: "Loan approval", DueDate: dueBy(financingPeriod),
+		})
+	}
+	milestones = append(milestones, models.TransactionMilestone{
+		Type: models.MilestoneClosing, Title: "Closing", DueDate: &closingDate,
+	})
+	for i := range milestones {
+		milestones[i].SortOrder = i + 1
+	}
+
+	transaction := models.Transaction{
+		PropertyID:  offer.PropertyID,
+		OfferID:     offer.ID,
+		BuyerID:     offer.BuyerID,
+		AgentID:     offer.AgentID,
+		Price:       offer.Amount,
+		ClosingDate: &closingDate,
+		Status:      models.TransactionStatusOpen,
+		Milestones:  milestones,
+	}
+	return tx.Create(&transaction).Error
+}
+
+// lockOpenTransaction locks an open transaction userID is a party to
+func lockOpenTransaction(tx *gorm.DB, id, userID uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, id).Error; err != nil {
+		return nil, err
+	}
+	if transaction.BuyerID != userID && transaction.AgentID != userID {
+		return nil, errors.New("unauthorized")
+	}
+	if transaction.Status != models.TransactionStatusOpen {
+		return nil, fmt.Errorf("transaction is %s", transaction.Status)
+	}
+
+	return &transaction, nil
+}
+
+// toTransactionResponse converts Transaction to TransactionResponse
+func toTransactionResponse(transaction *models.Transaction) *models.TransactionResponse {
+	milestones := transaction.Milestones
+	if milestones == nil {
+		milestones = []models.TransactionMilestone{}
+	}
+
+	return &models.TransactionResponse{
+		ID:            transaction.ID,
+		PropertyID:    transaction.PropertyID,
+		PropertyTitle: transaction.Property.Title,
+		Address:       transaction.Property.Address,
+		OfferID:       transaction.OfferID,
+		BuyerID:       transaction.BuyerID,
+		BuyerName:     strings.TrimSpace(transaction.Buyer.FirstName + " " + transaction.Buyer.LastName),
+		AgentID:       transaction.AgentID,
+		Price:         transaction.Price,
+		ClosingDate:   transaction.ClosingDate,
+		Status:        transaction.Status,
+		Milestones:    milestones,
+		ClosedAt:      transaction.ClosedAt,
+		CreatedAt:     transaction.CreatedAt,
+		UpdatedAt:     transaction.UpdatedAt,
+	}
+}
@@ -0,0 +1,516 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"galactavista/internal/models"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashService handles soft-deleted listings, media and VR tours
+type TrashService struct {
+	db              *gorm.DB
+	propertyService *PropertyService
+	retention       time.Duration
+}
+
+// NewTrashService creates a new trash service. Items are purged by the
+// retention job once they have been deleted for longer than retention;
+// a zero retention keeps them until an admin purges them.
+func NewTrashService(db *gorm.DB, propertyService *PropertyService, retention time.Duration) *TrashService {
+	return &TrashService{db: db, propertyService: propertyService, retention: retention}
+}
+
+// GetTrash lists an agent's deleted items, most recently deleted first.
+// Media and tours removed along with their listing are restored with it and
+// are not listed separately.
+func (s *TrashService) GetTrash(agentID uint, req *models.TrashRequest) (*models.PaginationResponse, error) {
+	items := []models.TrashItem{}
+
+	if req.Type == "" || req.Type == models.TrashItemProperty {
+		var properties []models.Property
+		if err := s.db.Unscoped().
+			Where("agent_id = ? AND deleted_at IS NOT NULL AND purged_at IS NULL", agentID).
+			Find(&properties).Error; err != nil {
+			return nil, err
+		}
+		for _, property := range properties {
+			items = append(items, s.trashItem(models.TrashItemProperty, property.ID, property.ID, property.Title, property.DeletedAt))
+		}
+	}
+
+	if req.Type == "" || req.Type == models.TrashItemMedia {
+		var mediaFiles []models.MediaFile
+		if err := s.db.Unscoped().
+			Joins("JOIN properties ON properties.id = media_files.property_id AND properties.deleted_at IS NULL").
+			Where("properties.agent_id = ? AND media_files.deleted_at IS NOT NULL", agentID).
+			Find(&mediaFiles).Error; err != nil {
+			return nil, err
+		}
+		for _, mediaFile := range mediaFiles {
+			items = append(items, s.trashItem(models.TrashItemMedia, mediaFile.ID, mediaFile.PropertyID, mediaFile.FileName, mediaFile.DeletedAt))
+		}
+	}
+
+	if req.Type == "" || req.Type == models.TrashItemVRTour {
+		var tours []models.VRTour
+		if err := s.db.Unscoped().
+			Joins("JOIN properties ON properties.id = vr_tours.property_id AND properties.deleted_at IS NULL").
+			Where("properties.agent_id = ? AND vr_tours.deleted_at IS NOT NULL", agentID).
+			Find(&tours).Error; err != nil {
+			return nil, err
+		}
+		for _, tour := range tours {
+			items = append(items, s.trashItem(models.TrashItemVRTour, tour.ID, tour.PropertyID, tour.Title, tour.DeletedAt))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	total := len(items)
+	start := (req.Page - 1) * req.PageSize
+	if start > total {
+		start = total
+	}
+	end := start + req.PageSize
+	if end > total {
+		end = total
+	}
+
+	return &models.PaginationResponse{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Total:      int64(total),
+		TotalPages: (total + req.PageSize - 1) / req.PageSize,
+		Data:       items[start:end],
+	}, nil
+}
+
+// RestoreProperty restores a deleted property together with the media and
+// tours that were deleted with it
+func (s *TrashService) RestoreProperty(id, agentID uint) (*models.PropertyResponse, error) {
+	var property models.Property
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL AND purged_at IS NULL").First(&property, id).Error; err != nil {
+		return nil, err
+	}
+
+	if property.AgentID != agentID {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := restoreDeletedChildren(tx, property.ID, property.DeletedAt.Time); err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&property).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.propertyService.GetProperty(property.ID)
+}
+
+// RestoreMediaFile restores a deleted media file of a live property
+func (s *TrashService) RestoreMediaFile(id, agentID uint) error {
+	var mediaFile models.MediaFile
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(&mediaFile, id).Error; err != nil {
+		return err
+	}
+
+	if err := s.checkParentProperty(mediaFile.PropertyID, agentID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&mediaFile).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		_, err := refreshQualityScore(tx, mediaFile.PropertyID)
+		return err
+	})
+}
+
+// RestoreVRTour restores a deleted VR tour of a live property
+func (s *TrashService) RestoreVRTour(id, agentID uint) error {
+	var tour models.VRTour
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(&tour, id).Error; err != nil {
+		return err
+	}
+
+	if err := s.checkParentProperty(tour.PropertyID, agentID); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&tour).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		_, err := refreshQualityScore(tx, tour.PropertyID)
+		return err
+	})
+}
+
+// PurgeProperty permanently deletes a property in the trash along with its
+// media, tours, open houses, revisions and uploaded files, keeping its
+// business records as purgeProperties describes
+func (s *TrashService) PurgeProperty(id uint) (*models.TrashPurgeResult, error) {
+	var property models.Property
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL AND purged_at IS NULL").First(&property, id).Error; err != nil {
+		return nil, err
+	}
+
+	result := &models.TrashPurgeResult{}
+	if err := s.purgeProperties([]uint{property.ID}, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PurgeMediaFile permanently deletes a media file in the trash and its upload
+func (s *TrashService) PurgeMediaFile(id uint) (*models.TrashPurgeResult, error) {
+	var mediaFile models.MediaFile
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(&mediaFile, id).Error; err != nil {
+		return nil, err
+	}
+
+	result := &models.TrashPurgeResult{}
+	if err := s.purgeMediaFiles([]models.MediaFile{mediaFile}, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PurgeVRTour permanently deletes a VR tour in the trash
+func (s *TrashService) PurgeVRTour(id uint) (*models.TrashPurgeResult, error) {
+	var tour models.VRTour
+	if err := s.db.Unscoped().Where("deleted_at IS NOT NULL").First(&tour, id).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Unscoped().Delete(&tour).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.TrashPurgeResult{VRTours: 1}, nil
+}
+
+// PurgeExpired permanently deletes every item that has been in the trash
+// for longer than the retention period
+func (s *TrashService) PurgeExpired() (*models.TrashPurgeResult, error) {
+	result := &models.TrashPurgeResult{}
+	if s.retention <= 0 {
+		return result, nil
+	}
+	cutoff := time.Now().Add(-s.retention)
+
+	var propertyIDs []uint
+	if err := s.db.Unscoped().Model(&models.Property{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND purged_at IS NULL", cutoff).
+		Pluck("id", &propertyIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := s.purgeProperties(propertyIDs, result); err != nil {
+		return nil, err
+	}
+
+	var mediaFiles []models.MediaFile
+	if err := s.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Find(&mediaFiles).Error; err != nil {
+		return nil, err
+	}
+	if err := s.purgeMediaFiles(mediaFiles, result); err != nil {
+		return nil, err
+	}
+
+	tours := s.db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&models.VRTour{})
+	if tours.Error != nil {
+		return nil, tours.Error
+	}
+	result.VRTours += int(tours.RowsAffected)
+
+	return result, nil
+}
+
+// RunRetention purges expired trash every interval until ctx is done
+func (s *TrashService) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := s.PurgeExpired()
+			if err != nil {
+				log.Printf("trash retention: purge failed: %v", err)
+				continue
+			}
+			log.Printf("trash retention: purged properties=%d media=%d tours=%d revisions=%d files=%d",
+				result.Properties, result.MediaFiles, result.VRTours, result.Revisions, result.FilesFreed)
+		}
+	}
+}
+
+// purgeProperties permanently deletes properties in the trash with their
+// media, tours, open houses, revisions, analytics events and other listing
+// content. Uploaded files are removed only after the records are gone so a
+// failed transaction never leaves records pointing at missing files.
+//
+// Offers, transactions, leads, conversations and showings are business
+// records and are kept: a property still referenced by any of them stays
+// behind as a purged row stripped of its content, so those records keep a
+// listing to point at. Properties with an open transaction are not purged
+// at all.
+func (s *TrashService) purgeProperties(ids []uint, result *models.TrashPurgeResult) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var mediaFiles []models.MediaFile
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var open []uint
+		if err := tx.Model(&models.Transaction{}).
+			Where("property_id IN ? AND status = ?", ids, models.TransactionStatusOpen).
+			Distinct().Pluck("property_id", &open).Error; err != nil {
+			return err
+		}
+		ids = excludeIDs(ids, open)
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Where("property_id IN ?", ids).Find(&mediaFiles).Error; err != nil {
+			return err
+		}
+
+		media := tx.Unscoped().Where("property_id IN ?", ids).Delete(&models.MediaFile{})
+		if media.Error != nil {
+			return media.Error
+		}
+		tours := tx.Unscoped().Where("property_id IN ?", ids).Delete(&models.VRTour{})
+		if tours.Error != nil {
+			return tours.Error
+		}
+		if err := tx.Where("open_house_id IN (?)", tx.Unscoped().Model(&models.OpenHouse{}).Select("id").Where("property_id IN ?", ids)).
+			Delete(&models.OpenHouseRSVP{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("property_id IN ?", ids).Delete(&models.OpenHouse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("property_id IN ?", ids).Delete(&models.PropertyAttribute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("property_id IN ? OR duplicate_of_id IN ?", ids, ids).Delete(&models.DuplicateCandidate{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("property_id IN ?", ids).Delete(&models.ModerationItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("property_id IN ?", ids).Delete(&models.ListingEvent{}).Error; err != nil {
+			return err
+		}
+		revisions := tx.Where("property_id IN ?", ids).Delete(&models.PropertyRevision{})
+		if revisions.Error != nil {
+			return revisions.Error
+		}
+
+		var referenced []uint
+		for _, record := range []interface{}{
+			&models.Offer{}, &models.Transaction{}, &models.Lead{},
+			&models.Conversation{}, &models.Showing{},
+		} {
+			var found []uint
+			if err := tx.Model(record).Where("property_id IN ?", ids).Distinct().Pluck("property_id", &found).Error; err != nil {
+				return err
+			}
+			referenced = append(referenced, found...)
+		}
+
+		kept := tx.Unscoped().Model(&models.Property{}).
+			Where("id IN ?", referenced).
+			Updates(purgedPropertyContent())
+		if kept.Error != nil {
+			return kept.Error
+		}
+		removed := excludeIDs(ids, referenced)
+		properties := tx.Unscoped().Where("id IN ?", removed).Delete(&models.Property{})
+		if properties.Error != nil {
+			return properties.Error
+		}
+
+		result.MediaFiles += int(media.RowsAffected)
+		result.VRTours += int(tours.RowsAffected)
+		result.Revisions += int(revisions.RowsAffected)
+		result.Properties += int(kept.RowsAffected + properties.RowsAffected)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result.FilesFreed += removeUploadedFiles(mediaFiles)
+	return nil
+}
+
+// purgedPropertyContent is the update that turns a property into a purged
+// row: its descriptive content, location and media links are cleared and
+// only the keys, type, status and prices business records report on stay
+func purgedPropertyContent() map[string]interface{} {
+	return map[string]interface{}{
+		"purged_at":      time.Now(),
+		"title":          "Deleted listing",
+		"description":    "",
+		"address":        "",
+		"city":           "",
+		"state":          "",
+		"zip_code":       "",
+		"address_key":    "",
+		"unit_number":    "",
+		"latitude":       nil,
+		"longitude":      nil,
+		"features":       nil,
+		"images":         nil,
+		"vr_model_url":   "",
+		"development_id": nil,
+	}
+}
+
+// purgeMediaFiles hard-deletes media records and then their uploaded files
+func (s *TrashService) purgeMediaFiles(mediaFiles []models.MediaFile, result *models.TrashPurgeResult) error {
+	if len(mediaFiles) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(mediaFiles))
+	for i, mediaFile := range mediaFiles {
+		ids[i] = mediaFile.ID
+	}
+
+	if err := s.db.Where("media_file_id IN ?", ids).Delete(&models.ModerationItem{}).Error; err != nil {
+		return err
+	}
+	deleted := s.db.Unscoped().Where("id IN ?", ids).Delete(&models.MediaFile{})
+	if deleted.Error != nil {
+		return deleted.Error
+	}
+
+	result.MediaFiles += int(deleted.RowsAffected)
+	result.FilesFreed += removeUploadedFiles(mediaFiles)
+	return nil
+}
+
+// checkParentProperty checks that a property is live and belongs to agentID
+func (s *TrashService) checkParentProperty(propertyID, agentID uint) error {
+	var property models.Property
+	if err := s.db.Unscoped().First(&property, propertyID).Error; err != nil {
+		return err
+	}
+
+	if property.AgentID != agentID {
+		return errors.New("unauthorized")
+	}
+	if property.DeletedAt.Valid {
+		return errors.New("property is in the trash; restore the property first")
+	}
+
+	return nil
+}
+
+// trashItem builds a trash listing entry
+func (s *TrashService) trashItem(itemType models.TrashItemType, id, propertyID uint, title string, deletedAt gorm.DeletedAt) models.TrashItem {
+	item := models.TrashItem{
+		Type:       itemType,
+		ID:         id,
+		PropertyID: propertyID,
+		Title:      title,
+		DeletedAt:  deletedAt.Time,
+	}
+
+	if s.retention > 0 {
+		purgeAt := deletedAt.Time.Add(s.retention)
+		item.PurgeAt = &purgeAt
+	}
+
+	return item
+}
+
+// softDeleteProperty moves a property and its media and tours to the trash.
+// The children are deleted after the property so restoreDeletedChildren can
+// tell them apart from items that were already in the trash.
+func softDeleteProperty(tx *gorm.DB, property *models.Property) error {
+	if err := tx.Delete(property).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("property_id = ?", property.ID).Delete(&models.MediaFile{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("property_id = ?", property.ID).Delete(&models.VRTour{}).Error
+}
+
+// restoreDeletedChildren restores the media and tours of a property that
+// were deleted at or after the property itself was
+func restoreDeletedChildren(tx *gorm.DB, propertyID uint, deletedAt time.Time) error {
+	if err := tx.Unscoped().Model(&models.MediaFile{}).
+		Where("property_id = ? AND deleted_at >= ?", propertyID, deletedAt).
+		Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&models.VRTour{}).
+		Where("property_id = ? AND deleted_at >= ?", propertyID, deletedAt).
+		Update("deleted_at", nil).Error
+}
+
+// removeUploadedFiles deletes the files of locally uploaded media and
+// returns how many were removed. Remote media such as MLS photos are skipped.
+func removeUploadedFiles(mediaFiles []models.MediaFile) int {
+	removed := 0
+	for _, mediaFile := range mediaFiles {
+		if !strings.HasPrefix(mediaFile.FileURL, "/"+mediaUploadDir+"/") {
+			continue
+		}
+
+		filePath := filepath.Join(mediaUploadDir, mediaFile.FileName)
+		if err := os.Remove(filePath); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("trash: failed to delete %s: %v", filePath, err)
+			}
+			continue
+		}
+		removed++
+	}
+	return removed
+}
+
+// excludeIDs returns the IDs in ids that are not in exclude
+func excludeIDs(ids, exclude []uint) []uint {
+	excluded := make(map[uint]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+
+	var remaining []uint
+	for _, id := range ids {
+		if !excluded[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
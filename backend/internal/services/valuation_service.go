@@ -0,0 +1,374 @@
+package services
+
+import (
+	"errors"
+	"galactavista/internal/models"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultValuationRadiusMiles = 1.0
+	defaultValuationMonths      = 12
+	defaultValuationMaxComps    = 6
+	// valuationCandidateLimit caps how many sales are scored per valuation
+	valuationCandidateLimit = 200
+	// valuationSizeTolerance is how far a comparable's square footage may be
+	// from the subject's, as a fraction of the subject's
+	valuationSizeTolerance = 0.25
+	// minValuationSpread is the smallest half-width of an estimate's range,
+	// as a fraction of the estimate
+	minValuationSpread = 0.05
+	earthRadiusMiles   = 3958.8
+	milesPerDegreeLat  = 69.0
+)
+
+// Valuation match methods reported in ValuationResponse.MatchedBy
+const (
+	valuationMatchDistance = "distance"
+	valuationMatchZipCode  = "zip_code"
+	valuationMatchCity     = "city"
+)
+
+// ValuationService estimates property values from comparable sales
+type ValuationService struct {
+	db          *gorm.DB
+	adjustments models.ValuationAdjustments
+}
+
+// NewValuationService creates a new valuation service that applies the
+// given default adjustments
+func NewValuationService(db *gorm.DB, adjustments models.ValuationAdjustments) *ValuationService {
+	return &ValuationService{db: db, adjustments: adjustments}
+}
+
+// valuationSubject is the property being valued
+type valuationSubject struct {
+	ID           uint
+	PropertyType models.PropertyType
+	City         string
+	State        string
+	ZipCode      string
+	Latitude     *float64
+	Longitude    *float64
+	Bedrooms     int
+	Bathrooms    float64
+	SquareFeet   int
+	YearBuilt    int
+	LotSize      float64
+}
+
+// ValueProperty estimates the value of an existing property
+func (s *ValuationService) ValueProperty(id uint, options *models.ValuationOptions) (*models.ValuationResponse, error) {
+	var property models.Property
+	if err := s.db.First(&property, id).Error; err != nil {
+		return nil, err
+	}
+
+	subject := valuationSubject{
+		ID:           property.ID,
+		PropertyType: property.PropertyType,
+		City:         property.City,
+		State:        property.State,
+		ZipCode:      property.ZipCode,
+		Latitude:     property.Latitude,
+		Longitude:    property.Longitude,
+		Bedrooms:     property.Bedrooms,
+		Bathrooms:    property.Bathrooms,
+		SquareFeet:   property.SquareFeet,
+		YearBuilt:    property.YearBuilt,
+		LotSize:      property.LotSize,
+	}
+	response, err := s.value(&subject, *options, s.adjustments)
+	if err != nil {
+		return nil, err
+	}
+	response.PropertyID = &property.ID
+
+	return response, nil
+}
+
+// EstimateValue estimates the value of a property described by its address
+// and attributes
+func (s *ValuationService) EstimateValue(req *models.ValuationRequest) (*models.ValuationResponse, error) {
+	var fieldErrors []models.FieldError
+	if !req.PropertyType.IsValid() {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "property_type", Message: "unknown property type"})
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "latitude", Message: "latitude and longitude must be given together"})
+	}
+	if req.Latitude == nil && req.ZipCode == "" && (req.City == "" || req.State == "") {
+		fieldErrors = append(fieldErrors, models.FieldError{Field: "zip_code", Message: "coordinates, a zip code, or a city and state are required"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	adjustments := s.adjustments
+	if req.Adjustments != nil {
+		adjustments = *req.Adjustments
+	}
+
+	subject := valuationSubject{
+		PropertyType: req.PropertyType,
+		City:         req.City,
+		State:        req.State,
+		ZipCode:      req.ZipCode,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Bedrooms:     req.Bedrooms,
+		Bathrooms:    req.Bathrooms,
+		SquareFeet:   req.SquareFeet,
+		YearBuilt:    req.YearBuilt,
+		LotSize:      req.LotSize,
+	}
+	return s.value(&subject, req.ValuationOptions, adjustments)
+}
+
+// value finds the subject's comparable sales, adjusts them and weighs them
+// into an estimate
+func (s *ValuationService) value(subject *valuationSubject, options models.ValuationOptions, adjustments models.ValuationAdjustments) (*models.ValuationResponse, error) {
+	if options.RadiusMiles == 0 {
+		options.RadiusMiles = defaultValuationRadiusMiles
+	}
+	if options.Months == 0 {
+		options.Months = defaultValuationMonths
+	}
+	if options.MaxComps == 0 {
+		options.MaxComps = defaultValuationMaxComps
+	}
+
+	now := time.Now()
+	query := s.db.Model(&models.Property{}).
+		Where("status = ? AND property_type = ? AND sold_at >= ?",
+			models.PropertyStatusSold, subject.PropertyType, now.AddDate(0, -options.Months, 0)).
+		Where("COALESCE(sold_price, price) > 0")
+	if subject.ID != 0 {
+		query = query.Where("id <> ?", subject.ID)
+	}
+	if subject.SquareFeet > 0 {
+		query = query.Where("square_feet BETWEEN ? AND ?",
+			int(float64(subject.SquareFeet)*(1-valuationSizeTolerance)),
+			int(math.Ceil(float64(subject.SquareFeet)*(1+valuationSizeTolerance))))
+	}
+	if subject.Bedrooms > 0 {
+		query = query.Where("bedrooms BETWEEN ? AND ?", subject.Bedrooms-1, subject.Bedrooms+1)
+	}
+
+	var matchedBy string
+	switch {
+	case subject.Latitude != nil && subject.Longitude != nil:
+		matchedBy = valuationMatchDistance
+		latDelta := options.RadiusMiles / milesPerDegreeLat
+		lngDelta := options.RadiusMiles / (milesPerDegreeLat * math.Max(math.Cos(*subject.Latitude*math.Pi/180), 0.01))
+		query = query.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?",
+			*subject.Latitude-latDelta, *subject.Latitude+latDelta,
+			*subject.Longitude-lngDelta, *subject.Longitude+lngDelta)
+	case subject.ZipCode != "":
+		matchedBy = valuationMatchZipCode
+		query = query.Where("zip_code = ?", subject.ZipCode)
+	case subject.City != "" && subject.State != "":
+		matchedBy = valuationMatchCity
+		query = query.Where("LOWER(city) = ? AND LOWER(state) = ?",
+			strings.ToLower(subject.City), strings.ToLower(subject.State))
+	default:
+		return nil, errors.New("property has no location to find comparable sales by")
+	}
+
+	var candidates []models.Property
+	if err := query.Order("sold_at DESC").Limit(valuationCandidateLimit).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	type scoredComp struct {
+		comp  models.ComparableSale
+		score float64
+	}
+	var scored []scoredComp
+	for i := range candidates {
+		comp := toComparableSale(&candidates[i])
+		if distance := subject.distanceTo(&candidates[i]); distance != nil {
+			if *distance > options.RadiusMiles {
+				continue
+			}
+			rounded := math.Round(*distance*100) / 100
+			comp.DistanceMiles = &rounded
+		}
+		scored = append(scored, scoredComp{
+			comp:  comp,
+			score: subject.similarityScore(&comp, options, now),
+		})
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score < scored[j].score })
+	if len(scored) > options.MaxComps {
+		scored = scored[:options.MaxComps]
+	}
+
+	comps := make([]models.ComparableSale, len(scored))
+	for i, candidate := range scored {
+		comp := candidate.comp
+		comp.Adjustments = subject.adjustComp(&comp, adjustments, now)
+		comp.AdjustedPrice = comp.SoldPrice
+		for _, adjustment := range comp.Adjustments {
+			comp.AdjustedPrice += adjustment.Amount
+		}
+		comp.AdjustedPrice = math.Round(comp.AdjustedPrice)
+		comp.Weight = math.Round(1/(1+candidate.score)*1000) / 1000
+		comps[i] = comp
+	}
+
+	return &models.ValuationResponse{
+		Estimate:    estimateValue(comps, subject.SquareFeet),
+		Comps:       comps,
+		MatchedBy:   matchedBy,
+		Options:     options,
+		Adjustments: adjustments,
+	}, nil
+}
+
+// distanceTo returns the distance in miles to a property, or nil when either
+// has no coordinates
+func (subject *valuationSubject) distanceTo(property *models.Property) *float64 {
+	if subject.Latitude == nil || subject.Longitude == nil || property.Latitude == nil || property.Longitude == nil {
+		return nil
+	}
+	distance := distanceMiles(*subject.Latitude, *subject.Longitude, *property.Latitude, *property.Longitude)
+	return &distance
+}
+
+// similarityScore rates how far a comparable sale is from the subject; lower
+// is more similar. Distance, size, bedrooms and age of the sale each add to
+// the score relative to the search bounds.
+func (subject *valuationSubject) similarityScore(comp *models.ComparableSale, options models.ValuationOptions, now time.Time) float64 {
+	score := 0.0
+	if comp.DistanceMiles != nil {
+		score += *comp.DistanceMiles / options.RadiusMiles
+	}
+	if subject.SquareFeet > 0 && comp.SquareFeet > 0 {
+		score += math.Abs(float64(comp.SquareFeet-subject.SquareFeet)) / (float64(subject.SquareFeet) * valuationSizeTolerance)
+	}
+	if subject.Bedrooms > 0 {
+		score += math.Abs(float64(comp.Bedrooms - subject.Bedrooms))
+	}
+	score += monthsBetween(comp.SoldAt, now) / float64(options.Months)
+	return score
+}
+
+// adjustComp lists the adjustments that bring a comparable sale in line with
+// the subject: market movement since the sale, then each differing attribute
+func (subject *valuationSubject) adjustComp(comp *models.ComparableSale, adjustments models.ValuationAdjustments, now time.Time) []models.PriceAdjustment {
+	result := []models.PriceAdjustment{}
+	add := func(reason string, amount float64) {
+		if amount = math.Round(amount); amount != 0 {
+			result = append(result, models.PriceAdjustment{Reason: reason, Amount: amount})
+		}
+	}
+
+	add("market_time", comp.SoldPrice*adjustments.MonthlyAppreciation*monthsBetween(comp.SoldAt, now))
+	if subject.SquareFeet > 0 && comp.SquareFeet > 0 {
+		add("square_feet", float64(subject.SquareFeet-comp.SquareFeet)*adjustments.PerSquareFoot)
+	}
+	add("bedrooms", float64(subject.Bedrooms-comp.Bedrooms)*adjustments.PerBedroom)
+	add("bathrooms", (subject.Bathrooms-comp.Bathrooms)*adjustments.PerBathroom)
+	if subject.YearBuilt > 0 && comp.YearBuilt > 0 {
+		add("year_built", float64(subject.YearBuilt-comp.YearBuilt)*adjustments.PerYearBuilt)
+	}
+	if subject.LotSize > 0 && comp.LotSize > 0 {
+		add("lot_size", (subject.LotSize-comp.LotSize)*adjustments.PerLotAcre)
+	}
+	return result
+}
+
+// estimateValue weighs adjusted comparable prices into an estimate. The
+// range spans one weighted standard deviation either side, and at least
+// minValuationSpread of the estimate.
+func estimateValue(comps []models.ComparableSale, squareFeet int) *models.ValueEstimate {
+	if len(comps) == 0 {
+		return nil
+	}
+
+	var totalWeight, weighted float64
+	for _, comp := range comps {
+		totalWeight += comp.Weight
+		weighted += comp.Weight * comp.AdjustedPrice
+	}
+	value := weighted / totalWeight
+
+	var variance float64
+	for _, comp := range comps {
+		variance += comp.Weight * math.Pow(comp.AdjustedPrice-value, 2)
+	}
+	deviation := math.Sqrt(variance / totalWeight)
+	spread := math.Max(deviation, value*minValuationSpread)
+
+	confidence := "low"
+	switch relative := deviation / value; {
+	case len(comps) >= 4 && relative <= 0.08:
+		confidence = "high"
+	case len(comps) >= 2 && relative <= 0.15:
+		confidence = "medium"
+	}
+
+	estimate := &models.ValueEstimate{
+		Value:      roundToHundred(value),
+		Low:        roundToHundred(value - spread),
+		High:       roundToHundred(value + spread),
+		Confidence: confidence,
+	}
+	if squareFeet > 0 {
+		pricePerSqFt := math.Round(estimate.Value/float64(squareFeet)*100) / 100
+		estimate.PricePerSqFt = &pricePerSqFt
+	}
+	return estimate
+}
+
+// toComparableSale converts a sold Property to a ComparableSale
+func toComparableSale(property *models.Property) models.ComparableSale {
+	comp := models.ComparableSale{
+		PropertyID: property.ID,
+		Title:      property.Title,
+		Address:    property.Address,
+		City:       property.City,
+		SoldPrice:  property.Price,
+		Bedrooms:   property.Bedrooms,
+		Bathrooms:  property.Bathrooms,
+		SquareFeet: property.SquareFeet,
+		YearBuilt:  property.YearBuilt,
+		LotSize:    property.LotSize,
+	}
+	if property.SoldPrice != nil {
+		comp.SoldPrice = *property.SoldPrice
+	}
+	if property.SoldAt != nil {
+		comp.SoldAt = *property.SoldAt
+	}
+	return comp
+}
+
+// distanceMiles returns the great-circle distance between two points
+func distanceMiles(lat1, lng1, lat2, lng2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// monthsBetween returns the number of 30-day months from one time to another
+func monthsBetween(from, to time.Time) float64 {
+	if to.Before(from) {
+		return 0
+	}
+	return to.Sub(from).Hours() / (24 * 30)
+}
+
+// roundToHundred rounds a dollar amount to the nearest hundred
+func roundToHundred(amount float64) float64 {
+	return math.Round(amount/100) * 100
+}
@@ -0,0 +1,348 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Address is a postal address split into the parts listings store
+type Address struct {
+	Street  string
+	Unit    string
+	City    string
+	State   string
+	ZipCode string
+}
+
+// unitDesignators introduce a secondary unit within a street line
+var unitDesignators = map[string]bool{
+	"apt":       true,
+	"apartment": true,
+	"unit":      true,
+	"ste":       true,
+	"suite":     true,
+	"rm":        true,
+	"room":      true,
+	"#":         true,
+}
+
+// directionals maps directions to their USPS abbreviations
+var directionals = map[string]string{
+	"north":     "N",
+	"south":     "S",
+	"east":      "E",
+	"west":      "W",
+	"northeast": "NE",
+	"northwest": "NW",
+	"southeast": "SE",
+	"southwest": "SW",
+	"n":         "N",
+	"s":         "S",
+	"e":         "E",
+	"w":         "W",
+	"ne":        "NE",
+	"nw":        "NW",
+	"se":        "SE",
+	"sw":        "SW",
+}
+
+// streetSuffixes maps common street suffix spellings to their USPS
+// abbreviations
+var streetSuffixes = map[string]string{
+	"street":    "St",
+	"str":       "St",
+	"st":        "St",
+	"avenue":    "Ave",
+	"av":        "Ave",
+	"ave":       "Ave",
+	"boulevard": "Blvd",
+	"blvd":      "Blvd",
+	"road":      "Rd",
+	"rd":        "Rd",
+	"drive":     "Dr",
+	"dr":        "Dr",
+	"lane":      "Ln",
+	"ln":        "Ln",
+	"court":     "Ct",
+	"ct":        "Ct",
+	"place":     "Pl",
+	"pl":        "Pl",
+	"terrace":   "Ter",
+	"ter":       "Ter",
+	"circle":    "Cir",
+	"cir":       "Cir",
+	"highway":   "Hwy",
+	"hwy":       "Hwy",
+	"parkway":   "Pkwy",
+	"pkwy":      "Pkwy",
+	"way":       "Way",
+	"trail":     "Trl",
+	"trl":       "Trl",
+	"square":    "Sq",
+	"sq":        "Sq",
+	"plaza":     "Plz",
+	"plz":       "Plz",
+	"alley":     "Aly",
+	"aly":       "Aly",
+	"crossing":  "Xing",
+	"xing":      "Xing",
+}
+
+// stateCodes maps US state names to their postal codes
+var stateCodes = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR",
+	"california": "CA", "colorado": "CO", "connecticut": "CT", "delaware": "DE",
+	"district of columbia": "DC", "florida": "FL", "georgia": "GA", "hawaii": "HI",
+	"idaho": "ID", "illinois": "IL", "indiana": "IN", "iowa": "IA",
+	"kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME",
+	"maryland": "MD", "massachusetts": "MA", "michigan": "MI", "minnesota": "MN",
+	"mississippi": "MS", "missouri": "MO", "montana": "MT", "nebraska": "NE",
+	"nevada": "NV", "new hampshire": "NH", "new jersey": "NJ", "new mexico": "NM",
+	"new york": "NY", "north carolina": "NC", "north dakota": "ND", "ohio": "OH",
+	"oklahoma": "OK", "oregon": "OR", "pennsylvania": "PA", "rhode island": "RI",
+	"south carolina": "SC", "south dakota": "SD", "tennessee": "TN", "texas": "TX",
+	"utah": "UT", "vermont": "VT", "virginia": "VA", "washington": "WA",
+	"west virginia": "WV", "wisconsin": "WI", "wyoming": "WY",
+}
+
+// Normalize standardizes an address: casing, USPS street suffix and
+// directional abbreviations, state codes and ZIP+4 formatting. A unit given
+// in the street line ("Apt 4B", "#4B") is moved to Unit unless Unit is
+// already set.
+func Normalize(a Address) Address {
+	street, unit := splitUnit(a.Street)
+	if a.Unit != "" {
+		unit = normalizeUnit(a.Unit)
+	}
+
+	return Address{
+		Street:  street,
+		Unit:    unit,
+		City:    recase(strings.Join(strings.Fields(a.City), " ")),
+		State:   normalizeState(a.State),
+		ZipCode: normalizeZip(a.ZipCode),
+	}
+}
+
+// Key is the canonical form of a normalized address used to match listings
+// of the same place. It ignores ZIP+4 and falls back to the city and state
+// when there is no ZIP code.
+func (a Address) Key() string {
+	area := zip5(a.ZipCode)
+	if area == "" {
+		area = strings.ToLower(a.City + "," + a.State)
+	}
+	return strings.ToLower(a.Street + "|" + a.Unit + "|" + area)
+}
+
+// HouseNumber returns the leading street number of a normalized street, if
+// it has one
+func (a Address) HouseNumber() string {
+	fields := strings.Fields(a.Street)
+	if len(fields) == 0 || !startsWithDigit(fields[0]) {
+		return ""
+	}
+	return fields[0]
+}
+
+// Zip5 returns the five digit ZIP code without any ZIP+4 extension
+func (a Address) Zip5() string {
+	return zip5(a.ZipCode)
+}
+
+// Similarity compares two strings case-insensitively by edit distance,
+// from 0 for nothing in common to 1 for equal
+func Similarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// splitUnit normalizes a street line and splits off a trailing unit
+func splitUnit(street string) (string, string) {
+	fields := strings.Fields(strings.ReplaceAll(street, ",", " "))
+	for i := range fields {
+		fields[i] = strings.TrimRight(fields[i], ".")
+	}
+
+	unit := ""
+	for i := 1; i < len(fields); i++ {
+		lower := strings.ToLower(fields[i])
+		if unitDesignators[lower] && i+1 < len(fields) {
+			unit = normalizeUnit(strings.Join(fields[i+1:], " "))
+			fields = fields[:i]
+			break
+		}
+		if strings.HasPrefix(lower, "#") && len(lower) > 1 {
+			unit = normalizeUnit(strings.Join(fields[i:], " "))
+			fields = fields[:i]
+			break
+		}
+	}
+
+	return normalizeStreet(fields), unit
+}
+
+// normalizeStreet recases street tokens and abbreviates the suffix and any
+// leading or trailing directional
+func normalizeStreet(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	start := 0
+	if startsWithDigit(fields[0]) {
+		start = 1
+	}
+	end := len(fields) - 1
+
+	words := make([]string, len(fields))
+	for i, field := range fields {
+		words[i] = recase(field)
+	}
+
+	// Directionals are only abbreviated where a street name remains
+	// between them and the suffix, so "North St" keeps its name
+	if end-start >= 2 {
+		if abbrev, ok := directionals[strings.ToLower(fields[start])]; ok {
+			words[start] = abbrev
+			start++
+		}
+	}
+	if end-start >= 2 {
+		if abbrev, ok := directionals[strings.ToLower(fields[end])]; ok {
+			words[end] = abbrev
+			end--
+		}
+	}
+	if end > start {
+		if abbrev, ok := streetSuffixes[strings.ToLower(fields[end])]; ok {
+			words[end] = abbrev
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// normalizeUnit strips unit designators and upper-cases the unit
+func normalizeUnit(unit string) string {
+	fields := strings.Fields(strings.ToUpper(unit))
+	for len(fields) > 1 && unitDesignators[strings.ToLower(fields[0])] {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && unitDesignators[strings.ToLower(fields[0])] {
+		return ""
+	}
+	return strings.TrimPrefix(strings.Join(fields, " "), "#")
+}
+
+// normalizeState converts state names to postal codes
+func normalizeState(state string) string {
+	state = strings.Join(strings.Fields(state), " ")
+	if len(state) == 2 {
+		return strings.ToUpper(state)
+	}
+	if code, ok := stateCodes[strings.ToLower(state)]; ok {
+		return code
+	}
+	return recase(state)
+}
+
+// normalizeZip formats nine digit ZIP codes as ZIP+4
+func normalizeZip(zip string) string {
+	zip = strings.TrimSpace(zip)
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return 'x'
+	}, zip)
+	if strings.ContainsRune(digits, 'x') {
+		return zip
+	}
+	switch len(digits) {
+	case 5:
+		return digits
+	case 9:
+		return digits[:5] + "-" + digits[5:]
+	}
+	return zip
+}
+
+// zip5 returns the five digit prefix of a normalized US ZIP code
+func zip5(zip string) string {
+	if len(zip) < 5 {
+		return ""
+	}
+	for _, r := range zip[:5] {
+		if !unicode.IsDigit(r) {
+			return ""
+		}
+	}
+	return zip[:5]
+}
+
+// recase title-cases words written entirely in upper or lower case and
+// leaves mixed-case words such as "McDonald" alone. Words starting with a
+// digit are lower-cased ("1ST" becomes "1st").
+func recase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		if startsWithDigit(word) {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+		if word != strings.ToUpper(word) && word != strings.ToLower(word) {
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
+
+// levenshtein returns the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
@@ -0,0 +1,128 @@
+package fairhousing
+
+// Dictionary is the rule set listings are linted against. Each rule lists
+// the phrases it matches; matching ignores case and treats any run of
+// whitespace or hyphens between words alike, so "adults-only" and
+// "Adults  only" both match "adults only". Severe rules state an exclusion
+// outright and block publication; advisory rules cover wording that can
+// read as a preference and only warn.
+var Dictionary = []Rule{
+	// Familial status
+	{
+		ID:       "familial-exclusion",
+		Category: CategoryFamilialStatus,
+		Severity: SeveritySevere,
+		Phrases: []string{
+			"no children", "no kids", "no families", "adults only", "adult only",
+			"no minors", "not suitable for children", "child free", "childless couples",
+		},
+		Message:      "Excluding families with children is prohibited familial status discrimination",
+		Alternatives: []string{"describe the property instead, e.g. \"quiet street\" or \"low-maintenance yard\""},
+	},
+	{
+		ID:           "familial-preference",
+		Category:     CategoryFamilialStatus,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"perfect for singles", "ideal for singles", "ideal for couples", "perfect for couples", "empty nesters", "bachelor pad"},
+		Message:      "Naming who the home suits can read as a preference against families",
+		Alternatives: []string{"cozy", "low-maintenance", "studio"},
+	},
+	{
+		ID:           "age-preference",
+		Category:     CategoryFamilialStatus,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"mature persons", "mature couple", "retirees only", "seniors only"},
+		Message:      "Age preferences are only allowed for qualifying housing for older persons",
+		Alternatives: []string{"55+ community (only if the community qualifies)", "single-level living"},
+	},
+
+	// Religion
+	{
+		ID:           "religion-exclusion",
+		Category:     CategoryReligion,
+		Severity:     SeveritySevere,
+		Phrases:      []string{"christians only", "christian only", "no muslims", "no jews", "jewish only", "muslims only", "christian home", "christian family"},
+		Message:      "Stating a religious preference or exclusion is prohibited",
+		Alternatives: []string{"remove the reference to religion"},
+	},
+	{
+		ID:           "religion-landmark",
+		Category:     CategoryReligion,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"near church", "close to church", "walk to church", "near synagogue", "near mosque", "near temple"},
+		Message:      "Naming a place of worship can read as a religious preference",
+		Alternatives: []string{"near local landmarks", "walk to downtown"},
+	},
+
+	// Race, color and national origin
+	{
+		ID:           "race-exclusion",
+		Category:     CategoryRace,
+		Severity:     SeveritySevere,
+		Phrases:      []string{"whites only", "white only", "no blacks", "white neighborhood", "white community", "no hispanics", "no asians"},
+		Message:      "Stating a racial preference or exclusion is prohibited",
+		Alternatives: []string{"remove the reference to race"},
+	},
+	{
+		ID:           "national-origin-exclusion",
+		Category:     CategoryNationalOrigin,
+		Severity:     SeveritySevere,
+		Phrases:      []string{"english speakers only", "english speaking only", "must speak english", "no immigrants", "no foreigners", "citizens only"},
+		Message:      "Excluding people by national origin or language is prohibited",
+		Alternatives: []string{"remove the requirement"},
+	},
+	{
+		ID:           "neighborhood-character",
+		Category:     CategoryRace,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"ethnic neighborhood", "exclusive neighborhood", "exclusive community", "integrated neighborhood", "traditional neighborhood"},
+		Message:      "Describing a neighborhood's people rather than its features can signal steering",
+		Alternatives: []string{"established neighborhood", "tree-lined streets", "close to parks and shops"},
+	},
+
+	// Disability
+	{
+		ID:           "disability-exclusion",
+		Category:     CategoryDisability,
+		Severity:     SeveritySevere,
+		Phrases:      []string{"no wheelchairs", "no disabled", "no handicapped", "able-bodied", "able bodied", "must be able to climb stairs", "no service animals"},
+		Message:      "Excluding people with disabilities is prohibited",
+		Alternatives: []string{"second-floor unit, no elevator", "stairs to entry"},
+	},
+	{
+		ID:           "disability-wording",
+		Category:     CategoryDisability,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"handicap accessible", "handicapped accessible", "handicapped parking"},
+		Message:      "\"Handicapped\" is outdated wording for accessibility features",
+		Alternatives: []string{"accessible", "wheelchair accessible", "accessible parking"},
+	},
+
+	// Sex
+	{
+		ID:           "sex-preference",
+		Category:     CategorySex,
+		Severity:     SeveritySevere,
+		Phrases:      []string{"men only", "women only", "male only", "female only", "no women", "no men"},
+		Message:      "Stating a preference by sex is prohibited outside shared living spaces",
+		Alternatives: []string{"remove the preference"},
+	},
+	{
+		ID:           "sex-wording",
+		Category:     CategorySex,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"ideal for men", "ideal for women", "man cave", "mother-in-law suite", "mother in law suite"},
+		Message:      "Gendered wording can read as a preference by sex",
+		Alternatives: []string{"bonus room", "guest suite", "accessory dwelling unit"},
+	},
+
+	// Source of income, protected in many states and cities
+	{
+		ID:           "source-of-income",
+		Category:     CategorySourceOfIncome,
+		Severity:     SeverityAdvisory,
+		Phrases:      []string{"no section 8", "no vouchers", "no housing assistance", "employed only"},
+		Message:      "Refusing housing assistance is prohibited in many jurisdictions",
+		Alternatives: []string{"state the income requirement that applies to all applicants"},
+	},
+}
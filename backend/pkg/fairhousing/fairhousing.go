@@ -0,0 +1,137 @@
+package fairhousing
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Category is the protected class a rule guards
+type Category string
+
+const (
+	CategoryFamilialStatus Category = "familial_status"
+	CategoryReligion       Category = "religion"
+	CategoryRace           Category = "race"
+	CategoryNationalOrigin Category = "national_origin"
+	CategoryDisability     Category = "disability"
+	CategorySex            Category = "sex"
+	CategorySourceOfIncome Category = "source_of_income"
+)
+
+// Severity is how serious a rule's matches are
+type Severity string
+
+const (
+	SeverityAdvisory Severity = "advisory"
+	SeveritySevere   Severity = "severe"
+)
+
+// Rule is a dictionary entry: the phrases to look for and what to say
+// about them
+type Rule struct {
+	ID           string
+	Category     Category
+	Severity     Severity
+	Phrases      []string
+	Message      string
+	Alternatives []string
+}
+
+// Finding is a phrase a rule matched in the linted text. Start and End are
+// character offsets of the match, End exclusive.
+type Finding struct {
+	RuleID       string
+	Category     Category
+	Severity     Severity
+	Phrase       string
+	Message      string
+	Alternatives []string
+	Start        int
+	End          int
+}
+
+// Linter finds dictionary phrases in text
+type Linter struct {
+	rules    []Rule
+	patterns [][]*regexp.Regexp
+}
+
+// NewLinter compiles a linter for the given rules
+func NewLinter(rules []Rule) *Linter {
+	linter := &Linter{rules: rules, patterns: make([][]*regexp.Regexp, len(rules))}
+	for i, rule := range rules {
+		for _, phrase := range rule.Phrases {
+			linter.patterns[i] = append(linter.patterns[i], phrasePattern(phrase))
+		}
+	}
+	return linter
+}
+
+// defaultLinter lints against the built-in dictionary
+var defaultLinter = NewLinter(Dictionary)
+
+// Lint checks text against the built-in dictionary
+func Lint(text string) []Finding {
+	return defaultLinter.Lint(text)
+}
+
+// Lint returns every dictionary phrase in text in the order they appear.
+// Overlapping matches of different rules are each reported; a span one rule
+// matches through several phrases is reported once.
+func (l *Linter) Lint(text string) []Finding {
+	var findings []Finding
+	for i, rule := range l.rules {
+		seen := make(map[[2]int]bool)
+		for _, pattern := range l.patterns[i] {
+			for _, loc := range pattern.FindAllStringIndex(text, -1) {
+				span := [2]int{loc[0], loc[1]}
+				if seen[span] {
+					continue
+				}
+				seen[span] = true
+				findings = append(findings, Finding{
+					RuleID:       rule.ID,
+					Category:     rule.Category,
+					Severity:     rule.Severity,
+					Phrase:       text[loc[0]:loc[1]],
+					Message:      rule.Message,
+					Alternatives: rule.Alternatives,
+					Start:        utf8.RuneCountInString(text[:loc[0]]),
+					End:          utf8.RuneCountInString(text[:loc[1]]),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Start != findings[j].Start {
+			return findings[i].Start < findings[j].Start
+		}
+		return findings[i].End > findings[j].End
+	})
+	return findings
+}
+
+// HasSevere reports whether any finding is severe
+func HasSevere(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeveritySevere {
+			return true
+		}
+	}
+	return false
+}
+
+// phrasePattern matches a phrase as whole words, ignoring case and treating
+// whitespace and hyphens between words alike
+func phrasePattern(phrase string) *regexp.Regexp {
+	words := strings.FieldsFunc(strings.ToLower(phrase), func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	return regexp.MustCompile(`(?i)\b` + strings.Join(words, `[\s-]+`) + `\b`)
+}
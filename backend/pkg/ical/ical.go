@@ -0,0 +1,178 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of an iCalendar document
+const ContentType = "text/calendar; charset=utf-8"
+
+// utcFormat is the iCalendar UTC date-time form (RFC 5545 section 3.3.5)
+const utcFormat = "20060102T150405Z"
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+// Event status values
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is an iCalendar VCALENDAR object
+type Calendar struct {
+	ProdID   string
+	Name     string
+	TimeZone string
+	Events   []Event
+}
+
+// Event is an iCalendar VEVENT. Times are written in UTC; TimeZone is only
+// a hint to clients about the event's local time zone.
+type Event struct {
+	UID          string
+	Sequence     int
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Write encodes the calendar as an iCalendar document
+func (c *Calendar) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN", "VCALENDAR")
+	lw.line("VERSION", "2.0")
+	lw.line("PRODID", c.ProdID)
+	lw.line("CALSCALE", "GREGORIAN")
+	lw.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		lw.text("X-WR-CALNAME", c.Name)
+	}
+	if c.TimeZone != "" {
+		lw.line("X-WR-TIMEZONE", c.TimeZone)
+	}
+
+	stamp := time.Now()
+	for _, event := range c.Events {
+		lw.line("BEGIN", "VEVENT")
+		lw.line("UID", event.UID)
+		lw.line("DTSTAMP", formatUTC(stamp))
+		lw.line("DTSTART", formatUTC(event.Start))
+		lw.line("DTEND", formatUTC(event.End))
+		if event.Sequence > 0 {
+			lw.line("SEQUENCE", strconv.Itoa(event.Sequence))
+		}
+		lw.text("SUMMARY", event.Summary)
+		if event.Description != "" {
+			lw.text("DESCRIPTION", event.Description)
+		}
+		if event.Location != "" {
+			lw.text("LOCATION", event.Location)
+		}
+		if event.URL != "" {
+			lw.line("URL", event.URL)
+		}
+		if event.Status != "" {
+			lw.line("STATUS", event.Status)
+		}
+		if !event.Created.IsZero() {
+			lw.line("CREATED", formatUTC(event.Created))
+		}
+		if !event.LastModified.IsZero() {
+			lw.line("LAST-MODIFIED", formatUTC(event.LastModified))
+		}
+		lw.line("END", "VEVENT")
+	}
+
+	lw.line("END", "VCALENDAR")
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// String returns the calendar as an iCalendar document
+func (c *Calendar) String() string {
+	var sb strings.Builder
+	c.Write(&sb)
+	return sb.String()
+}
+
+// lineWriter writes folded CRLF content lines, keeping the first error
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a property whose value needs no escaping
+func (lw *lineWriter) line(name, value string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = lw.w.WriteString(fold(name+":"+value) + "\r\n")
+}
+
+// text writes a TEXT property, escaping its value
+func (lw *lineWriter) text(name, value string) {
+	lw.line(name, escapeText(value))
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// fold splits a content line into 75-octet lines without breaking UTF-8
+// sequences (RFC 5545 section 3.1)
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var sb strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
+
+// isRuneStart reports whether b begins a UTF-8 sequence
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// formatUTC formats a time as an iCalendar UTC date-time
+func formatUTC(t time.Time) string {
+	return t.UTC().Format(utcFormat)
+}
@@ -0,0 +1,117 @@
+package reso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPageSize is the number of records requested per OData page
+const DefaultPageSize = 200
+
+// Client is a minimal RESO Web API (OData) client
+type Client struct {
+	baseURL    string
+	token      string
+	pageSize   int
+	httpClient *http.Client
+}
+
+// NewClient creates a new RESO Web API client. baseURL is the OData service
+// root, e.g. https://api.mls.example.com/reso/odata
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		pageSize:   DefaultPageSize,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// FetchProperties pages through every Property modified at or after since,
+// oldest first, calling fn once per page. Paging stops at the first error
+// returned by fn so callers can persist progress page by page.
+func (c *Client) FetchProperties(ctx context.Context, since *time.Time, fn func(page []Property) error) error {
+	params := url.Values{}
+	params.Set("$orderby", "ModificationTimestamp asc")
+	params.Set("$expand", "Media")
+	params.Set("$top", strconv.Itoa(c.pageSize))
+	if since != nil {
+		params.Set("$filter", "ModificationTimestamp ge "+since.UTC().Format(time.RFC3339Nano))
+	}
+
+	next := c.baseURL + "/Property?" + params.Encode()
+	for next != "" {
+		page, err := c.getPage(ctx, next)
+		if err != nil {
+			return err
+		}
+
+		if len(page.Value) > 0 {
+			if err := fn(page.Value); err != nil {
+				return err
+			}
+		}
+
+		next, err = c.resolve(page.NextLink)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getPage requests and decodes a single OData page
+func (c *Client) getPage(ctx context.Context, pageURL string) (*Page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build RESO request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RESO request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("RESO request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var page Page
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode RESO response: %w", err)
+	}
+
+	return &page, nil
+}
+
+// resolve turns a possibly relative nextLink into an absolute URL
+func (c *Client) resolve(link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(c.baseURL + "/")
+	if err != nil {
+		return "", fmt.Errorf("invalid RESO base URL: %w", err)
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid RESO next link: %w", err)
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,107 @@
+package reso
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// modificationFilter matches the $filter expressions produced by Client
+var modificationFilter = regexp.MustCompile(`^ModificationTimestamp (ge|gt) (\S+)$`)
+
+// FixtureHandler serves a static set of Property records through the same
+// OData conventions as a RESO Web API server: $filter on
+// ModificationTimestamp, $orderby, $top/$skip paging and @odata.nextLink.
+// It lets the ingestion pipeline be exercised locally without MLS credentials.
+type FixtureHandler struct {
+	// MaxPageSize caps $top the way servers that page smaller than asked
+	// do; 0 serves pages of the requested size
+	MaxPageSize int
+
+	records []Property
+}
+
+// NewFixtureHandler creates a fixture handler over the given records
+func NewFixtureHandler(records []Property) *FixtureHandler {
+	sorted := make([]Property, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ModificationTimestamp.Before(sorted[j].ModificationTimestamp)
+	})
+	return &FixtureHandler{records: sorted}
+}
+
+// ServeHTTP implements http.Handler
+func (h *FixtureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, "/Property") {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+
+	records := h.records
+	if filter := query.Get("$filter"); filter != "" {
+		match := modificationFilter.FindStringSubmatch(filter)
+		if match == nil {
+			http.Error(w, "unsupported $filter", http.StatusBadRequest)
+			return
+		}
+		since, err := time.Parse(time.RFC3339Nano, match[2])
+		if err != nil {
+			http.Error(w, "invalid timestamp in $filter", http.StatusBadRequest)
+			return
+		}
+
+		var filtered []Property
+		for _, record := range records {
+			ts := record.ModificationTimestamp
+			if ts.After(since) || (match[1] == "ge" && ts.Equal(since)) {
+				filtered = append(filtered, record)
+			}
+		}
+		records = filtered
+	}
+
+	top := DefaultPageSize
+	if value := query.Get("$top"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			top = n
+		}
+	}
+	if h.MaxPageSize > 0 && top > h.MaxPageSize {
+		top = h.MaxPageSize
+	}
+	skip := 0
+	if value := query.Get("$skip"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			skip = n
+		}
+	}
+
+	page := Page{Context: "$metadata#Property", Value: []Property{}}
+	if skip < len(records) {
+		end := skip + top
+		if end > len(records) {
+			end = len(records)
+		}
+		page.Value = records[skip:end]
+
+		if end < len(records) {
+			next := url.Values{}
+			for key, values := range query {
+				next[key] = values
+			}
+			next.Set("$skip", strconv.Itoa(end))
+			page.NextLink = r.URL.Path + "?" + next.Encode()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
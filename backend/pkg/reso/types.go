@@ -0,0 +1,115 @@
+package reso
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Property is the subset of the RESO Data Dictionary Property resource used by Galactavista
+type Property struct {
+	ListingKey              string     `json:"ListingKey" xml:"ListingKey"`
+	ListingId               string     `json:"ListingId,omitempty" xml:"ListingId,omitempty"`
+	StandardStatus          string     `json:"StandardStatus,omitempty" xml:"StandardStatus,omitempty"`
+	PropertyType            string     `json:"PropertyType,omitempty" xml:"PropertyType,omitempty"`
+	PropertySubType         string     `json:"PropertySubType,omitempty" xml:"PropertySubType,omitempty"`
+	ListPrice               float64    `json:"ListPrice,omitempty" xml:"ListPrice,omitempty"`
+	UnparsedAddress         string     `json:"UnparsedAddress,omitempty" xml:"UnparsedAddress,omitempty"`
+	StreetNumber            string     `json:"StreetNumber,omitempty" xml:"StreetNumber,omitempty"`
+	StreetName              string     `json:"StreetName,omitempty" xml:"StreetName,omitempty"`
+	StreetSuffix            string     `json:"StreetSuffix,omitempty" xml:"StreetSuffix,omitempty"`
+	UnitNumber              string     `json:"UnitNumber,omitempty" xml:"UnitNumber,omitempty"`
+	City                    string     `json:"City,omitempty" xml:"City,omitempty"`
+	StateOrProvince         string     `json:"StateOrProvince,omitempty" xml:"StateOrProvince,omitempty"`
+	PostalCode              string     `json:"PostalCode,omitempty" xml:"PostalCode,omitempty"`
+	Country                 string     `json:"Country,omitempty" xml:"Country,omitempty"`
+	BedroomsTotal           int        `json:"BedroomsTotal,omitempty" xml:"BedroomsTotal,omitempty"`
+	BathroomsTotalInteger   int        `json:"BathroomsTotalInteger,omitempty" xml:"BathroomsTotalInteger,omitempty"`
+	BathroomsFull           int        `json:"BathroomsFull,omitempty" xml:"BathroomsFull,omitempty"`
+	BathroomsHalf           int        `json:"BathroomsHalf,omitempty" xml:"BathroomsHalf,omitempty"`
+	LivingArea              float64    `json:"LivingArea,omitempty" xml:"LivingArea,omitempty"`
+	YearBuilt               int        `json:"YearBuilt,omitempty" xml:"YearBuilt,omitempty"`
+	LotSizeAcres            float64    `json:"LotSizeAcres,omitempty" xml:"LotSizeAcres,omitempty"`
+	LotSizeSquareFeet       float64    `json:"LotSizeSquareFeet,omitempty" xml:"LotSizeSquareFeet,omitempty"`
+	Latitude                float64    `json:"Latitude,omitempty" xml:"Latitude,omitempty"`
+	Longitude               float64    `json:"Longitude,omitempty" xml:"Longitude,omitempty"`
+	PublicRemarks           string     `json:"PublicRemarks,omitempty" xml:"PublicRemarks,omitempty"`
+	InteriorFeatures        MultiValue `json:"InteriorFeatures,omitempty" xml:"InteriorFeatures,omitempty"`
+	ExteriorFeatures        MultiValue `json:"ExteriorFeatures,omitempty" xml:"ExteriorFeatures,omitempty"`
+	Appliances              MultiValue `json:"Appliances,omitempty" xml:"Appliances,omitempty"`
+	VirtualTourURLUnbranded string     `json:"VirtualTourURLUnbranded,omitempty" xml:"VirtualTourURLUnbranded,omitempty"`
+	ListAgentKey            string     `json:"ListAgentKey,omitempty" xml:"ListAgentKey,omitempty"`
+	ListAgentEmail          string     `json:"ListAgentEmail,omitempty" xml:"ListAgentEmail,omitempty"`
+	ListAgentFullName       string     `json:"ListAgentFullName,omitempty" xml:"ListAgentFullName,omitempty"`
+	ListingContractDate     string     `json:"ListingContractDate,omitempty" xml:"ListingContractDate,omitempty"`
+	ClosePrice              float64    `json:"ClosePrice,omitempty" xml:"ClosePrice,omitempty"`
+	CloseDate               string     `json:"CloseDate,omitempty" xml:"CloseDate,omitempty"`
+	OriginalEntryTimestamp  *time.Time `json:"OriginalEntryTimestamp,omitempty" xml:"OriginalEntryTimestamp,omitempty"`
+	ModificationTimestamp   time.Time  `json:"ModificationTimestamp" xml:"ModificationTimestamp"`
+	Media                   []Media    `json:"Media,omitempty" xml:"Media>MediaItem,omitempty"`
+}
+
+// Media is the subset of the RESO Data Dictionary Media resource used by Galactavista
+type Media struct {
+	MediaKey              string     `json:"MediaKey" xml:"MediaKey"`
+	MediaURL              string     `json:"MediaURL" xml:"MediaURL"`
+	MediaCategory         string     `json:"MediaCategory,omitempty" xml:"MediaCategory,omitempty"`
+	MimeType              string     `json:"MimeType,omitempty" xml:"MimeType,omitempty"`
+	Order                 int        `json:"Order,omitempty" xml:"Order,omitempty"`
+	ImageWidth            int        `json:"ImageWidth,omitempty" xml:"ImageWidth,omitempty"`
+	ImageHeight           int        `json:"ImageHeight,omitempty" xml:"ImageHeight,omitempty"`
+	ShortDescription      string     `json:"ShortDescription,omitempty" xml:"ShortDescription,omitempty"`
+	ModificationTimestamp *time.Time `json:"ModificationTimestamp,omitempty" xml:"ModificationTimestamp,omitempty"`
+}
+
+// DateFormat is the layout of RESO date fields such as CloseDate
+const DateFormat = "2006-01-02"
+
+// RESO standard status values
+const (
+	StatusActive              = "Active"
+	StatusActiveUnderContract = "Active Under Contract"
+	StatusComingSoon          = "Coming Soon"
+	StatusPending             = "Pending"
+	StatusClosed              = "Closed"
+	StatusCanceled            = "Canceled"
+	StatusExpired             = "Expired"
+	StatusWithdrawn           = "Withdrawn"
+	StatusHold                = "Hold"
+	StatusDelete              = "Delete"
+)
+
+// MultiValue is a RESO multi-select lookup. Servers encode it either as a
+// JSON array or as a comma separated string.
+type MultiValue []string
+
+// UnmarshalJSON accepts both array and comma separated string encodings
+func (m *MultiValue) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*m = list
+		return nil
+	}
+
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		return err
+	}
+
+	var values []string
+	for _, value := range strings.Split(joined, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	*m = values
+	return nil
+}
+
+// Page is a single OData response page
+type Page struct {
+	Context  string     `json:"@odata.context,omitempty"`
+	Count    *int       `json:"@odata.count,omitempty"`
+	NextLink string     `json:"@odata.nextLink,omitempty"`
+	Value    []Property `json:"value"`
+}
@@ -0,0 +1,158 @@
+package tabular
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Format represents a supported tabular file format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ContentType returns the MIME type for the format
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// ParseFormat parses a format name, defaulting to CSV when empty
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "csv":
+		return FormatCSV, nil
+	case "xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", name)
+	}
+}
+
+// FormatFromFileName detects the format from a file extension
+func FormatFromFileName(fileName string) (Format, error) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(fileName), "."))
+}
+
+// Read reads all rows from a CSV or XLSX source. The first row is returned
+// as-is, so callers decide whether it is a header. maxPartSize caps how
+// large each part of an XLSX archive may decompress to.
+func Read(r io.ReaderAt, size int64, format Format, maxPartSize int64) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case FormatXLSX:
+		return ReadXLSX(r, size, maxPartSize)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Write writes rows in the requested format
+func Write(w io.Writer, rows [][]string, format Format) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, rows)
+	case FormatXLSX:
+		return WriteXLSX(w, "Sheet1", rows)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// RowWriter writes rows one at a time, so large exports need not be held
+// in memory. Close must be called to complete the file.
+type RowWriter interface {
+	WriteRow(row []string) error
+	Close() error
+}
+
+// NewWriter creates a row writer for the requested format
+func NewWriter(w io.Writer, format Format) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, "Sheet1")
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// ReadCSV reads all rows from a CSV source
+func ReadCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	// Strip a UTF-8 byte order mark left by spreadsheet exports
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+
+	return rows, nil
+}
+
+// WriteCSV writes rows as CSV. Values that start like a formula are
+// prefixed with an apostrophe, see escapeFormula.
+func WriteCSV(w io.Writer, rows [][]string) error {
+	writer := &csvWriter{writer: csv.NewWriter(w)}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// csvWriter streams rows as CSV
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// WriteRow writes a row, neutralizing values a spreadsheet would run as a
+// formula
+func (w *csvWriter) WriteRow(row []string) error {
+	escaped := make([]string, len(row))
+	for i, value := range row {
+		escaped[i] = escapeFormula(value)
+	}
+	if err := w.writer.Write(escaped); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// escapeFormula prefixes a value that starts like a spreadsheet formula with
+// an apostrophe, so user-entered text such as "=HYPERLINK(...)" opens as
+// text. Plain numbers such as "-5" are left alone.
+func escapeFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
@@ -0,0 +1,388 @@
+package tabular
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Sheet limits of the XLSX format
+const (
+	xlsxMaxRows    = 1048576
+	xlsxMaxColumns = 16384
+)
+
+// ReadXLSX reads all rows from the first worksheet of an XLSX workbook. Each
+// part of the archive may decompress to at most maxPartSize bytes, and values
+// below the first row may not lie past its last used column, so a small
+// file cannot expand into a huge sheet.
+func ReadXLSX(r io.ReaderAt, size int64, maxPartSize int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files, maxPartSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if sharedStrings, err = readSharedStrings(f, maxPartSize); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("worksheet %s not found in workbook", sheetPath)
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(sheetFile, &sheet, maxPartSize); err != nil {
+		return nil, fmt.Errorf("failed to parse worksheet: %w", err)
+	}
+
+	var rows [][]string
+	headerColumns := 0
+	for r, row := range sheet.Rows {
+		// Row numbers are 1-based and may skip empty rows
+		rowIndex := len(rows)
+		if row.Index > 0 {
+			rowIndex = row.Index - 1
+		}
+		if rowIndex >= xlsxMaxRows {
+			return nil, fmt.Errorf("row %d is past the last worksheet row", rowIndex+1)
+		}
+		for len(rows) < rowIndex {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			colIndex := i
+			if cell.Ref != "" {
+				col, err := columnIndex(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+				colIndex = col
+			}
+			if colIndex >= xlsxMaxColumns {
+				return nil, fmt.Errorf("row %d has more cells than the worksheet has columns", rowIndex+1)
+			}
+
+			value, err := cell.value(sharedStrings)
+			if err != nil {
+				return nil, err
+			}
+			// Empty cells, such as formatted ones past the data, are left
+			// out so they do not widen the row
+			if value == "" {
+				continue
+			}
+			if r > 0 && colIndex >= headerColumns {
+				return nil, fmt.Errorf("cell %s%d is past the last header column", columnName(colIndex), rowIndex+1)
+			}
+			for len(values) < colIndex {
+				values = append(values, "")
+			}
+			values = append(values, value)
+		}
+		if r == 0 {
+			headerColumns = len(values)
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// WriteXLSX writes rows to a single-sheet XLSX workbook using inline strings
+func WriteXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	writer, err := newXLSXWriter(w, sheetName)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// xlsxWriter streams rows into the worksheet of a single-sheet workbook
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+// newXLSXWriter writes the workbook parts and opens the worksheet
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapeXML(sheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write XLSX part %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, fmt.Errorf("failed to write XLSX part %s: %w", part.name, err)
+		}
+	}
+
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write worksheet: %w", err)
+	}
+
+	writer := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(sheet)}
+	writer.sheet.WriteString(xml.Header)
+	writer.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return writer, nil
+}
+
+func (w *xlsxWriter) WriteRow(row []string) error {
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for j, value := range row {
+		ref := columnName(j) + strconv.Itoa(w.rows)
+		if isNumeric(value) {
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, value)
+		} else {
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(value))
+		}
+	}
+	if _, err := w.sheet.WriteString(`</row>`); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+	return nil
+}
+
+func (w *xlsxWriter) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return fmt.Errorf("failed to write worksheet: %w", err)
+	}
+	return w.archive.Close()
+}
+
+type xlsxWorksheet struct {
+	Rows []xlsxRow `xml:"sheetData>row"`
+}
+
+type xlsxRow struct {
+	Index int        `xml:"r,attr"`
+	Cells []xlsxCell `xml:"c"`
+}
+
+type xlsxCell struct {
+	Ref    string    `xml:"r,attr"`
+	Type   string    `xml:"t,attr"`
+	Value  string    `xml:"v"`
+	Inline xlsxRText `xml:"is"`
+}
+
+// xlsxRText is a rich text run container used by shared and inline strings
+type xlsxRText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+func (c xlsxCell) value(sharedStrings []string) (string, error) {
+	switch c.Type {
+	case "s":
+		idx, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		if err != nil || idx < 0 || idx >= len(sharedStrings) {
+			return "", fmt.Errorf("invalid shared string reference in cell %s", c.Ref)
+		}
+		return sharedStrings[idx], nil
+	case "inlineStr":
+		return c.Inline.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		return c.Value, nil
+	}
+}
+
+func firstSheetPath(files map[string]*zip.File, maxPartSize int64) (string, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("workbook.xml not found in XLSX archive")
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(workbookFile, &workbook, maxPartSize); err != nil {
+		return "", fmt.Errorf("failed to parse workbook: %w", err)
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("workbook contains no sheets")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(relsFile, &rels, maxPartSize); err != nil {
+		return "", fmt.Errorf("failed to parse workbook relationships: %w", err)
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", fmt.Errorf("worksheet relationship %s not found", workbook.Sheets[0].RelID)
+}
+
+func readSharedStrings(f *zip.File, maxPartSize int64) ([]string, error) {
+	var sst struct {
+		Items []xlsxRText `xml:"si"`
+	}
+	if err := decodeZipXML(f, &sst, maxPartSize); err != nil {
+		return nil, fmt.Errorf("failed to parse shared strings: %w", err)
+	}
+
+	values := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		values[i] = item.String()
+	}
+	return values, nil
+}
+
+// decodeZipXML decodes an XML part of the archive, failing once it has
+// decompressed more than maxPartSize bytes
+func decodeZipXML(f *zip.File, v interface{}, maxPartSize int64) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Read one byte past the cap to tell a part that fills it exactly from
+	// one that is larger
+	limited := io.LimitReader(rc, maxPartSize+1).(*io.LimitedReader)
+	err = xml.NewDecoder(limited).Decode(v)
+	if limited.N == 0 {
+		return fmt.Errorf("%s is larger than %d bytes", f.Name, maxPartSize)
+	}
+	return err
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero-based column index
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch >= 'A' && ch <= 'Z' {
+			col = col*26 + int(ch-'A'+1)
+			if col > xlsxMaxColumns {
+				return 0, fmt.Errorf("cell %s is past the last worksheet column", ref)
+			}
+			n++
+			continue
+		}
+		break
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+// columnName converts a zero-based column index to its spreadsheet letters
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func isNumeric(value string) bool {
+	if value == "" || strings.TrimSpace(value) != value {
+		return false
+	}
+	// Keep values like ZIP codes with leading zeros as text
+	if len(value) > 1 && value[0] == '0' && value[1] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && !strings.ContainsAny(value, "eEinIN")
+}
+
+func escapeXML(value string) string {
+	var b strings.Builder
+	if err := xml.EscapeText(&b, []byte(value)); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
@@ -96,11 +96,13 @@ export const useAuth = () => {
     });
   }, []);
 
+  const profileVersion = authState.user?.version ?? 1;
+
   const updateProfile = useCallback(async (profileData: Partial<User>) => {
     try {
       setAuthState(prev => ({ ...prev, isLoading: true }));
       
-      const updatedUser = await apiClient.updateProfile(profileData);
+      const updatedUser = await apiClient.updateProfile(profileData, profileVersion);
       
       // Update localStorage
       localStorage.setItem('user', JSON.stringify(updatedUser));
@@ -116,7 +118,7 @@ export const useAuth = () => {
       setAuthState(prev => ({ ...prev, isLoading: false }));
       throw error;
     }
-  }, []);
+  }, [profileVersion]);
 
   const refreshProfile = useCallback(async () => {
     try {
@@ -96,11 +96,11 @@ export const useProperties = (initialParams?: PropertySearchRequest) => {
     }
   }, []);
 
-  const updateProperty = useCallback(async (id: number, propertyData: any) => {
+  const updateProperty = useCallback(async (id: number, propertyData: any, version: number) => {
     try {
       setState(prev => ({ ...prev, loading: true, error: null }));
       
-      const updatedProperty = await apiClient.updateProperty(id, propertyData);
+      const updatedProperty = await apiClient.updateProperty(id, propertyData, version);
       
       setState(prev => ({
         ...prev,
@@ -120,11 +120,11 @@ export const useProperties = (initialParams?: PropertySearchRequest) => {
     }
   }, []);
 
-  const deleteProperty = useCallback(async (id: number) => {
+  const deleteProperty = useCallback(async (id: number, version: number) => {
     try {
       setState(prev => ({ ...prev, loading: true, error: null }));
       
-      await apiClient.deleteProperty(id);
+      await apiClient.deleteProperty(id, version);
       
       setState(prev => ({
         ...prev,
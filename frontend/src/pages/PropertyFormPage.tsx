@@ -82,6 +82,7 @@ const PropertyFormPage: React.FC = () => {
     features: [],
   });
 
+  const [version, setVersion] = useState(1);
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
   const [success, setSuccess] = useState(false);
@@ -113,6 +114,7 @@ const PropertyFormPage: React.FC = () => {
     try {
       setLoading(true);
       const property = await apiClient.getProperty(parseInt(propertyId));
+      setVersion(property.version);
       setFormData({
         title: property.title || '',
         description: property.description || '',
@@ -163,7 +165,7 @@ const PropertyFormPage: React.FC = () => {
           lot_size: formData.lot_size,
           features: formData.features.length > 0 ? formData.features : undefined,
         };
-        await apiClient.updateProperty(parseInt(id), updateData);
+        await apiClient.updateProperty(parseInt(id), updateData, version);
       } else {
         const createData: PropertyCreateRequest = {
           title: formData.title,
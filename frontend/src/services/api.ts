@@ -29,8 +29,8 @@ export class WebAPIService {
     return apiClient.getProfile();
   }
 
-  static async updateProfile(profileData: Partial<User>) {
-    return apiClient.updateProfile(profileData);
+  static async updateProfile(profileData: Partial<User>, version: number) {
+    return apiClient.updateProfile(profileData, version);
   }
 
   // Property methods
@@ -46,12 +46,12 @@ export class WebAPIService {
     return apiClient.createProperty(propertyData);
   }
 
-  static async updateProperty(id: number, propertyData: PropertyUpdateRequest) {
-    return apiClient.updateProperty(id, propertyData);
+  static async updateProperty(id: number, propertyData: PropertyUpdateRequest, version: number) {
+    return apiClient.updateProperty(id, propertyData, version);
   }
 
-  static async deleteProperty(id: number) {
-    return apiClient.deleteProperty(id);
+  static async deleteProperty(id: number, version: number) {
+    return apiClient.deleteProperty(id, version);
   }
 
   static async getPropertiesByAgent(params?: { page?: number; page_size?: number }) {
@@ -8,6 +8,9 @@ export interface User {
   phone?: string;
   avatar?: string;
   is_active: boolean;
+  brokerage_id?: number;
+  is_broker?: boolean;
+  version: number;
   created_at: string;
   updated_at: string;
 }
@@ -46,21 +49,814 @@ export interface Property {
   square_feet?: number;
   year_built?: number;
   lot_size?: number;
+  commercial?: CommercialDetails;
+  land?: LandDetails;
+  latitude?: number | null;
+  longitude?: number | null;
+  geocode_accuracy?: GeocodeAccuracy;
   features: string[];
+  attributes: AttributeValue[];
   images: string[];
   vr_model_url?: string;
+  development_id?: number;
+  unit_number?: string;
+  development?: DevelopmentSummary;
+  rollup?: DevelopmentRollup;
   agent: User;
+  sold_price?: number;
+  sold_at?: string;
+  listing_type: ListingType;
+  monthly_rent?: number;
+  security_deposit?: number;
+  lease_term_months?: number;
+  pet_policy?: PetPolicy;
+  available_from?: string;
+  quality_score: number;
+  quality?: ListingQuality;
+  moderation_status: ModerationStatus;
+  compliance: ListingCompliance;
+  version: number;
+  open_houses: OpenHouse[];
   created_at: string;
   updated_at: string;
 }
 
+export interface OpenHouse {
+  id: number;
+  property_id: number;
+  starts_at: string;
+  ends_at: string;
+  time_zone: string;
+  local_start: string;
+  local_end: string;
+  capacity: number;
+  notes?: string;
+  confirmed_count: number;
+  waitlist_count: number;
+  spots_remaining: number | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export type RSVPStatus = 'confirmed' | 'waitlisted' | 'cancelled';
+
+export type ShowingStatus = 'requested' | 'confirmed' | 'rescheduled' | 'cancelled';
+
+export interface ShowingSlot {
+  starts_at: string;
+  ends_at: string;
+  local_start: string;
+}
+
+export interface Showing {
+  id: number;
+  property_id: number;
+  property_title: string;
+  address: string;
+  agent_id: number;
+  buyer: User;
+  starts_at: string;
+  ends_at: string;
+  local_start: string;
+  time_zone: string;
+  status: ShowingStatus;
+  message: string;
+  cancel_reason?: string;
+  cancelled_by?: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export type LeadStage = 'new' | 'contacted' | 'qualified' | 'showing' | 'offer' | 'won' | 'lost';
+
+export interface LeadNote {
+  id: number;
+  lead_id: number;
+  author_id: number;
+  author: User;
+  body: string;
+  created_at: string;
+}
+
+export interface Lead {
+  id: number;
+  property_id: number;
+  property_title: string;
+  agent_id: number;
+  agent_name: string;
+  brokerage_id?: number;
+  buyer_id?: number;
+  name: string;
+  email: string;
+  phone: string;
+  message: string;
+  source: 'inquiry';
+  stage: LeadStage;
+  notes?: LeadNote[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface InquiryRequest {
+  name?: string;
+  email?: string;
+  phone?: string;
+  message: string;
+}
+
+export interface MessageAttachment {
+  file_name: string;
+  file_url: string;
+  file_type: string;
+  file_size: number;
+}
+
+export interface Message {
+  id: number;
+  conversation_id: number;
+  sender_id: number;
+  body: string;
+  attachments: MessageAttachment[];
+  read: boolean;
+  flagged?: boolean;
+  created_at: string;
+}
+
+export interface Conversation {
+  id: number;
+  property_id: number;
+  property_title: string;
+  participant: User;
+  last_message?: Message;
+  unread_count: number;
+  last_message_at: string | null;
+  created_at: string;
+}
+
+export interface MessageEvent {
+  type: 'message' | 'read';
+  conversation_id: number;
+  message?: Message;
+  reader_id?: number;
+  last_read_id?: number;
+}
+
+export type OfferStatus = 'submitted' | 'countered' | 'accepted' | 'rejected' | 'withdrawn' | 'expired';
+
+export type Contingency = 'inspection' | 'appraisal' | 'financing' | 'sale_of_home';
+
+export interface Offer {
+  id: number;
+  property_id: number;
+  property_title: string;
+  buyer_id: number;
+  buyer_name: string;
+  agent_id: number;
+  parent_id?: number;
+  proposed_by: number;
+  amount: number;
+  earnest_money: number;
+  contingencies: Contingency[];
+  closing_date: string | null;
+  expires_at: string;
+  message: string;
+  status: OfferStatus;
+  transaction_id?: number;
+  history?: Offer[];
+  created_at: string;
+  updated_at: string;
+}
+
+export type TransactionStatus = 'open' | 'closed' | 'cancelled';
+
+export interface TransactionMilestone {
+  id: number;
+  transaction_id: number;
+  type: 'inspection' | 'appraisal' | 'financing' | 'closing' | 'custom';
+  title: string;
+  due_date: string | null;
+  completed_at: string | null;
+  completed_by: number | null;
+  sort_order: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface Transaction {
+  id: number;
+  property_id: number;
+  property_title: string;
+  address: string;
+  offer_id: number;
+  buyer_id: number;
+  buyer_name: string;
+  agent_id: number;
+  price: number;
+  closing_date: string | null;
+  status: TransactionStatus;
+  milestones: TransactionMilestone[];
+  closed_at: string | null;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ComparedProperty {
+  id: number;
+  title: string;
+  address: string;
+  city: string;
+  state: string;
+  zip_code: string;
+  property_type: PropertyType;
+  status: PropertyStatus;
+  listing_type: ListingType;
+  price: number;
+  monthly_rent: number | null;
+  bedrooms: number;
+  bathrooms: number;
+  square_feet: number;
+  year_built: number;
+  lot_size: number;
+  price_per_sqft: number | null;
+  age: number | null;
+  image: string;
+  vr_model_url: string;
+  features: string[];
+}
+
+export interface ComparisonRow {
+  field: string;
+  values: (string | number | null)[];
+  differs: boolean;
+}
+
+export interface FeatureComparison {
+  feature: string;
+  present: boolean[];
+  differs: boolean;
+}
+
+export interface PropertyComparison {
+  properties: ComparedProperty[];
+  rows: ComparisonRow[];
+  features: FeatureComparison[];
+  common_features: string[];
+  differing_rows: string[];
+}
+
+export interface ValuationAdjustments {
+  per_square_foot: number;
+  per_bedroom: number;
+  per_bathroom: number;
+  per_year_built: number;
+  per_lot_acre: number;
+  monthly_appreciation: number;
+}
+
+export interface ComparableSale {
+  property_id: number;
+  title: string;
+  address: string;
+  city: string;
+  sold_price: number;
+  sold_at: string;
+  bedrooms: number;
+  bathrooms: number;
+  square_feet: number;
+  year_built: number;
+  lot_size: number;
+  distance_miles: number | null;
+  adjustments: { reason: string; amount: number }[];
+  adjusted_price: number;
+  weight: number;
+}
+
+export interface Valuation {
+  property_id?: number;
+  estimate: {
+    value: number;
+    low: number;
+    high: number;
+    price_per_sqft: number | null;
+    confidence: 'low' | 'medium' | 'high';
+  } | null;
+  comps: ComparableSale[];
+  matched_by: 'distance' | 'zip_code' | 'city';
+  options: { radius_miles: number; months: number; max_comps: number };
+  adjustments: ValuationAdjustments;
+}
+
+export interface PaymentBreakdown {
+  principal_and_interest: number;
+  property_tax: number;
+  insurance: number;
+  hoa: number;
+  pmi: number;
+  total: number;
+}
+
+export interface AmortizationEntry {
+  period: number;
+  payment: number;
+  principal: number;
+  interest: number;
+  pmi: number;
+  balance: number;
+}
+
+export interface MortgageCalculation {
+  property_id?: number;
+  price: number;
+  down_payment: number;
+  down_payment_percent: number;
+  loan_amount: number;
+  interest_rate: number;
+  term_years: number;
+  monthly: PaymentBreakdown;
+  pmi_months: number;
+  total_interest: number;
+  total_of_payments: number;
+  schedule_type: 'none' | 'monthly' | 'yearly';
+  schedule?: AmortizationEntry[];
+}
+
+export interface Affordability {
+  max_price: number;
+  max_loan_amount: number;
+  max_monthly_payment: number;
+  limited_by: 'front_end_ratio' | 'back_end_ratio';
+  monthly: PaymentBreakdown;
+  listings: PaginationResponse<Property>;
+}
+
+export interface Development {
+  id: number;
+  name: string;
+  description: string;
+  address: string;
+  city: string;
+  state: string;
+  zip_code: string;
+  country: string;
+  latitude: number | null;
+  longitude: number | null;
+  year_built: number;
+  amenities: string[];
+  images: string[];
+  vr_model_url: string;
+  agent_id: number;
+  unit_count: number;
+  available_units: number;
+  units?: DevelopmentUnit[];
+  price_table?: UnitPriceRow[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface DevelopmentSummary {
+  id: number;
+  name: string;
+  amenities: string[];
+  images: string[];
+  vr_model_url: string;
+}
+
+export interface DevelopmentRollup {
+  matching_units: number;
+  min_price: number | null;
+  max_price: number | null;
+  min_rent: number | null;
+  max_rent: number | null;
+  min_bedrooms: number;
+  max_bedrooms: number;
+}
+
+export interface DevelopmentUnit {
+  id: number;
+  unit_number: string;
+  title: string;
+  property_type: PropertyType;
+  listing_type: ListingType;
+  status: PropertyStatus;
+  bedrooms: number;
+  bathrooms: number;
+  square_feet: number;
+  price: number;
+  monthly_rent: number | null;
+  available_from: string | null;
+}
+
+export interface UnitPriceRow {
+  listing_type: ListingType;
+  bedrooms: number;
+  units: number;
+  available_units: number;
+  min_price: number | null;
+  max_price: number | null;
+  min_square_feet: number;
+  max_square_feet: number;
+}
+
+export interface DevelopmentCreateRequest {
+  name: string;
+  description?: string;
+  address: string;
+  city: string;
+  state: string;
+  zip_code: string;
+  country?: string;
+  latitude?: number;
+  longitude?: number;
+  year_built?: number;
+  amenities?: string[];
+  images?: string[];
+  vr_model_url?: string;
+}
+
+export type DevelopmentUpdateRequest = Partial<DevelopmentCreateRequest>;
+
+export interface DevelopmentUnitCreateRequest {
+  unit_number: string;
+  title: string;
+  description?: string;
+  price?: number;
+  property_type: PropertyType;
+  listing_type?: ListingType;
+  bedrooms?: number;
+  bathrooms?: number;
+  square_feet?: number;
+  features?: string[];
+  images?: string[];
+  commercial?: CommercialDetails;
+  monthly_rent?: number;
+  security_deposit?: number;
+  lease_term_months?: number;
+  pet_policy?: PetPolicy;
+  available_from?: string;
+}
+
+export type AttributeType = 'bool' | 'int' | 'enum' | 'text';
+
+export interface Attribute {
+  id: number;
+  key: string;
+  label: string;
+  category: string;
+  type: AttributeType;
+  options: string[] | null;
+  property_types: PropertyType[] | null;
+  aliases: string[] | null;
+  sort_order: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface AttributeRequest {
+  key: string;
+  label: string;
+  category: string;
+  type: AttributeType;
+  options?: string[];
+  property_types?: PropertyType[];
+  aliases?: string[];
+  sort_order?: number;
+}
+
+export interface AttributeValue {
+  key: string;
+  label: string;
+  category: string;
+  type: AttributeType;
+  value: boolean | number | string;
+}
+
+export interface UnmatchedFeature {
+  feature: string;
+  listings: number;
+}
+
+export interface FeatureMigrationResponse {
+  properties_scanned: number;
+  properties_updated: number;
+  features_migrated: number;
+  unmatched: UnmatchedFeature[];
+}
+
+export type CommercialLeaseType = 'gross' | 'modified_gross' | 'net' | 'double_net' | 'triple_net' | 'absolute_net';
+export type Utility = 'water' | 'well' | 'sewer' | 'septic' | 'electricity' | 'gas' | 'internet';
+export type Topography = 'flat' | 'gentle_slope' | 'rolling' | 'steep' | 'mixed';
+
+export interface CommercialDetails {
+  zoning: string;
+  usable_square_feet?: number | null;
+  cap_rate?: number | null;
+  net_operating_income?: number | null;
+  lease_type?: CommercialLeaseType | '';
+}
+
+export interface LandDetails {
+  acreage: number | null;
+  utilities?: Utility[] | null;
+  topography?: Topography | '';
+  zoning: string;
+}
+
+export type DuplicateReason = 'same_address' | 'similar_address' | 'same_location';
+export type DuplicateStatus = 'pending' | 'dismissed' | 'merged';
+
+export interface DuplicateListing {
+  id: number;
+  title: string;
+  address: string;
+  unit_number: string;
+  city: string;
+  state: string;
+  zip_code: string;
+  address_key: string;
+  price: number;
+  status: PropertyStatus;
+  agent_id: number;
+  image_count: number;
+  created_at: string;
+}
+
+export interface DuplicateCandidate {
+  id: number;
+  reason: DuplicateReason;
+  score: number;
+  status: DuplicateStatus;
+  property: DuplicateListing;
+  duplicate_of: DuplicateListing;
+  reviewed_by_id: number | null;
+  reviewed_at: string | null;
+  created_at: string;
+}
+
+export interface DuplicateMergeRequest {
+  keep_id?: number;
+}
+
+export interface DuplicateScanResult {
+  scanned: number;
+  normalized: number;
+  flagged: number;
+}
+
+export type GeocodeAccuracy = 'rooftop' | 'parcel' | 'interpolated' | 'approximate';
+
+export interface QualityCheck {
+  key: string;
+  label: string;
+  points: number;
+  max_points: number;
+  complete: boolean;
+  suggestion?: string;
+}
+
+export interface ListingQuality {
+  property_id: number;
+  score: number;
+  checklist: QualityCheck[];
+  suggestions: string[];
+}
+
+export interface QualityRefreshResult {
+  scanned: number;
+  updated: number;
+}
+
+export type ModerationStatus = 'approved' | 'pending' | 'rejected';
+export type ModerationItemType = 'property' | 'media';
+
+export interface ModerationFlag {
+  rule: string;
+  reason: string;
+}
+
+export interface ModerationItem {
+  id: number;
+  item_type: ModerationItemType;
+  status: ModerationStatus;
+  flags: ModerationFlag[];
+  property_id: number;
+  property_title: string;
+  description?: string;
+  agent_id: number;
+  media_file_id?: number;
+  media_url?: string;
+  reported_by_id?: number;
+  review_reason?: string;
+  reviewed_by_id?: number | null;
+  reviewed_at?: string | null;
+  created_at: string;
+}
+
+export interface ModerationReviewRequest {
+  reason?: string;
+}
+
+export interface ModerationReportRequest {
+  media_file_id?: number;
+  reason: string;
+}
+
+export type ComplianceStatus = 'clear' | 'blocked' | 'overridden';
+export type ComplianceSeverity = 'advisory' | 'severe';
+
+export interface ComplianceWarning {
+  rule_id: string;
+  category: string;
+  severity: ComplianceSeverity;
+  phrase: string;
+  message: string;
+  alternatives: string[];
+  // Character offsets of the phrase in the description, end exclusive
+  start: number;
+  end: number;
+}
+
+export interface ListingCompliance {
+  status: ComplianceStatus;
+  override_by_id?: number;
+  override_reason?: string;
+  overridden_at?: string;
+  warnings?: ComplianceWarning[];
+}
+
+export interface ComplianceLintRequest {
+  description: string;
+}
+
+export interface ComplianceLintResponse {
+  warnings: ComplianceWarning[];
+  blocked: boolean;
+}
+
+export interface ComplianceOverrideRequest {
+  reason: string;
+}
+
+export interface BlockedListing {
+  id: number;
+  title: string;
+  agent_id: number;
+  agent_name: string;
+  warnings: ComplianceWarning[];
+  updated_at: string;
+}
+
+export type ListingEventType = 'view' | 'media_view' | 'tour_open' | 'inquiry';
+export type AnalyticsInterval = 'day' | 'week' | 'month';
+
+export interface ListingEventRequest {
+  type: 'media_view' | 'tour_open';
+  media_file_id?: number;
+  vr_tour_id?: number;
+}
+
+export interface AnalyticsRequest {
+  from?: string;
+  to?: string;
+  interval?: AnalyticsInterval;
+}
+
+export interface ListingEventTotals {
+  views: number;
+  unique_visitors: number;
+  media_views: number;
+  tour_opens: number;
+  inquiries: number;
+}
+
+export interface AnalyticsPoint extends ListingEventTotals {
+  period: string;
+}
+
+export interface FunnelStage {
+  stage: ListingEventType;
+  visitors: number;
+  rate: number;
+}
+
+export interface ListingComparison {
+  similar_listings: number;
+  average: ListingEventTotals;
+  average_inquiry_rate: number;
+  views_index: number | null;
+  inquiries_index: number | null;
+}
+
+export interface ListingAnalytics {
+  property_id: number;
+  from: string;
+  to: string;
+  interval: AnalyticsInterval;
+  totals: ListingEventTotals;
+  series: AnalyticsPoint[];
+  funnel: FunnelStage[];
+  comparison: ListingComparison;
+}
+
+export interface ListingPerformance {
+  property_id: number;
+  title: string;
+  totals: ListingEventTotals;
+  inquiry_rate: number;
+}
+
+export interface AgentAnalytics {
+  agent_id: number;
+  from: string;
+  to: string;
+  interval: AnalyticsInterval;
+  totals: ListingEventTotals;
+  series: AnalyticsPoint[];
+  funnel: FunnelStage[];
+  listings: ListingPerformance[];
+}
+
+export type ReportMetric =
+  | 'registrations'
+  | 'listings_created'
+  | 'listings_sold'
+  | 'media_storage'
+  | 'vr_tours_published'
+  | 'active_agents';
+export type ReportFrequency = 'daily' | 'weekly' | 'monthly';
+
+export interface ReportRequest extends AnalyticsRequest {
+  group_by?: string;
+  format?: 'json' | 'csv' | 'xlsx';
+}
+
+export interface ReportRow {
+  period: string;
+  group?: string;
+  count: number;
+  value?: number;
+}
+
+export interface ReportTotal {
+  group?: string;
+  count: number;
+  value?: number;
+}
+
+export interface Report {
+  metric: ReportMetric;
+  label: string;
+  value_label?: string;
+  from: string;
+  to: string;
+  interval: AnalyticsInterval;
+  group_by?: string;
+  rows: ReportRow[];
+  totals: ReportTotal[];
+}
+
+export interface ReportMetricInfo {
+  metric: ReportMetric;
+  label: string;
+  description: string;
+  value_label?: string;
+  dimensions: string[];
+}
+
+export interface ReportSchedule {
+  id: number;
+  name: string;
+  metric: ReportMetric;
+  group_by: string;
+  frequency: ReportFrequency;
+  recipients: string[];
+  is_active: boolean;
+  next_run_at: string;
+  last_run_at?: string;
+  last_run_status: string;
+  last_run_error: string;
+  created_by_id: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ReportScheduleRequest {
+  name: string;
+  metric: ReportMetric;
+  group_by?: string;
+  frequency: ReportFrequency;
+  recipients: string[];
+  is_active?: boolean;
+}
+
 export type PropertyType = 'house' | 'condo' | 'townhouse' | 'apartment' | 'land' | 'commercial';
 export type PropertyStatus = 'available' | 'sold' | 'pending' | 'rented';
+export type ListingType = 'sale' | 'rent';
+export type PetPolicy = 'none' | 'cats' | 'dogs' | 'cats_and_dogs' | 'negotiable';
 
 export interface PropertyCreateRequest {
   title: string;
   description?: string;
-  price: number;
+  price?: number;
   address: string;
   city: string;
   state: string;
@@ -74,6 +870,17 @@ export interface PropertyCreateRequest {
   lot_size?: number;
   features?: string[];
   images?: string[];
+  unit_number?: string;
+  geocode_accuracy?: GeocodeAccuracy;
+  attributes?: Record<string, boolean | number | string>;
+  commercial?: CommercialDetails;
+  land?: LandDetails;
+  listing_type?: ListingType;
+  monthly_rent?: number;
+  security_deposit?: number;
+  lease_term_months?: number;
+  pet_policy?: PetPolicy;
+  available_from?: string;
 }
 
 export interface PropertyUpdateRequest {
@@ -95,6 +902,17 @@ export interface PropertyUpdateRequest {
   features?: string[];
   images?: string[];
   vr_model_url?: string;
+  unit_number?: string;
+  geocode_accuracy?: GeocodeAccuracy;
+  attributes?: Record<string, boolean | number | string>;
+  commercial?: CommercialDetails;
+  land?: LandDetails;
+  listing_type?: ListingType;
+  monthly_rent?: number;
+  security_deposit?: number;
+  lease_term_months?: number;
+  pet_policy?: PetPolicy;
+  available_from?: string;
 }
 
 // Search and pagination types
@@ -113,6 +931,32 @@ export interface PropertySearchRequest extends PaginationRequest {
   city?: string;
   state?: string;
   status?: PropertyStatus;
+  listing_type?: ListingType;
+  min_rent?: number;
+  max_rent?: number;
+  pets?: 'cats' | 'dogs';
+  available_by?: string;
+  max_lease_months?: number;
+  development_id?: number;
+  rollup_developments?: boolean;
+  zoning?: string;
+  min_usable_square_feet?: number;
+  min_cap_rate?: number;
+  max_cap_rate?: number;
+  min_net_operating_income?: number;
+  commercial_lease_type?: CommercialLeaseType;
+  min_acreage?: number;
+  max_acreage?: number;
+  utilities?: string;
+  topography?: Topography;
+  // Sent as attr[key]=value query parameters
+  attr?: Record<string, string>;
+  has_open_house?: boolean;
+  open_house_from?: string;
+  open_house_to?: string;
+  open_house_weekend?: boolean;
+  open_house_tz?: string;
+  sort?: 'relevance' | 'newest' | 'price_asc' | 'price_desc';
 }
 
 export interface PaginationResponse<T> {
@@ -158,15 +1002,22 @@ export interface VRExperienceCreateRequest {
 export interface DashboardStats {
   totalProperties: number;
   availableProperties: number;
+  pendingProperties: number;
   soldProperties: number;
+  rentedProperties: number;
   totalViews: number;
   totalInquiries: number;
+  newLeads: number;
+  upcomingShowings: number;
+  averageDaysOnMarket: number;
+  averageDaysToSell: number;
+  refreshedAt: string;
 }
 
 export interface AgentDashboardData {
   stats: DashboardStats;
   recentProperties: Property[];
-  recentInquiries: any[]; // TODO: Define inquiry type
+  recentInquiries: Lead[];
 }
 
 // Mobile-specific types
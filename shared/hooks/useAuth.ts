@@ -101,11 +101,13 @@ export const useAuth = () => {
     });
   }, []);
 
+  const profileVersion = authState.user?.version ?? 1;
+
   const updateProfile = useCallback(async (profileData: Partial<User>) => {
     try {
       setAuthState(prev => ({ ...prev, isLoading: true }));
       
-      const updatedUser = await apiClient.updateProfile(profileData);
+      const updatedUser = await apiClient.updateProfile(profileData, profileVersion);
       
       // Update AsyncStorage
       await AsyncStorage.setItem('user', JSON.stringify(updatedUser));
@@ -121,7 +123,7 @@ export const useAuth = () => {
       setAuthState(prev => ({ ...prev, isLoading: false }));
       throw error;
     }
-  }, []);
+  }, [profileVersion]);
 
   const refreshProfile = useCallback(async () => {
     try {
@@ -8,6 +8,11 @@ export const getAPIBaseURL = () => {
   return 'http://localhost:8080/api/v1';
 };
 
+// ifMatch builds the precondition header for updating a versioned resource
+const ifMatch = (version: number): Record<string, string> => ({
+  'If-Match': `"${version}"`,
+});
+
 // Generic API client for both web and mobile
 export class APIClient {
   private baseURL: string;
@@ -51,6 +56,10 @@ export class APIClient {
         this.clearToken();
         throw new Error('Authentication failed');
       }
+      if (response.status === 412) {
+        // Resource changed since it was loaded
+        throw new Error('This record was modified by someone else. Reload it and try again.');
+      }
       throw new Error(`HTTP error! status: ${response.status}`);
     }
 
@@ -84,9 +93,10 @@ export class APIClient {
     return response.data!;
   }
 
-  async updateProfile(profileData: Partial<User>): Promise<User> {
+  async updateProfile(profileData: Partial<User>, version: number): Promise<User> {
     const response = await this.request<User>('/auth/profile', {
       method: 'PUT',
+      headers: ifMatch(version),
       body: JSON.stringify(profileData),
     });
     return response.data!;
@@ -122,17 +132,19 @@ export class APIClient {
     return response.data!;
   }
 
-  async updateProperty(id: number, propertyData: PropertyUpdateRequest): Promise<Property> {
+  async updateProperty(id: number, propertyData: PropertyUpdateRequest, version: number): Promise<Property> {
     const response = await this.request<Property>(`/properties/${id}`, {
       method: 'PUT',
+      headers: ifMatch(version),
       body: JSON.stringify(propertyData),
     });
     return response.data!;
   }
 
-  async deleteProperty(id: number): Promise<void> {
+  async deleteProperty(id: number, version: number): Promise<void> {
     await this.request(`/properties/${id}`, {
       method: 'DELETE',
+      headers: ifMatch(version),
     });
   }
 